@@ -6,6 +6,9 @@ type ADConfig struct {
 	ServiceAccountPassword string   `json:"serviceAccountPassword,omitempty" yaml:"serviceAccountPassword,omitempty"`
 	ServiceAccountUsername string   `json:"serviceAccountUsername,omitempty" yaml:"serviceAccountUsername,omitempty"`
 	UserSearchBase         string   `json:"userSearchBase,omitempty" yaml:"userSearchBase,omitempty"`
+	TestGroup              string   `json:"testGroup,omitempty" yaml:"testGroup,omitempty"`
 	TestUsername           string   `json:"testUsername,omitempty" yaml:"testUsername,omitempty"`
 	TestPassword           string   `json:"testPassword,omitempty" yaml:"testPassword,omitempty"`
+	DeniedUsername         string   `json:"deniedUsername,omitempty" yaml:"deniedUsername,omitempty"`
+	DeniedPassword         string   `json:"deniedPassword,omitempty" yaml:"deniedPassword,omitempty"`
 }
@@ -0,0 +1,14 @@
+package authproviders
+
+type FreeIPAConfig struct {
+	Port                            int64    `json:"port,omitempty" yaml:"port,omitempty"`
+	Servers                         []string `json:"servers,omitempty" yaml:"servers,omitempty"`
+	ServiceAccountDistinguishedName string   `json:"serviceAccountDistinguishedName,omitempty" yaml:"serviceAccountDistinguishedName,omitempty"`
+	ServiceAccountPassword          string   `json:"serviceAccountPassword,omitempty" yaml:"serviceAccountPassword,omitempty"`
+	UserSearchBase                  string   `json:"userSearchBase,omitempty" yaml:"userSearchBase,omitempty"`
+	TestGroup                       string   `json:"testGroup,omitempty" yaml:"testGroup,omitempty"`
+	TestUsername                    string   `json:"testUsername,omitempty" yaml:"testUsername,omitempty"`
+	TestPassword                    string   `json:"testPassword,omitempty" yaml:"testPassword,omitempty"`
+	DeniedUsername                  string   `json:"deniedUsername,omitempty" yaml:"deniedUsername,omitempty"`
+	DeniedPassword                  string   `json:"deniedPassword,omitempty" yaml:"deniedPassword,omitempty"`
+}
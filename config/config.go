@@ -7,6 +7,7 @@ import (
 	"runtime"
 
 	"github.com/imdario/mergo"
+	provisioningv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
 	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
 	"github.com/rancher/shepherd/clients/rancher"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
@@ -24,6 +25,7 @@ import (
 
 type TestClientName string
 type Role string
+type RBACScope string
 type PSACT string
 
 const (
@@ -33,8 +35,17 @@ const (
 	AdminClientName    TestClientName = "Admin User"
 	StandardClientName TestClientName = "Standard User"
 
-	ClusterOwner Role = "cluster-owner"
-	ProjectOwner Role = "project-owner"
+	ClusterOwner  Role = "cluster-owner"
+	ProjectOwner  Role = "project-owner"
+	ClusterMember Role = "cluster-member"
+	ProjectMember Role = "project-member"
+	ReadOnly      Role = "read-only"
+
+	DeclaredBindings Role = "declared-bindings"
+
+	GlobalScope  RBACScope = "global"
+	ClusterScope RBACScope = "cluster"
+	ProjectScope RBACScope = "project"
 
 	RancherPrivileged PSACT = "rancher-privileged"
 	RancherRestricted PSACT = "rancher-restricted"
@@ -97,6 +108,12 @@ func (c TestClientName) String() string {
 	return string(c)
 }
 
+type AgentCustomization struct {
+	AgentEnvVars []rkev1.EnvVar                               `json:"agentEnvVars,omitempty" yaml:"agentEnvVars,omitempty"`
+	ClusterAgent *provisioningv1.AgentDeploymentCustomization `json:"clusterAgent,omitempty" yaml:"clusterAgent,omitempty"`
+	FleetAgent   *provisioningv1.AgentDeploymentCustomization `json:"fleetAgent,omitempty" yaml:"fleetAgent,omitempty"`
+}
+
 type Nodepool struct {
 	Quantity         int64  `json:"quantity,omitempty" yaml:"quantity,omitempty"`
 	Etcd             bool   `json:"etcd,omitempty" yaml:"etcd,omitempty"`
@@ -125,6 +142,25 @@ type PrivateRegistries struct {
 	Username               string `json:"username,omitempty" yaml:"username,omitempty"`
 }
 
+type RKEConfig struct {
+	AdditionalManifest    string                          `json:"additionalManifest,omitempty" yaml:"additionalManifest,omitempty"`
+	ETCDArgs              []string                        `json:"etcdArgs,omitempty" yaml:"etcdArgs,omitempty"`
+	LocalAuthEndpoint     *rkev1.LocalClusterAuthEndpoint `json:"localAuthEndpoint,omitempty" yaml:"localAuthEndpoint,omitempty"`
+	MachineGlobalConfig   map[string]any                  `json:"machineGlobalConfig,omitempty" yaml:"machineGlobalConfig,omitempty"`
+	MachineSelectorConfig []rkev1.RKESystemConfig         `json:"machineSelectorConfig,omitempty" yaml:"machineSelectorConfig,omitempty"`
+	MachineSelectorFiles  []rkev1.RKEProvisioningFiles    `json:"machineSelectorFiles,omitempty" yaml:"machineSelectorFiles,omitempty"`
+}
+
+type S3Credentials struct {
+	AccessKey     string `json:"accessKey,omitempty" yaml:"accessKey,omitempty"`
+	Bucket        string `json:"bucket,omitempty" yaml:"bucket,omitempty"`
+	Endpoint      string `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	EndpointCA    string `json:"endpointCA,omitempty" yaml:"endpointCA,omitempty"`
+	Region        string `json:"region,omitempty" yaml:"region,omitempty"`
+	SecretKey     string `json:"secretKey,omitempty" yaml:"secretKey,omitempty"`
+	SkipSSLVerify bool   `json:"skipSSLVerify,omitempty" yaml:"skipSSLVerify,omitempty"`
+}
+
 type Standalone struct {
 	AirgapInternalFQDN             string `json:"airgapInternalFQDN,omitempty" yaml:"airgapInternalFQDN,omitempty"`
 	BootstrapPassword              string `json:"bootstrapPassword,omitempty" yaml:"bootstrapPassword,omitempty"`
@@ -149,6 +185,18 @@ type Standalone struct {
 	UpgradedRancherTagVersion      string `json:"upgradedRancherTagVersion,omitempty" yaml:"upgradedRancherTagVersion,omitempty"`
 }
 
+type StandaloneBackup struct {
+	ChartRepository string `json:"chartRepository,omitempty" yaml:"chartRepository,omitempty"`
+	ChartVersion    string `json:"chartVersion,omitempty" yaml:"chartVersion,omitempty"`
+	ResourceSetName string `json:"resourceSetName,omitempty" yaml:"resourceSetName,omitempty"`
+}
+
+type StandaloneMinIO struct {
+	Bucket  string `json:"bucket,omitempty" yaml:"bucket,omitempty"`
+	Port    string `json:"port,omitempty" yaml:"port,omitempty"`
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+}
+
 type StandaloneRegistry struct {
 	AssetsPath         string `json:"assetsPath,omitempty" yaml:"assetsPath,omitempty"`
 	Authenticated      bool   `json:"authenticated,omitempty" yaml:"authenticated,omitempty"`
@@ -158,43 +206,180 @@ type StandaloneRegistry struct {
 	UpgradedAssetsPath string `json:"upgradedAssetsPath,omitempty" yaml:"upgradedAssetsPath,omitempty"`
 }
 
+type TerraformProvider struct {
+	DevOverride string `json:"devOverride,omitempty" yaml:"devOverride,omitempty"`
+	Source      string `json:"source,omitempty" yaml:"source,omitempty"`
+	Version     string `json:"version,omitempty" yaml:"version,omitempty"`
+}
+
+type Providers struct {
+	FilesystemMirror string                       `json:"filesystemMirror,omitempty" yaml:"filesystemMirror,omitempty"`
+	Manifest         string                       `json:"manifest,omitempty" yaml:"manifest,omitempty"`
+	Versions         map[string]TerraformProvider `json:"versions,omitempty" yaml:"versions,omitempty"`
+}
+
 type TerraformConfig struct {
-	AWSConfig                           aws.Config                   `json:"awsConfig,omitempty" yaml:"awsConfig,omitempty"`
-	AWSCredentials                      aws.Credentials              `json:"awsCredentials,omitempty" yaml:"awsCredentials,omitempty"`
-	AzureConfig                         azure.Config                 `json:"azureConfig,omitempty" yaml:"azureConfig,omitempty"`
-	AzureCredentials                    azure.Credentials            `json:"azureCredentials,omitempty" yaml:"azureCredentials,omitempty"`
-	GoogleConfig                        google.Config                `json:"googleConfig,omitempty" yaml:"googleConfig,omitempty"`
-	GoogleCredentials                   google.Credentials           `json:"googleCredentials,omitempty" yaml:"googleCredentials,omitempty"`
-	HarvesterConfig                     harvester.Config             `json:"harvesterConfig,omitempty" yaml:"harvesterConfig,omitempty"`
-	HarvesterCredentials                harvester.Credentials        `json:"harvesterCredentials,omitempty" yaml:"harvesterCredentials,omitempty"`
-	LinodeConfig                        linode.Config                `json:"linodeConfig,omitempty" yaml:"linodeConfig,omitempty"`
-	LinodeCredentials                   linode.Credentials           `json:"linodeCredentials,omitempty" yaml:"linodeCredentials,omitempty"`
-	VsphereConfig                       vsphere.Config               `json:"vsphereConfig,omitempty" yaml:"vsphereConfig,omitempty"`
-	VsphereCredentials                  vsphere.Credentials          `json:"vsphereCredentials,omitempty" yaml:"vsphereCredentials,omitempty"`
-	ADConfig                            authproviders.ADConfig       `json:"adConfig,omitempty" yaml:"adConfig,omitempty"`
-	AzureADConfig                       authproviders.AzureADConfig  `json:"azureADConfig,omitempty" yaml:"azureADConfig,omitempty"`
-	GithubConfig                        authproviders.GithubConfig   `json:"githubConfig,omitempty" yaml:"githubConfig,omitempty"`
-	OktaConfig                          authproviders.OktaConfig     `json:"oktaConfig,omitempty" yaml:"oktaConfig,omitempty"`
-	OpenLDAPConfig                      authproviders.OpenLDAPConfig `json:"openLDAPConfig,omitempty" yaml:"openLDAPConfig,omitempty"`
-	AuthProvider                        string                       `json:"authProvider,omitempty" yaml:"authProvider,omitempty"`
-	ResourcePrefix                      string                       `json:"resourcePrefix,omitempty" yaml:"resourcePrefix,omitempty"`
-	CNI                                 string                       `json:"cni,omitempty" yaml:"cni,omitempty"`
-	ChartValues                         string                       `json:"chartValues,omitempty" yaml:"chartValues,omitempty"`
-	DisableKubeProxy                    string                       `json:"disable-kube-proxy,omitempty" yaml:"disable-kube-proxy,omitempty"`
-	DefaultClusterRoleForProjectMembers string                       `json:"defaultClusterRoleForProjectMembers,omitempty" yaml:"defaultClusterRoleForProjectMembers,omitempty"`
-	EnableNetworkPolicy                 bool                         `json:"enableNetworkPolicy,omitempty" yaml:"enableNetworkPolicy,omitempty"`
-	ETCD                                *rkev1.ETCD                  `json:"etcd,omitempty" yaml:"etcd,omitempty"`
-	ETCDRKE1                            *management.ETCDService      `json:"etcdRKE1,omitempty" yaml:"etcdRKE1,omitempty"`
-	Module                              string                       `json:"module,omitempty" yaml:"module,omitempty"`
-	NetworkPlugin                       string                       `json:"networkPlugin,omitempty" yaml:"networkPlugin,omitempty"`
-	PrivateKeyPath                      string                       `json:"privateKeyPath,omitempty" yaml:"privateKeyPath,omitempty"`
-	PrivateRegistries                   *PrivateRegistries           `json:"privateRegistries,omitempty" yaml:"privateRegistries,omitempty"`
-	Proxy                               *Proxy                       `json:"proxy,omitempty" yaml:"proxy,omitempty"`
-	Provider                            string                       `json:"provider,omitempty" yaml:"provider,omitempty"`
-	Standalone                          *Standalone                  `json:"standalone,omitempty" yaml:"standalone,omitempty"`
-	StandaloneRegistry                  *StandaloneRegistry          `json:"standaloneRegistry,omitempty" yaml:"standaloneRegistry,omitempty"`
-	TimeSleep                           string                       `json:"timeSleep,omitempty" yaml:"timeSleep,omitempty"`
-	WindowsPrivateKeyPath               string                       `json:"windowsPrivateKeyPath,omitempty" yaml:"windowsPrivateKeyPath,omitempty"`
+	AgentCustomization                  *AgentCustomization           `json:"agentCustomization,omitempty" yaml:"agentCustomization,omitempty"`
+	AWSConfig                           aws.Config                    `json:"awsConfig,omitempty" yaml:"awsConfig,omitempty"`
+	AWSCredentials                      aws.Credentials               `json:"awsCredentials,omitempty" yaml:"awsCredentials,omitempty"`
+	AzureConfig                         azure.Config                  `json:"azureConfig,omitempty" yaml:"azureConfig,omitempty"`
+	AzureCredentials                    azure.Credentials             `json:"azureCredentials,omitempty" yaml:"azureCredentials,omitempty"`
+	GoogleConfig                        google.Config                 `json:"googleConfig,omitempty" yaml:"googleConfig,omitempty"`
+	GoogleCredentials                   google.Credentials            `json:"googleCredentials,omitempty" yaml:"googleCredentials,omitempty"`
+	HarvesterConfig                     harvester.Config              `json:"harvesterConfig,omitempty" yaml:"harvesterConfig,omitempty"`
+	HarvesterCredentials                harvester.Credentials         `json:"harvesterCredentials,omitempty" yaml:"harvesterCredentials,omitempty"`
+	LinodeConfig                        linode.Config                 `json:"linodeConfig,omitempty" yaml:"linodeConfig,omitempty"`
+	LinodeCredentials                   linode.Credentials            `json:"linodeCredentials,omitempty" yaml:"linodeCredentials,omitempty"`
+	VsphereConfig                       vsphere.Config                `json:"vsphereConfig,omitempty" yaml:"vsphereConfig,omitempty"`
+	VsphereCredentials                  vsphere.Credentials           `json:"vsphereCredentials,omitempty" yaml:"vsphereCredentials,omitempty"`
+	ADConfig                            authproviders.ADConfig        `json:"adConfig,omitempty" yaml:"adConfig,omitempty"`
+	AzureADConfig                       authproviders.AzureADConfig   `json:"azureADConfig,omitempty" yaml:"azureADConfig,omitempty"`
+	GithubConfig                        authproviders.GithubConfig    `json:"githubConfig,omitempty" yaml:"githubConfig,omitempty"`
+	OktaConfig                          authproviders.OktaConfig      `json:"oktaConfig,omitempty" yaml:"oktaConfig,omitempty"`
+	OpenLDAPConfig                      authproviders.OpenLDAPConfig  `json:"openLDAPConfig,omitempty" yaml:"openLDAPConfig,omitempty"`
+	ADFSConfig                          authproviders.SAMLConfig      `json:"adfsConfig,omitempty" yaml:"adfsConfig,omitempty"`
+	FreeIPAConfig                       authproviders.FreeIPAConfig   `json:"freeIPAConfig,omitempty" yaml:"freeIPAConfig,omitempty"`
+	GenericOIDCConfig                   authproviders.OIDCConfig      `json:"genericOIDCConfig,omitempty" yaml:"genericOIDCConfig,omitempty"`
+	KeycloakConfig                      authproviders.SAMLConfig      `json:"keycloakConfig,omitempty" yaml:"keycloakConfig,omitempty"`
+	KeycloakOIDCConfig                  authproviders.OIDCConfig      `json:"keycloakOIDCConfig,omitempty" yaml:"keycloakOIDCConfig,omitempty"`
+	PingConfig                          authproviders.SAMLConfig      `json:"pingConfig,omitempty" yaml:"pingConfig,omitempty"`
+	AccessMode                          string                        `json:"accessMode,omitempty" yaml:"accessMode,omitempty"`
+	AllowedPrincipalIDs                 []string                      `json:"allowedPrincipalIds,omitempty" yaml:"allowedPrincipalIds,omitempty"`
+	AuthProvider                        string                        `json:"authProvider,omitempty" yaml:"authProvider,omitempty"`
+	ResourcePrefix                      string                        `json:"resourcePrefix,omitempty" yaml:"resourcePrefix,omitempty"`
+	CNI                                 string                        `json:"cni,omitempty" yaml:"cni,omitempty"`
+	ChartValues                         string                        `json:"chartValues,omitempty" yaml:"chartValues,omitempty"`
+	DisableKubeProxy                    string                        `json:"disable-kube-proxy,omitempty" yaml:"disable-kube-proxy,omitempty"`
+	DefaultClusterRoleForProjectMembers string                        `json:"defaultClusterRoleForProjectMembers,omitempty" yaml:"defaultClusterRoleForProjectMembers,omitempty"`
+	EnableNetworkPolicy                 bool                          `json:"enableNetworkPolicy,omitempty" yaml:"enableNetworkPolicy,omitempty"`
+	ETCD                                *rkev1.ETCD                   `json:"etcd,omitempty" yaml:"etcd,omitempty"`
+	ETCDRKE1                            *management.ETCDService       `json:"etcdRKE1,omitempty" yaml:"etcdRKE1,omitempty"`
+	Module                              string                        `json:"module,omitempty" yaml:"module,omitempty"`
+	NetworkPlugin                       string                        `json:"networkPlugin,omitempty" yaml:"networkPlugin,omitempty"`
+	PrivateKeyPath                      string                        `json:"privateKeyPath,omitempty" yaml:"privateKeyPath,omitempty"`
+	PrivateRegistries                   *PrivateRegistries            `json:"privateRegistries,omitempty" yaml:"privateRegistries,omitempty"`
+	Proxy                               *Proxy                        `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	Provider                            string                        `json:"provider,omitempty" yaml:"provider,omitempty"`
+	Providers                           *Providers                    `json:"providers,omitempty" yaml:"providers,omitempty"`
+	RKEConfig                           *RKEConfig                    `json:"rkeConfig,omitempty" yaml:"rkeConfig,omitempty"`
+	S3Credentials                       *S3Credentials                `json:"s3Credentials,omitempty" yaml:"s3Credentials,omitempty"`
+	Standalone                          *Standalone                   `json:"standalone,omitempty" yaml:"standalone,omitempty"`
+	StandaloneBackup                    *StandaloneBackup             `json:"standaloneBackup,omitempty" yaml:"standaloneBackup,omitempty"`
+	StandaloneMinIO                     *StandaloneMinIO              `json:"standaloneMinIO,omitempty" yaml:"standaloneMinIO,omitempty"`
+	StandaloneRegistry                  *StandaloneRegistry           `json:"standaloneRegistry,omitempty" yaml:"standaloneRegistry,omitempty"`
+	TimeSleep                           string                        `json:"timeSleep,omitempty" yaml:"timeSleep,omitempty"`
+	UpgradeStrategy                     *rkev1.ClusterUpgradeStrategy `json:"upgradeStrategy,omitempty" yaml:"upgradeStrategy,omitempty"`
+	WindowsPrivateKeyPath               string                        `json:"windowsPrivateKeyPath,omitempty" yaml:"windowsPrivateKeyPath,omitempty"`
+}
+
+type App struct {
+	ChartName            string `json:"chartName,omitempty" yaml:"chartName,omitempty"`
+	ChartVersion         string `json:"chartVersion,omitempty" yaml:"chartVersion,omitempty"`
+	Name                 string `json:"name,omitempty" yaml:"name,omitempty"`
+	Namespace            string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Repo                 string `json:"repo,omitempty" yaml:"repo,omitempty"`
+	UpgradedChartVersion string `json:"upgradedChartVersion,omitempty" yaml:"upgradedChartVersion,omitempty"`
+	Values               string `json:"values,omitempty" yaml:"values,omitempty"`
+}
+
+type ChartRepo struct {
+	GitBranch string `json:"gitBranch,omitempty" yaml:"gitBranch,omitempty"`
+	GitRepo   string `json:"gitRepo,omitempty" yaml:"gitRepo,omitempty"`
+	Insecure  bool   `json:"insecure,omitempty" yaml:"insecure,omitempty"`
+	Name      string `json:"name,omitempty" yaml:"name,omitempty"`
+	URL       string `json:"url,omitempty" yaml:"url,omitempty"`
+}
+
+type Marketplace struct {
+	Apps       []App       `json:"apps,omitempty" yaml:"apps,omitempty"`
+	ChartRepos []ChartRepo `json:"chartRepos,omitempty" yaml:"chartRepos,omitempty"`
+}
+
+type Feature struct {
+	Name  string `json:"name,omitempty" yaml:"name,omitempty"`
+	Value bool   `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+type Setting struct {
+	Name  string `json:"name,omitempty" yaml:"name,omitempty"`
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+type Token struct {
+	ClusterID   string `json:"clusterId,omitempty" yaml:"clusterId,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Name        string `json:"name,omitempty" yaml:"name,omitempty"`
+	TTL         int64  `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+}
+
+type GitRepo struct {
+	Branch          string   `json:"branch,omitempty" yaml:"branch,omitempty"`
+	Name            string   `json:"name,omitempty" yaml:"name,omitempty"`
+	Password        string   `json:"password,omitempty" yaml:"password,omitempty"`
+	Paths           []string `json:"paths,omitempty" yaml:"paths,omitempty"`
+	Repo            string   `json:"repo,omitempty" yaml:"repo,omitempty"`
+	TargetNamespace string   `json:"targetNamespace,omitempty" yaml:"targetNamespace,omitempty"`
+	Username        string   `json:"username,omitempty" yaml:"username,omitempty"`
+}
+
+type Fleet struct {
+	ClusterGroup string    `json:"clusterGroup,omitempty" yaml:"clusterGroup,omitempty"`
+	GitRepos     []GitRepo `json:"gitRepos,omitempty" yaml:"gitRepos,omitempty"`
+}
+
+type GlobalSettings struct {
+	Features []Feature `json:"features,omitempty" yaml:"features,omitempty"`
+	Settings []Setting `json:"settings,omitempty" yaml:"settings,omitempty"`
+	Tokens   []Token   `json:"tokens,omitempty" yaml:"tokens,omitempty"`
+}
+
+type Namespace struct {
+	ContainerResourceLimit *management.ContainerResourceLimit `json:"containerResourceLimit,omitempty" yaml:"containerResourceLimit,omitempty"`
+	Name                   string                             `json:"name,omitempty" yaml:"name,omitempty"`
+	ResourceQuota          *management.ResourceQuotaLimit     `json:"resourceQuota,omitempty" yaml:"resourceQuota,omitempty"`
+}
+
+type Project struct {
+	ContainerResourceLimit *management.ContainerResourceLimit `json:"containerResourceLimit,omitempty" yaml:"containerResourceLimit,omitempty"`
+	Name                   string                             `json:"name,omitempty" yaml:"name,omitempty"`
+	NamespaceDefaultLimit  *management.ResourceQuotaLimit     `json:"namespaceDefaultLimit,omitempty" yaml:"namespaceDefaultLimit,omitempty"`
+	Namespaces             []Namespace                        `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	ResourceQuota          *management.ResourceQuotaLimit     `json:"resourceQuota,omitempty" yaml:"resourceQuota,omitempty"`
+}
+
+type RBACRule struct {
+	APIGroups       []string `json:"apiGroups,omitempty" yaml:"apiGroups,omitempty"`
+	NonResourceURLs []string `json:"nonResourceURLs,omitempty" yaml:"nonResourceURLs,omitempty"`
+	ResourceNames   []string `json:"resourceNames,omitempty" yaml:"resourceNames,omitempty"`
+	Resources       []string `json:"resources,omitempty" yaml:"resources,omitempty"`
+	Verbs           []string `json:"verbs,omitempty" yaml:"verbs,omitempty"`
+}
+
+type RoleTemplate struct {
+	Context         string     `json:"context,omitempty" yaml:"context,omitempty"`
+	Name            string     `json:"name,omitempty" yaml:"name,omitempty"`
+	RoleTemplateIDs []string   `json:"roleTemplateIds,omitempty" yaml:"roleTemplateIds,omitempty"`
+	Rules           []RBACRule `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+type GlobalRole struct {
+	Name           string     `json:"name,omitempty" yaml:"name,omitempty"`
+	NewUserDefault bool       `json:"newUserDefault,omitempty" yaml:"newUserDefault,omitempty"`
+	Rules          []RBACRule `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+type RoleBinding struct {
+	GroupPrincipalID string    `json:"groupPrincipalId,omitempty" yaml:"groupPrincipalId,omitempty"`
+	Name             string    `json:"name,omitempty" yaml:"name,omitempty"`
+	Project          string    `json:"project,omitempty" yaml:"project,omitempty"`
+	Role             string    `json:"role,omitempty" yaml:"role,omitempty"`
+	Scope            RBACScope `json:"scope,omitempty" yaml:"scope,omitempty"`
+}
+
+type RBAC struct {
+	Bindings      []RoleBinding  `json:"bindings,omitempty" yaml:"bindings,omitempty"`
+	GlobalRoles   []GlobalRole   `json:"globalRoles,omitempty" yaml:"globalRoles,omitempty"`
+	RoleTemplates []RoleTemplate `json:"roleTemplates,omitempty" yaml:"roleTemplates,omitempty"`
 }
 
 type Scaling struct {
@@ -205,24 +390,36 @@ type Scaling struct {
 }
 
 type Snapshots struct {
-	CreateSnapshot  bool   `json:"createSnapshot,omitempty" yaml:"createSnapshot,omitempty"`
-	RestoreSnapshot bool   `json:"restoreSnapshot,omitempty" yaml:"restoreSnapshot,omitempty"`
-	SnapshotName    string `json:"snapshotName,omitempty" yaml:"snapshotName,omitempty"`
-	SnapshotRestore string `json:"snapshotRestore,omitempty" yaml:"snapshotRestore,omitempty"`
+	CreateSnapshot            bool   `json:"createSnapshot,omitempty" yaml:"createSnapshot,omitempty"`
+	CreateSnapshotGeneration  int64  `json:"createSnapshotGeneration,omitempty" yaml:"createSnapshotGeneration,omitempty"`
+	RestoreSnapshot           bool   `json:"restoreSnapshot,omitempty" yaml:"restoreSnapshot,omitempty"`
+	RestoreSnapshotGeneration int64  `json:"restoreSnapshotGeneration,omitempty" yaml:"restoreSnapshotGeneration,omitempty"`
+	SnapshotName              string `json:"snapshotName,omitempty" yaml:"snapshotName,omitempty"`
+	SnapshotRestore           string `json:"snapshotRestore,omitempty" yaml:"snapshotRestore,omitempty"`
 }
 
 type TerratestConfig struct {
-	KubernetesVersion         string     `json:"kubernetesVersion,omitempty" yaml:"kubernetesVersion,omitempty"`
-	LocalQaseReporting        bool       `json:"localQaseReporting,omitempty" yaml:"localQaseReporting,omitempty" default:"false"`
-	NodeCount                 int64      `json:"nodeCount,omitempty" yaml:"nodeCount,omitempty"`
-	Nodepools                 []Nodepool `json:"nodepools,omitempty" yaml:"nodepools,omitempty"`
-	PSACT                     string     `json:"psact,omitempty" yaml:"psact,omitempty"`
-	ScalingInput              Scaling    `json:"scalingInput,omitempty" yaml:"scalingInput,omitempty"`
-	SnapshotInput             Snapshots  `json:"snapshotInput,omitempty" yaml:"snapshotInput,omitempty"`
-	StandaloneLogging         bool       `json:"standaloneLogging,omitempty" yaml:"standaloneLogging,omitempty"`
-	TFLogging                 bool       `json:"tfLogging,omitempty" yaml:"tfLogging,omitempty"`
-	UpgradedKubernetesVersion string     `json:"upgradedKubernetesVersion,omitempty" yaml:"upgradedKubernetesVersion,omitempty"`
-	WindowsNodeCount          int64      `json:"windowsNodeCount,omitempty" yaml:"windowsNodeCount,omitempty"`
+	ArtifactsDir              string          `json:"artifactsDir,omitempty" yaml:"artifactsDir,omitempty"`
+	Fleet                     *Fleet          `json:"fleet,omitempty" yaml:"fleet,omitempty"`
+	GlobalSettings            *GlobalSettings `json:"globalSettings,omitempty" yaml:"globalSettings,omitempty"`
+	KubernetesUpgradePath     []string        `json:"kubernetesUpgradePath,omitempty" yaml:"kubernetesUpgradePath,omitempty"`
+	KubernetesVersion         string          `json:"kubernetesVersion,omitempty" yaml:"kubernetesVersion,omitempty"`
+	LocalQaseReporting        bool            `json:"localQaseReporting,omitempty" yaml:"localQaseReporting,omitempty" default:"false"`
+	Marketplace               *Marketplace    `json:"marketplace,omitempty" yaml:"marketplace,omitempty"`
+	MaxUpgradeDowntime        string          `json:"maxUpgradeDowntime,omitempty" yaml:"maxUpgradeDowntime,omitempty"`
+	NodeCount                 int64           `json:"nodeCount,omitempty" yaml:"nodeCount,omitempty"`
+	Nodepools                 []Nodepool      `json:"nodepools,omitempty" yaml:"nodepools,omitempty"`
+	Projects                  []Project       `json:"projects,omitempty" yaml:"projects,omitempty"`
+	PSACT                     string          `json:"psact,omitempty" yaml:"psact,omitempty"`
+	RBAC                      *RBAC           `json:"rbac,omitempty" yaml:"rbac,omitempty"`
+	ScalingInput              Scaling         `json:"scalingInput,omitempty" yaml:"scalingInput,omitempty"`
+	SnapshotInput             Snapshots       `json:"snapshotInput,omitempty" yaml:"snapshotInput,omitempty"`
+	StandaloneLogging         bool            `json:"standaloneLogging,omitempty" yaml:"standaloneLogging,omitempty"`
+	TFLogging                 bool            `json:"tfLogging,omitempty" yaml:"tfLogging,omitempty"`
+	UpgradedKubernetesVersion string          `json:"upgradedKubernetesVersion,omitempty" yaml:"upgradedKubernetesVersion,omitempty"`
+	UpgradedProviderVersion   string          `json:"upgradedProviderVersion,omitempty" yaml:"upgradedProviderVersion,omitempty"`
+	VerifyNoDrift             bool            `json:"verifyNoDrift,omitempty" yaml:"verifyNoDrift,omitempty" default:"false"`
+	WindowsNodeCount          int64           `json:"windowsNodeCount,omitempty" yaml:"windowsNodeCount,omitempty"`
 }
 
 // LoadTFPConfigs loads the TFP configurations from the provided map
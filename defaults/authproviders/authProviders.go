@@ -1,9 +1,15 @@
 package authproviders
 
 const (
-	AD       = "ad"
-	AzureAD  = "azureAD"
-	GitHub   = "github"
-	OpenLDAP = "openldap"
-	Okta     = "okta"
+	AD           = "ad"
+	ADFS         = "adfs"
+	AzureAD      = "azureAD"
+	FreeIPA      = "freeipa"
+	GenericOIDC  = "genericOIDC"
+	GitHub       = "github"
+	Keycloak     = "keycloak"
+	KeycloakOIDC = "keycloakOIDC"
+	OpenLDAP     = "openldap"
+	Okta         = "okta"
+	Ping         = "ping"
 )
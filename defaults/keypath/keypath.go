@@ -9,6 +9,7 @@ const (
 	RKEKeyPath        = "/src/github.com/rancher/tfp-automation/modules/rke"
 	RKE2KeyPath       = "/src/github.com/rancher/tfp-automation/modules/rke2"
 	RancherKeyPath    = "/src/github.com/rancher/tfp-automation/modules/rancher2"
+	RollbackKeyPath   = "/src/github.com/rancher/tfp-automation/modules/rollback"
 	SanityKeyPath     = "/src/github.com/rancher/tfp-automation/modules/sanity"
 	UpgradeKeyPath    = "/src/github.com/rancher/tfp-automation/modules/upgrade"
 )
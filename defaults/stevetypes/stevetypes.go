@@ -1,9 +1,11 @@
 package stevetypes
 
 const (
-	Deployment   = "apps.deployment"
-	Ingress      = "networking.k8s.io.ingress"
-	Machine      = "cluster.x-k8s.io.machine"
-	Provisioning = "provisioning.cattle.io.cluster"
-	Service      = "service"
+	Deployment      = "apps.deployment"
+	EtcdSnapshot    = "rke.cattle.io.etcdsnapshot"
+	Ingress         = "networking.k8s.io.ingress"
+	Machine         = "cluster.x-k8s.io.machine"
+	Provisioning    = "provisioning.cattle.io.cluster"
+	RKEControlPlane = "rke.cattle.io.rkecontrolplane"
+	Service         = "service"
 )
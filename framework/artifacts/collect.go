@@ -0,0 +1,202 @@
+package artifacts
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/rancher/shepherd/clients/rancher"
+	"github.com/rancher/shepherd/pkg/session"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/defaults/configs"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	ArtifactsDir = "artifacts"
+
+	stateJSON = "state.json"
+	tarSuffix = ".tar.gz"
+)
+
+// GetArtifactsDir is a function that will return the root directory of the test artifacts. It defaults to the
+// artifacts folder of the key path if terratest.artifactsDir is not set.
+func GetArtifactsDir(terratestConfig *config.TerratestConfig, keyPath string) string {
+	if terratestConfig != nil && terratestConfig.ArtifactsDir != "" {
+		return terratestConfig.ArtifactsDir
+	}
+
+	return filepath.Join(keyPath, ArtifactsDir)
+}
+
+// Collect is a function that will write the artifacts of the current test to its own directory and return it. The
+// directory contains the redacted main.tf, the redacted output of terraform show -json and, for every cluster in the
+// state that is not active, a dump of its Rancher objects and events. It must be called before terraform destroy, while
+// the state still holds the clusters; the Terraform logs are added by Bundle. Failures are only logged so that
+// collecting artifacts never fails the test, and an empty directory is returned if it could not be created.
+func Collect(t *testing.T, terraformOptions *terraform.Options, rancherConfig *rancher.Config, terratestConfig *config.TerratestConfig,
+	keyPath string) string {
+	testName := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	artifactsDir := filepath.Join(GetArtifactsDir(terratestConfig, keyPath), testName)
+
+	logrus.Infof("Collecting test artifacts in %s...", artifactsDir)
+
+	err := os.MkdirAll(artifactsDir, 0755)
+	if err != nil {
+		logrus.Warnf("Failed to create artifacts directory. Error: %v", err)
+		return ""
+	}
+
+	mainTF, err := os.ReadFile(keyPath + configs.MainTF)
+	if err == nil {
+		mainTF, err = redactHCL(mainTF)
+	}
+
+	if err == nil {
+		err = os.WriteFile(filepath.Join(artifactsDir, filepath.Base(configs.MainTF)), mainTF, 0644)
+	}
+
+	if err != nil {
+		logrus.Warnf("Failed to collect main.tf. Error: %v", err)
+	}
+
+	state := collectState(t, terraformOptions, artifactsDir)
+
+	if rancherConfig != nil && rancherConfig.Host != "" && rancherConfig.AdminToken != "" && state != nil {
+		client, err := rancher.NewClient(rancherConfig.AdminToken, session.NewSession())
+		if err != nil {
+			logrus.Warnf("Failed to create Rancher client for artifacts. Error: %v", err)
+		} else {
+			dumpClusters(client, state, artifactsDir)
+		}
+	}
+
+	return artifactsDir
+}
+
+// Bundle is a function that will move the Terraform logs of the key path into the artifacts directory returned by
+// Collect and tar it for archiving. It is called after terraform destroy, so that the bundle holds the init, apply and
+// destroy output of the test and the next test starts with an empty log.
+func Bundle(keyPath, artifactsDir string) {
+	if artifactsDir == "" {
+		return
+	}
+
+	err := moveLog(keyPath, artifactsDir)
+	if err != nil {
+		logrus.Warnf("Failed to collect Terraform logs. Error: %v", err)
+	}
+
+	err = tarDir(artifactsDir, artifactsDir+tarSuffix)
+	if err != nil {
+		logrus.Warnf("Failed to tar artifacts directory. Error: %v", err)
+	}
+}
+
+// collectState is a helper function that will write the redacted output of terraform show -json to the artifacts
+// directory and return the parsed state.
+func collectState(t *testing.T, terraformOptions *terraform.Options, artifactsDir string) *tfjson.State {
+	stateOptions := *terraformOptions
+	stateOptions.PlanFilePath = ""
+	stateOptions.Logger = logger.Discard
+
+	output, err := terraform.ShowE(t, &stateOptions)
+	if err != nil {
+		logrus.Warnf("Failed to run terraform show. Error: %v", err)
+		return nil
+	}
+
+	state := new(tfjson.State)
+	err = json.Unmarshal([]byte(output), state)
+	if err != nil {
+		logrus.Warnf("Failed to parse terraform show output. Error: %v", err)
+		state = nil
+	}
+
+	content, err := redactJSON([]byte(output))
+	if err == nil {
+		err = os.WriteFile(filepath.Join(artifactsDir, stateJSON), content, 0644)
+	}
+
+	if err != nil {
+		logrus.Warnf("Failed to collect Terraform state. Error: %v", err)
+	}
+
+	return state
+}
+
+// moveLog is a helper function that will move the Terraform log of the key path into the artifacts directory, so that
+// each test only bundles its own logs.
+func moveLog(keyPath, artifactsDir string) error {
+	content, err := os.ReadFile(filepath.Join(keyPath, TerraformLog))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(filepath.Join(artifactsDir, TerraformLog), content, 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Truncate(filepath.Join(keyPath, TerraformLog), 0)
+}
+
+// tarDir is a helper function that will write the directory to a gzipped tarball.
+func tarDir(dir, tarPath string) error {
+	tarFile, err := os.Create(tarPath)
+	if err != nil {
+		return err
+	}
+
+	defer tarFile.Close()
+
+	gzipWriter := gzip.NewWriter(tarFile)
+	defer gzipWriter.Close()
+
+	tarWriter := tar.NewWriter(gzipWriter)
+	defer tarWriter.Close()
+
+	baseDir := filepath.Dir(dir)
+
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+
+		header.Name, err = filepath.Rel(baseDir, path)
+		if err != nil {
+			return err
+		}
+
+		err = tarWriter.WriteHeader(header)
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+
+		_, err = io.Copy(tarWriter, file)
+
+		return err
+	})
+}
@@ -0,0 +1,149 @@
+package artifacts
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/rancher/norman/types"
+	"github.com/rancher/shepherd/clients/rancher"
+	"github.com/rancher/tfp-automation/defaults/stevetypes"
+	"github.com/rancher/tfp-automation/framework/set/defaults"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	active           = "active"
+	clusterIDFilter  = "clusterId"
+	clusterNameLabel = "cluster.x-k8s.io/cluster-name="
+	clusterV1ID      = "cluster_v1_id"
+	event            = "event"
+	fleetDefault     = "fleet-default"
+	id               = "id"
+	involvedObject   = "involvedObject"
+	labelSelector    = "labelSelector"
+	name             = "name"
+	rancherDir       = "rancher"
+)
+
+// stateCluster is a cluster found in the Terraform state.
+type stateCluster struct {
+	id   string
+	name string
+	isV2 bool
+}
+
+// dumpClusters is a function that will write the Rancher cluster, provisioning cluster, machines, nodes and events of
+// every cluster in the Terraform state that is not active to the rancher folder of the artifacts directory.
+func dumpClusters(client *rancher.Client, state *tfjson.State, artifactsDir string) {
+	for _, cluster := range getStateClusters(state) {
+		managementCluster, err := client.Management.Cluster.ByID(cluster.id)
+		if err != nil {
+			logrus.Warnf("Failed to get cluster %s for artifacts. Error: %v", cluster.name, err)
+			continue
+		}
+
+		if managementCluster.State == active {
+			continue
+		}
+
+		logrus.Infof("Cluster %s is %s, collecting Rancher objects...", cluster.name, managementCluster.State)
+
+		dump := map[string]any{
+			"cluster": managementCluster,
+		}
+
+		if cluster.isV2 {
+			provisioningCluster, err := client.Steve.SteveType(stevetypes.Provisioning).ByID(fleetDefault + "/" + cluster.name)
+			if err == nil {
+				dump["provisioningCluster"] = provisioningCluster.JSONResp
+			}
+
+			query := url.Values{labelSelector: []string{clusterNameLabel + cluster.name}}
+			machines, err := client.Steve.SteveType(stevetypes.Machine).NamespacedSteveClient(fleetDefault).List(query)
+			if err == nil {
+				dump["machines"] = machines.Data
+			}
+		}
+
+		nodes, err := client.Management.Node.List(&types.ListOpts{Filters: map[string]interface{}{clusterIDFilter: cluster.id}})
+		if err == nil {
+			dump["nodes"] = nodes.Data
+		}
+
+		dump["events"] = getEvents(client, cluster)
+
+		content, err := json.Marshal(dump)
+		if err != nil {
+			logrus.Warnf("Failed to marshal Rancher objects of cluster %s. Error: %v", cluster.name, err)
+			continue
+		}
+
+		content, err = redactJSON(content)
+		if err != nil {
+			logrus.Warnf("Failed to redact Rancher objects of cluster %s. Error: %v", cluster.name, err)
+			continue
+		}
+
+		err = os.MkdirAll(filepath.Join(artifactsDir, rancherDir), 0755)
+		if err == nil {
+			err = os.WriteFile(filepath.Join(artifactsDir, rancherDir, cluster.name+".json"), content, 0644)
+		}
+
+		if err != nil {
+			logrus.Warnf("Failed to write Rancher objects of cluster %s. Error: %v", cluster.name, err)
+		}
+	}
+}
+
+// getEvents is a helper function that will return the events in the fleet-default and cluster namespaces that involve
+// the cluster or one of its machines.
+func getEvents(client *rancher.Client, cluster stateCluster) []any {
+	var events []any
+
+	for _, namespace := range []string{fleetDefault, cluster.id} {
+		eventList, err := client.Steve.SteveType(event).NamespacedSteveClient(namespace).List(nil)
+		if err != nil {
+			continue
+		}
+
+		for _, item := range eventList.Data {
+			object, _ := item.JSONResp[involvedObject].(map[string]any)
+			objectName, _ := object[name].(string)
+
+			if namespace == cluster.id || strings.HasPrefix(objectName, cluster.name) {
+				events = append(events, item.JSONResp)
+			}
+		}
+	}
+
+	return events
+}
+
+// getStateClusters is a helper function that will return the rancher2_cluster and rancher2_cluster_v2 resources of the
+// Terraform state.
+func getStateClusters(state *tfjson.State) []stateCluster {
+	var clusters []stateCluster
+
+	if state == nil || state.Values == nil || state.Values.RootModule == nil {
+		return clusters
+	}
+
+	for _, resource := range state.Values.RootModule.Resources {
+		clusterName, _ := resource.AttributeValues[name].(string)
+
+		switch resource.Type {
+		case defaults.Cluster:
+			clusterID, _ := resource.AttributeValues[id].(string)
+			clusters = append(clusters, stateCluster{id: clusterID, name: clusterName})
+		case defaults.ClusterV2:
+			clusterID, _ := resource.AttributeValues[clusterV1ID].(string)
+			clusters = append(clusters, stateCluster{id: clusterID, name: clusterName, isV2: true})
+		}
+	}
+
+	return clusters
+}
@@ -0,0 +1,54 @@
+package artifacts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+const (
+	TerraformLog = "terraform.log"
+)
+
+// fileLogger is a Terratest logger that will always write the Terraform output to a log file so that it can be bundled
+// with the test artifacts, and will optionally forward the output to stdout.
+type fileLogger struct {
+	mu      sync.Mutex
+	path    string
+	forward *logger.Logger
+}
+
+// NewLogger is a function that will return a Terratest logger writing to the terraform.log file in the key path. When
+// tfLogging is false, the Terraform output is only written to the log file.
+func NewLogger(keyPath string, tfLogging bool) *logger.Logger {
+	forward := logger.Discard
+	if tfLogging {
+		forward = logger.Default
+	}
+
+	return logger.New(&fileLogger{
+		path:    filepath.Join(keyPath, TerraformLog),
+		forward: forward,
+	})
+}
+
+// Logf will append the formatted line to the log file and forward it to the wrapped logger.
+func (l *fileLogger) Logf(t testing.TestingT, format string, args ...interface{}) {
+	l.forward.Logf(t, format, args...)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+
+	defer file.Close()
+
+	fmt.Fprintf(file, format+"\n", args...)
+}
@@ -0,0 +1,195 @@
+package artifacts
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	redacted          = "REDACTED"
+	registrationToken = "registration_token"
+)
+
+var (
+	sensitiveKey = regexp.MustCompile(`(?i)(password|passwd|secret|token|access_key|accesskey|private_key|privatekey|ssh_key|sshkey|api_key|apikey|kube_?config|cert|ca_bundle|sp_key)`)
+
+	// registrationTokenKey matches the keys of a cluster registration token that embed the token in their value.
+	registrationTokenKey = regexp.MustCompile(`(?i)(command|manifest_url)`)
+
+	// sensitiveLine matches a key: value or key = value line with a sensitive key, as found in heredocs and embedded
+	// YAML documents.
+	sensitiveLine = regexp.MustCompile(`(?im)^(\s*-?\s*"?[\w.-]*(password|passwd|secret|token|access_key|accesskey|private_key|privatekey|ssh_key|sshkey|api_key|apikey|kube_?config|sp_key)[\w.-]*"?\s*[:=]\s*).+$`)
+
+	// rancherToken matches a Rancher token, which is 54 characters of the Rancher token alphabet, wherever it is
+	// embedded, such as in a registration command or a manifest URL.
+	rancherToken = regexp.MustCompile(`[bcdfghjklmnpqrstvwxz2456789]{54}`)
+)
+
+// redactHCL is a helper function that will replace the value of every sensitive attribute in the generated main.tf.
+func redactHCL(content []byte) ([]byte, error) {
+	file, diags := hclwrite.ParseConfig(content, "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	redactBody(file.Body())
+
+	return file.Bytes(), nil
+}
+
+// redactBody is a helper function that will redact the sensitive attributes of the body and all of its nested blocks.
+// Attributes holding an object or a list of objects, such as a map of credentials, have their sensitive keys redacted
+// at any depth.
+func redactBody(body *hclwrite.Body) {
+	for name, attribute := range body.Attributes() {
+		if sensitiveKey.MatchString(name) {
+			body.SetAttributeValue(name, cty.StringVal(redacted))
+			continue
+		}
+
+		tokens, changed := redactTokens(attribute.Expr().BuildTokens(nil))
+		if changed {
+			body.SetAttributeRaw(name, tokens)
+		}
+	}
+
+	for _, block := range body.Blocks() {
+		redactBody(block.Body())
+	}
+}
+
+// redactTokens is a helper function that will replace the value of every sensitive key of the object constructors in
+// the expression, scrub the string literals, such as heredocs, and report whether any value was redacted.
+func redactTokens(tokens hclwrite.Tokens) (hclwrite.Tokens, bool) {
+	var result hclwrite.Tokens
+	changed := false
+
+	for i := 0; i < len(tokens); i++ {
+		key, separator := objectKey(tokens, i)
+		if separator < 0 || !sensitiveKey.MatchString(key) {
+			token := tokens[i]
+
+			if token.Type == hclsyntax.TokenStringLit || token.Type == hclsyntax.TokenQuotedLit {
+				scrubbed := redactString(string(token.Bytes))
+				if scrubbed != string(token.Bytes) {
+					token = &hclwrite.Token{Type: token.Type, Bytes: []byte(scrubbed), SpacesBefore: token.SpacesBefore}
+					changed = true
+				}
+			}
+
+			result = append(result, token)
+			continue
+		}
+
+		result = append(result, tokens[i:separator+1]...)
+		result = append(result, hclwrite.TokensForValue(cty.StringVal(redacted))...)
+
+		i = valueEnd(tokens, separator+1) - 1
+		changed = true
+	}
+
+	return result, changed
+}
+
+// objectKey is a helper function that will return the object key starting at the given token and the index of the
+// = or : separating it from its value. A negative index is returned if the token does not start an object key.
+func objectKey(tokens hclwrite.Tokens, start int) (string, int) {
+	switch {
+	case tokens[start].Type == hclsyntax.TokenIdent:
+		if start+1 < len(tokens) && isKeySeparator(tokens[start+1]) {
+			return string(tokens[start].Bytes), start + 1
+		}
+	case tokens[start].Type == hclsyntax.TokenOQuote:
+		if start+3 < len(tokens) && tokens[start+1].Type == hclsyntax.TokenQuotedLit &&
+			tokens[start+2].Type == hclsyntax.TokenCQuote && isKeySeparator(tokens[start+3]) {
+			return string(tokens[start+1].Bytes), start + 3
+		}
+	}
+
+	return "", -1
+}
+
+// isKeySeparator is a helper function that will check if the token separates an object key from its value.
+func isKeySeparator(token *hclwrite.Token) bool {
+	return token.Type == hclsyntax.TokenEqual || token.Type == hclsyntax.TokenColon
+}
+
+// valueEnd is a helper function that will return the index of the first token after the object value starting at the
+// given token. The value ends at the next newline or comma outside of any nested expression, or at the end of the
+// enclosing object.
+func valueEnd(tokens hclwrite.Tokens, start int) int {
+	depth := 0
+
+	for i := start; i < len(tokens); i++ {
+		switch tokens[i].Type {
+		case hclsyntax.TokenOBrace, hclsyntax.TokenOBrack, hclsyntax.TokenOParen, hclsyntax.TokenOQuote,
+			hclsyntax.TokenOHeredoc, hclsyntax.TokenTemplateInterp, hclsyntax.TokenTemplateControl:
+			depth++
+		case hclsyntax.TokenCBrace, hclsyntax.TokenCBrack, hclsyntax.TokenCParen, hclsyntax.TokenCQuote,
+			hclsyntax.TokenCHeredoc, hclsyntax.TokenTemplateSeqEnd:
+			if depth == 0 {
+				return i
+			}
+
+			depth--
+		case hclsyntax.TokenNewline, hclsyntax.TokenComma:
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return len(tokens)
+}
+
+// redactJSON is a helper function that will replace the value of every sensitive key in the JSON document.
+func redactJSON(content []byte) ([]byte, error) {
+	var document any
+
+	err := json.Unmarshal(content, &document)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(redactValue(document, false), "", "  ")
+}
+
+// redactValue is a helper function that will walk a decoded JSON value and redact the sensitive keys. Inside a cluster
+// registration token, the commands and manifest URL are redacted as well, since they embed the token. Every other
+// string is scrubbed of sensitive lines and Rancher tokens.
+func redactValue(value any, inRegistrationToken bool) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, nested := range typed {
+			_, isString := nested.(string)
+			if isString && (sensitiveKey.MatchString(key) || (inRegistrationToken && registrationTokenKey.MatchString(key))) {
+				typed[key] = redacted
+				continue
+			}
+
+			typed[key] = redactValue(nested, inRegistrationToken || strings.Contains(key, registrationToken))
+		}
+	case []any:
+		for i, nested := range typed {
+			typed[i] = redactValue(nested, inRegistrationToken)
+		}
+	case string:
+		return redactString(typed)
+	}
+
+	return value
+}
+
+// redactString is a helper function that will redact the values of the sensitive lines of a multiline string, such as
+// a heredoc or an embedded YAML document, and any Rancher token embedded in it.
+func redactString(value string) string {
+	value = sensitiveLine.ReplaceAllString(value, "${1}"+redacted)
+
+	return rancherToken.ReplaceAllString(value, redacted)
+}
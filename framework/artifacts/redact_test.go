@@ -0,0 +1,92 @@
+package artifacts
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	registrationTokenValue = "b2c4d5f6g7h8j9k2l4m5n6p7q8r9s2t4v5w6x7z8b9c2d4f5g6h7j8"
+	bearerTokenValue       = "kubeconfig-u-abcde:" + registrationTokenValue
+)
+
+const clusterV2State = `{
+  "values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "rancher2_cluster_v2.cluster",
+          "type": "rancher2_cluster_v2",
+          "values": {
+            "name": "tfp-cluster",
+            "kube_config": "apiVersion: v1\nkind: Config\nusers:\n- name: tfp-cluster\n  user:\n    token: \"` + bearerTokenValue + `\"\n",
+            "cluster_registration_token": [
+              {
+                "cluster_id": "c-m-abcde",
+                "command": "kubectl apply -f https://rancher.example.com/v3/import/` + registrationTokenValue + `_c-m-abcde.yaml",
+                "insecure_command": "curl --insecure -sfL https://rancher.example.com/v3/import/` + registrationTokenValue + `_c-m-abcde.yaml | kubectl apply -f -",
+                "manifest_url": "https://rancher.example.com/v3/import/` + registrationTokenValue + `_c-m-abcde.yaml",
+                "node_command": "sudo docker run rancher/rancher-agent --server https://rancher.example.com --token ` + registrationTokenValue + `",
+                "token": "` + registrationTokenValue + `"
+              }
+            ]
+          }
+        }
+      ]
+    }
+  }
+}`
+
+const heredocConfig = `resource "rancher2_machine_config_v2" "machine_config" {
+  generate_name = "tfp-machine"
+
+  user_data = <<EOT
+#cloud-config
+password: supersecret
+runcmd:
+  - curl -sfL https://rancher.example.com/system-agent-install.sh | sh -s - --token ` + registrationTokenValue + `
+EOT
+}
+`
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name    string
+		redact  func([]byte) ([]byte, error)
+		content string
+		secrets []string
+		kept    []string
+	}{
+		{
+			name:    "Cluster v2 state",
+			redact:  redactJSON,
+			content: clusterV2State,
+			secrets: []string{registrationTokenValue, bearerTokenValue, "apiVersion: v1"},
+			kept:    []string{"tfp-cluster", "c-m-abcde"},
+		},
+		{
+			name:    "Heredoc in main.tf",
+			redact:  redactHCL,
+			content: heredocConfig,
+			secrets: []string{registrationTokenValue, "supersecret"},
+			kept:    []string{"tfp-machine", "#cloud-config"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.redact([]byte(tt.content))
+			require.NoError(t, err)
+
+			for _, secret := range tt.secrets {
+				require.False(t, strings.Contains(string(result), secret), "%q survived redaction", secret)
+			}
+
+			for _, value := range tt.kept {
+				require.Contains(t, string(result), value)
+			}
+		})
+	}
+}
@@ -6,15 +6,24 @@ import (
 	"github.com/gruntwork-io/terratest/modules/terraform"
 	"github.com/rancher/shepherd/clients/rancher"
 	"github.com/rancher/shepherd/pkg/config"
+	tfpConfig "github.com/rancher/tfp-automation/config"
 	"github.com/rancher/tfp-automation/defaults/configs"
+	"github.com/rancher/tfp-automation/framework/artifacts"
 	"github.com/sirupsen/logrus"
 )
 
-// Cleanup is a function that will run terraform destroy and cleanup Terraform resources.
+// Cleanup is a function that will collect the test artifacts, run terraform destroy and cleanup Terraform resources.
+// The artifacts are bundled once terraform destroy has run, so that they include its output.
 func Cleanup(t *testing.T, terraformOptions *terraform.Options, keyPath string) {
 	rancherConfig := new(rancher.Config)
 	config.LoadConfig(configs.Rancher, rancherConfig)
 
+	terratestConfig := new(tfpConfig.TerratestConfig)
+	config.LoadConfig(configs.Terratest, terratestConfig)
+
+	artifactsDir := artifacts.Collect(t, terraformOptions, rancherConfig, terratestConfig, keyPath)
+	defer artifacts.Bundle(keyPath, artifactsDir)
+
 	if *rancherConfig.Cleanup {
 		logrus.Infof("Cleaning up Terraform resources...")
 		terraform.Destroy(t, terraformOptions)
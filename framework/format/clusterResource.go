@@ -0,0 +1,30 @@
+package format
+
+import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/tfp-automation/framework/set/defaults"
+)
+
+const (
+	clusterV1ID = "cluster_v1_id"
+)
+
+// ClusterResource is a function that will return the address of the cluster resource with the given resource prefix in
+// the main.tf file.
+func ClusterResource(resourcePrefix string, isRKE1 bool) string {
+	if isRKE1 {
+		return defaults.Cluster + "." + resourcePrefix
+	}
+
+	return defaults.ClusterV2 + "." + resourcePrefix
+}
+
+// ClusterID is a function that will format the reference to the v1 ID of the cluster resource with the given resource
+// prefix, so that the cluster and the resources scoped to it can be created in the same plan.
+func ClusterID(resourcePrefix string, isRKE1 bool) hclwrite.Tokens {
+	if isRKE1 {
+		return RawExpression(ClusterResource(resourcePrefix, isRKE1) + ".id")
+	}
+
+	return RawExpression(ClusterResource(resourcePrefix, isRKE1) + "." + clusterV1ID)
+}
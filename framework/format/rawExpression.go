@@ -0,0 +1,13 @@
+package format
+
+import (
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// RawExpression is a function that will format the given expression, such as a resource reference, into raw HCL tokens.
+func RawExpression(expression string) hclwrite.Tokens {
+	return hclwrite.Tokens{
+		{Type: hclsyntax.TokenIdent, Bytes: []byte(expression)},
+	}
+}
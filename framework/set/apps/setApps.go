@@ -0,0 +1,85 @@
+package apps
+
+import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/framework/format"
+	"github.com/rancher/tfp-automation/framework/set/defaults"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	appV2     = "rancher2_app_v2"
+	catalogV2 = "rancher2_catalog_v2"
+
+	chartName    = "chart_name"
+	chartVersion = "chart_version"
+	clusterID    = "cluster_id"
+	gitBranch    = "git_branch"
+	gitRepo      = "git_repo"
+	insecure     = "insecure"
+	repoName     = "repo_name"
+	url          = "url"
+	values       = "values"
+)
+
+// SetApps is a function that will set the chart repositories and apps declared in the terratest config in the main.tf
+// file. Apps whose repo matches a declared chart repository reference it, any other repo is expected to already exist
+// in the cluster, such as rancher-charts.
+func SetApps(newFile *hclwrite.File, rootBody *hclwrite.Body, terraformConfig *config.TerraformConfig, marketplace *config.Marketplace,
+	isRKE1 bool) (*hclwrite.File, *hclwrite.Body, error) {
+	chartRepos := map[string]string{}
+
+	for _, chartRepo := range marketplace.ChartRepos {
+		catalogName := terraformConfig.ResourcePrefix + "-" + chartRepo.Name
+		chartRepos[chartRepo.Name] = catalogV2 + "." + catalogName + "." + defaults.ResourceName
+
+		catalogBlock := rootBody.AppendNewBlock(defaults.Resource, []string{catalogV2, catalogName})
+		catalogBlockBody := catalogBlock.Body()
+
+		catalogBlockBody.SetAttributeRaw(clusterID, format.ClusterID(terraformConfig.ResourcePrefix, isRKE1))
+		catalogBlockBody.SetAttributeValue(defaults.ResourceName, cty.StringVal(chartRepo.Name))
+
+		if chartRepo.GitRepo != "" {
+			catalogBlockBody.SetAttributeValue(gitRepo, cty.StringVal(chartRepo.GitRepo))
+			catalogBlockBody.SetAttributeValue(gitBranch, cty.StringVal(chartRepo.GitBranch))
+		} else {
+			catalogBlockBody.SetAttributeValue(url, cty.StringVal(chartRepo.URL))
+		}
+
+		if chartRepo.Insecure {
+			catalogBlockBody.SetAttributeValue(insecure, cty.BoolVal(true))
+		}
+
+		rootBody.AppendNewline()
+	}
+
+	for _, app := range marketplace.Apps {
+		appBlock := rootBody.AppendNewBlock(defaults.Resource, []string{appV2, terraformConfig.ResourcePrefix + "-" + app.Name})
+		appBlockBody := appBlock.Body()
+
+		appBlockBody.SetAttributeRaw(clusterID, format.ClusterID(terraformConfig.ResourcePrefix, isRKE1))
+		appBlockBody.SetAttributeValue(defaults.ResourceName, cty.StringVal(app.Name))
+		appBlockBody.SetAttributeValue(defaults.Namespace, cty.StringVal(app.Namespace))
+
+		if catalogReference, ok := chartRepos[app.Repo]; ok {
+			appBlockBody.SetAttributeRaw(repoName, format.RawExpression(catalogReference))
+		} else {
+			appBlockBody.SetAttributeValue(repoName, cty.StringVal(app.Repo))
+		}
+
+		appBlockBody.SetAttributeValue(chartName, cty.StringVal(app.ChartName))
+
+		if app.ChartVersion != "" {
+			appBlockBody.SetAttributeValue(chartVersion, cty.StringVal(app.ChartVersion))
+		}
+
+		if app.Values != "" {
+			appBlockBody.SetAttributeValue(values, cty.StringVal(app.Values))
+		}
+
+		rootBody.AppendNewline()
+	}
+
+	return newFile, rootBody, nil
+}
@@ -0,0 +1,32 @@
+package access
+
+import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	accessMode          = "access_mode"
+	allowedPrincipalIDs = "allowed_principal_ids"
+)
+
+// SetAccess is a function that will set the access mode and allowed principals of an auth provider block in the main.tf
+// file. Nothing is set when they are not given, so that the provider defaults are kept. Once an access mode is given,
+// the allowed principals are always set, so that an empty list clears the principals of a previous apply.
+func SetAccess(authConfigBlockBody *hclwrite.Body, terraformConfig *config.TerraformConfig) {
+	if terraformConfig.AccessMode != "" {
+		authConfigBlockBody.SetAttributeValue(accessMode, cty.StringVal(terraformConfig.AccessMode))
+	}
+
+	if len(terraformConfig.AllowedPrincipalIDs) > 0 {
+		var principalIDs []cty.Value
+		for _, principalID := range terraformConfig.AllowedPrincipalIDs {
+			principalIDs = append(principalIDs, cty.StringVal(principalID))
+		}
+
+		authConfigBlockBody.SetAttributeValue(allowedPrincipalIDs, cty.ListVal(principalIDs))
+	} else if terraformConfig.AccessMode != "" {
+		authConfigBlockBody.SetAttributeValue(allowedPrincipalIDs, cty.ListValEmpty(cty.String))
+	}
+}
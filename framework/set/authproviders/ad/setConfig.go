@@ -5,6 +5,7 @@ import (
 
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/framework/set/authproviders/access"
 	"github.com/sirupsen/logrus"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -35,6 +36,8 @@ func SetAD(terraformConfig *config.TerraformConfig, newFile *hclwrite.File, root
 	adBlockBody.SetAttributeValue(testUsername, cty.StringVal(terraformConfig.ADConfig.TestUsername))
 	adBlockBody.SetAttributeValue(testPassword, cty.StringVal(terraformConfig.ADConfig.TestPassword))
 
+	access.SetAccess(adBlockBody, terraformConfig)
+
 	_, err := file.Write(newFile.Bytes())
 	if err != nil {
 		logrus.Infof("Failed to write Active Directory configurations to main.tf file. Error: %v", err)
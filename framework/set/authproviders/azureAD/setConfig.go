@@ -6,6 +6,7 @@ import (
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/rancher/shepherd/clients/rancher"
 	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/framework/set/authproviders/access"
 	"github.com/sirupsen/logrus"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -37,6 +38,8 @@ func SetAzureAD(rancherConfig *rancher.Config, terraformConfig *config.Terraform
 	azureADBlockBody.SetAttributeValue(tenantID, cty.StringVal(terraformConfig.AzureADConfig.TenantID))
 	azureADBlockBody.SetAttributeValue(tokenEndpoint, cty.StringVal(terraformConfig.AzureADConfig.TokenEndpoint))
 
+	access.SetAccess(azureADBlockBody, terraformConfig)
+
 	_, err := file.Write(newFile.Bytes())
 	if err != nil {
 		logrus.Infof("Failed to write Azure AD configurations to main.tf file. Error: %v", err)
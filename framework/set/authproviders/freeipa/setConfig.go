@@ -0,0 +1,61 @@
+package freeipa
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/framework/set/authproviders/access"
+	"github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	freeIPAConfig = "rancher2_auth_config_freeipa"
+
+	resource                        = "resource"
+	port                            = "port"
+	servers                         = "servers"
+	serviceAccountDistinguishedName = "service_account_distinguished_name"
+	serviceAccountPassword          = "service_account_password"
+	userSearchBase                  = "user_search_base"
+	testUsername                    = "test_username"
+	testPassword                    = "test_password"
+)
+
+// SetFreeIPA is a function that will set the FreeIPA configurations in the main.tf file.
+func SetFreeIPA(terraformConfig *config.TerraformConfig, newFile *hclwrite.File, rootBody *hclwrite.Body, file *os.File) error {
+	if len(terraformConfig.FreeIPAConfig.Servers) == 0 {
+		return fmt.Errorf("freeIPAConfig.servers must contain at least one server")
+	}
+
+	freeIPABlock := rootBody.AppendNewBlock(resource, []string{freeIPAConfig, freeIPAConfig})
+	freeIPABlockBody := freeIPABlock.Body()
+
+	if terraformConfig.FreeIPAConfig.Port != 0 {
+		freeIPABlockBody.SetAttributeValue(port, cty.NumberIntVal(terraformConfig.FreeIPAConfig.Port))
+	}
+
+	var serverValues []cty.Value
+	for _, server := range terraformConfig.FreeIPAConfig.Servers {
+		serverValues = append(serverValues, cty.StringVal(server))
+	}
+
+	freeIPABlockBody.SetAttributeValue(servers, cty.ListVal(serverValues))
+	freeIPABlockBody.SetAttributeValue(serviceAccountDistinguishedName, cty.StringVal(terraformConfig.FreeIPAConfig.ServiceAccountDistinguishedName))
+	freeIPABlockBody.SetAttributeValue(serviceAccountPassword, cty.StringVal(terraformConfig.FreeIPAConfig.ServiceAccountPassword))
+	freeIPABlockBody.SetAttributeValue(userSearchBase, cty.StringVal(terraformConfig.FreeIPAConfig.UserSearchBase))
+	freeIPABlockBody.SetAttributeValue(testUsername, cty.StringVal(terraformConfig.FreeIPAConfig.TestUsername))
+	freeIPABlockBody.SetAttributeValue(testPassword, cty.StringVal(terraformConfig.FreeIPAConfig.TestPassword))
+
+	access.SetAccess(freeIPABlockBody, terraformConfig)
+
+	_, err := file.Write(newFile.Bytes())
+	if err != nil {
+		logrus.Infof("Failed to write FreeIPA configurations to main.tf file. Error: %v", err)
+		return err
+	}
+
+	return nil
+}
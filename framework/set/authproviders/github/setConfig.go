@@ -5,6 +5,7 @@ import (
 
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/framework/set/authproviders/access"
 	"github.com/sirupsen/logrus"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -25,6 +26,8 @@ func SetGithub(terraformConfig *config.TerraformConfig, newFile *hclwrite.File,
 	githubBlockBody.SetAttributeValue(clientID, cty.StringVal(terraformConfig.GithubConfig.ClientID))
 	githubBlockBody.SetAttributeValue(clientSecret, cty.StringVal(terraformConfig.GithubConfig.ClientSecret))
 
+	access.SetAccess(githubBlockBody, terraformConfig)
+
 	_, err := file.Write(newFile.Bytes())
 	if err != nil {
 		logrus.Infof("Failed to write Github configurations to main.tf file. Error: %v", err)
@@ -5,6 +5,7 @@ import (
 
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/framework/set/authproviders/access"
 	"github.com/sirupsen/logrus"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -35,6 +36,8 @@ func SetOpenLDAP(terraformConfig *config.TerraformConfig, newFile *hclwrite.File
 	openLDAPBlockBody.SetAttributeValue(testUsername, cty.StringVal(terraformConfig.OpenLDAPConfig.TestUsername))
 	openLDAPBlockBody.SetAttributeValue(testPassword, cty.StringVal(terraformConfig.OpenLDAPConfig.TestPassword))
 
+	access.SetAccess(openLDAPBlockBody, terraformConfig)
+
 	_, err := file.Write(newFile.Bytes())
 	if err != nil {
 		logrus.Infof("Failed to write OpenLDAP configurations to main.tf file. Error: %v", err)
@@ -0,0 +1,104 @@
+package oidc
+
+import (
+	"os"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/shepherd/clients/rancher"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/config/authproviders"
+	"github.com/rancher/tfp-automation/framework/set/authproviders/access"
+	"github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	genericOIDCConfig = "rancher2_auth_config_generic_oidc"
+
+	resource         = "resource"
+	authEndpoint     = "auth_endpoint"
+	clientID         = "client_id"
+	clientSecret     = "client_secret"
+	groupsField      = "groups_field"
+	issuer           = "issuer"
+	jwksURL          = "jwks_url"
+	rancherURL       = "rancher_url"
+	scopes           = "scopes"
+	tokenEndpoint    = "token_endpoint"
+	userInfoEndpoint = "user_info_endpoint"
+
+	keycloakAuthPath     = "/protocol/openid-connect/auth"
+	keycloakCertsPath    = "/protocol/openid-connect/certs"
+	keycloakTokenPath    = "/protocol/openid-connect/token"
+	keycloakUserInfoPath = "/protocol/openid-connect/userinfo"
+	verifyAuthCallback   = "/verify-auth"
+)
+
+// SetGenericOIDC is a function that will set the generic OIDC configurations in the main.tf file.
+func SetGenericOIDC(rancherConfig *rancher.Config, terraformConfig *config.TerraformConfig, newFile *hclwrite.File,
+	rootBody *hclwrite.Body, file *os.File) error {
+	return setOIDC(rancherConfig, terraformConfig, &terraformConfig.GenericOIDCConfig, "generic OIDC", newFile, rootBody, file)
+}
+
+// SetKeycloakOIDC is a function that will set the generic OIDC configurations for a Keycloak realm in the main.tf file.
+// Endpoints that are not given are derived from the realm issuer URL.
+func SetKeycloakOIDC(rancherConfig *rancher.Config, terraformConfig *config.TerraformConfig, newFile *hclwrite.File,
+	rootBody *hclwrite.Body, file *os.File) error {
+	keycloakOIDCConfig := terraformConfig.KeycloakOIDCConfig
+	realmURL := strings.TrimSuffix(keycloakOIDCConfig.Issuer, "/")
+
+	if keycloakOIDCConfig.AuthEndpoint == "" {
+		keycloakOIDCConfig.AuthEndpoint = realmURL + keycloakAuthPath
+	}
+
+	if keycloakOIDCConfig.JWKSURL == "" {
+		keycloakOIDCConfig.JWKSURL = realmURL + keycloakCertsPath
+	}
+
+	if keycloakOIDCConfig.TokenEndpoint == "" {
+		keycloakOIDCConfig.TokenEndpoint = realmURL + keycloakTokenPath
+	}
+
+	if keycloakOIDCConfig.UserInfoEndpoint == "" {
+		keycloakOIDCConfig.UserInfoEndpoint = realmURL + keycloakUserInfoPath
+	}
+
+	return setOIDC(rancherConfig, terraformConfig, &keycloakOIDCConfig, "Keycloak OIDC", newFile, rootBody, file)
+}
+
+// setOIDC is a helper function that will set the configurations of an OIDC auth provider in the main.tf file.
+func setOIDC(rancherConfig *rancher.Config, terraformConfig *config.TerraformConfig, oidcConfig *authproviders.OIDCConfig, providerName string, newFile *hclwrite.File,
+	rootBody *hclwrite.Body, file *os.File) error {
+	oidcBlock := rootBody.AppendNewBlock(resource, []string{genericOIDCConfig, genericOIDCConfig})
+	oidcBlockBody := oidcBlock.Body()
+
+	oidcBlockBody.SetAttributeValue(authEndpoint, cty.StringVal(oidcConfig.AuthEndpoint))
+	oidcBlockBody.SetAttributeValue(clientID, cty.StringVal(oidcConfig.ClientID))
+	oidcBlockBody.SetAttributeValue(clientSecret, cty.StringVal(oidcConfig.ClientSecret))
+
+	if oidcConfig.GroupsField != "" {
+		oidcBlockBody.SetAttributeValue(groupsField, cty.StringVal(oidcConfig.GroupsField))
+	}
+
+	oidcBlockBody.SetAttributeValue(issuer, cty.StringVal(oidcConfig.Issuer))
+	oidcBlockBody.SetAttributeValue(jwksURL, cty.StringVal(oidcConfig.JWKSURL))
+	oidcBlockBody.SetAttributeValue(rancherURL, cty.StringVal("https://"+rancherConfig.Host+verifyAuthCallback))
+
+	if oidcConfig.Scopes != "" {
+		oidcBlockBody.SetAttributeValue(scopes, cty.StringVal(oidcConfig.Scopes))
+	}
+
+	oidcBlockBody.SetAttributeValue(tokenEndpoint, cty.StringVal(oidcConfig.TokenEndpoint))
+	oidcBlockBody.SetAttributeValue(userInfoEndpoint, cty.StringVal(oidcConfig.UserInfoEndpoint))
+
+	access.SetAccess(oidcBlockBody, terraformConfig)
+
+	_, err := file.Write(newFile.Bytes())
+	if err != nil {
+		logrus.Infof("Failed to write %s configurations to main.tf file. Error: %v", providerName, err)
+		return err
+	}
+
+	return nil
+}
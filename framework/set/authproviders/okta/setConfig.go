@@ -6,6 +6,7 @@ import (
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/rancher/shepherd/clients/rancher"
 	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/framework/set/authproviders/access"
 	"github.com/sirupsen/logrus"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -39,6 +40,8 @@ func SetOkta(rancherConfig *rancher.Config, terraformConfig *config.TerraformCon
 	oktaBlockBody.SetAttributeValue(uidField, cty.StringVal(terraformConfig.OktaConfig.UIDField))
 	oktaBlockBody.SetAttributeValue(userNameField, cty.StringVal(terraformConfig.OktaConfig.UserNameField))
 
+	access.SetAccess(oktaBlockBody, terraformConfig)
+
 	_, err := file.Write(newFile.Bytes())
 	if err != nil {
 		logrus.Infof("Failed to write Okta configurations to main.tf file. Error: %v", err)
@@ -0,0 +1,79 @@
+package saml
+
+import (
+	"os"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/shepherd/clients/rancher"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/config/authproviders"
+	"github.com/rancher/tfp-automation/framework/set/authproviders/access"
+	"github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	adfsConfig     = "rancher2_auth_config_adfs"
+	keycloakConfig = "rancher2_auth_config_keycloak"
+	pingConfig     = "rancher2_auth_config_ping"
+
+	resource           = "resource"
+	displayNameField   = "display_name_field"
+	entityID           = "entity_id"
+	groupsField        = "groups_field"
+	idpMetadataContent = "idp_metadata_content"
+	rancherAPIHost     = "rancher_api_host"
+	spCert             = "sp_cert"
+	spKey              = "sp_key"
+	uidField           = "uid_field"
+	userNameField      = "user_name_field"
+)
+
+// SetADFS is a function that will set the ADFS configurations in the main.tf file.
+func SetADFS(rancherConfig *rancher.Config, terraformConfig *config.TerraformConfig, newFile *hclwrite.File,
+	rootBody *hclwrite.Body, file *os.File) error {
+	return setSAML(rancherConfig, terraformConfig, &terraformConfig.ADFSConfig, adfsConfig, "ADFS", newFile, rootBody, file)
+}
+
+// SetKeycloak is a function that will set the Keycloak SAML configurations in the main.tf file.
+func SetKeycloak(rancherConfig *rancher.Config, terraformConfig *config.TerraformConfig, newFile *hclwrite.File,
+	rootBody *hclwrite.Body, file *os.File) error {
+	return setSAML(rancherConfig, terraformConfig, &terraformConfig.KeycloakConfig, keycloakConfig, "Keycloak", newFile, rootBody, file)
+}
+
+// SetPing is a function that will set the Ping configurations in the main.tf file.
+func SetPing(rancherConfig *rancher.Config, terraformConfig *config.TerraformConfig, newFile *hclwrite.File,
+	rootBody *hclwrite.Body, file *os.File) error {
+	return setSAML(rancherConfig, terraformConfig, &terraformConfig.PingConfig, pingConfig, "Ping", newFile, rootBody, file)
+}
+
+// setSAML is a helper function that will set the configurations of a SAML auth provider in the main.tf file.
+func setSAML(rancherConfig *rancher.Config, terraformConfig *config.TerraformConfig, samlConfig *authproviders.SAMLConfig, authConfig, providerName string,
+	newFile *hclwrite.File, rootBody *hclwrite.Body, file *os.File) error {
+	samlBlock := rootBody.AppendNewBlock(resource, []string{authConfig, authConfig})
+	samlBlockBody := samlBlock.Body()
+
+	samlBlockBody.SetAttributeValue(displayNameField, cty.StringVal(samlConfig.DisplayNameField))
+
+	if samlConfig.EntityID != "" {
+		samlBlockBody.SetAttributeValue(entityID, cty.StringVal(samlConfig.EntityID))
+	}
+
+	samlBlockBody.SetAttributeValue(groupsField, cty.StringVal(samlConfig.GroupsField))
+	samlBlockBody.SetAttributeValue(idpMetadataContent, cty.StringVal(samlConfig.IdpMetadataContent))
+	samlBlockBody.SetAttributeValue(rancherAPIHost, cty.StringVal("https://"+rancherConfig.Host))
+	samlBlockBody.SetAttributeValue(spCert, cty.StringVal(samlConfig.SPCert))
+	samlBlockBody.SetAttributeValue(spKey, cty.StringVal(samlConfig.SPKey))
+	samlBlockBody.SetAttributeValue(uidField, cty.StringVal(samlConfig.UIDField))
+	samlBlockBody.SetAttributeValue(userNameField, cty.StringVal(samlConfig.UserNameField))
+
+	access.SetAccess(samlBlockBody, terraformConfig)
+
+	_, err := file.Write(newFile.Bytes())
+	if err != nil {
+		logrus.Infof("Failed to write %s configurations to main.tf file. Error: %v", providerName, err)
+		return err
+	}
+
+	return nil
+}
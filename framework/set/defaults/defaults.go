@@ -19,14 +19,14 @@ const (
 	Custom       = "custom"
 	Import       = "import"
 
-	Rancher2Source      = "rancher/rancher2"
-	Rancher2LocalSource = "terraform.local/local/rancher2"
+	Rancher2Source = "rancher/rancher2"
 
 	DefaultPodSecurityAdmission = "default_pod_security_admission_configuration_template_name"
 	PodSecurityAdmission        = "rancher2_pod_security_admission_configuration_template"
 	CloudCredential             = "rancher2_cloud_credential"
 	Cluster                     = "rancher2_cluster"
 	ClusterV2                   = "rancher2_cluster_v2"
+	MachineConfigV2             = "rancher2_machine_config_v2"
 	SecretV2                    = "rancher2_secret_v2"
 
 	AgentEnvVars                        = "agent_env_vars"
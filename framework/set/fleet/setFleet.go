@@ -0,0 +1,53 @@
+package fleet
+
+import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/framework/set/defaults"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	clusterID    = "cluster_id"
+	localCluster = "local"
+	namespace    = "fleet-default"
+	password     = "password"
+	secretType   = "kubernetes.io/basic-auth"
+	username     = "username"
+)
+
+// SetFleet is a function that will set the git credential secrets of the Fleet git repos declared in the terratest
+// config in the main.tf file. Git repos without a username are treated as public and do not get a secret.
+func SetFleet(newFile *hclwrite.File, rootBody *hclwrite.Body, terraformConfig *config.TerraformConfig,
+	fleet *config.Fleet) (*hclwrite.File, *hclwrite.Body, error) {
+	for _, gitRepo := range fleet.GitRepos {
+		if gitRepo.Username == "" {
+			continue
+		}
+
+		secretName := GitRepoSecretName(terraformConfig, gitRepo)
+
+		secretBlock := rootBody.AppendNewBlock(defaults.Resource, []string{defaults.SecretV2, secretName})
+		secretBlockBody := secretBlock.Body()
+
+		secretBlockBody.SetAttributeValue(clusterID, cty.StringVal(localCluster))
+		secretBlockBody.SetAttributeValue(defaults.ResourceName, cty.StringVal(secretName))
+		secretBlockBody.SetAttributeValue(defaults.Namespace, cty.StringVal(namespace))
+		secretBlockBody.SetAttributeValue(defaults.Type, cty.StringVal(secretType))
+
+		dataBlock := secretBlockBody.AppendNewBlock(defaults.Data+" =", nil)
+		dataBlockBody := dataBlock.Body()
+
+		dataBlockBody.SetAttributeValue(password, cty.StringVal(gitRepo.Password))
+		dataBlockBody.SetAttributeValue(username, cty.StringVal(gitRepo.Username))
+
+		rootBody.AppendNewline()
+	}
+
+	return newFile, rootBody, nil
+}
+
+// GitRepoSecretName is a function that will return the name of the git credential secret of the given git repo.
+func GitRepoSecretName(terraformConfig *config.TerraformConfig, gitRepo config.GitRepo) string {
+	return terraformConfig.ResourcePrefix + "-" + gitRepo.Name + "-auth"
+}
@@ -0,0 +1,101 @@
+package imports
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/tfp-automation/framework/set/defaults"
+	"github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	id            = "id"
+	ignoreChanges = "ignore_changes"
+	importBlock   = "import"
+	lifecycle     = "lifecycle"
+	to            = "to"
+)
+
+// Resource is an existing Rancher object that will be imported into the Terraform state. Attributes and Blocks must
+// describe the object as it exists in Rancher so that the plan is empty once it is imported. Write-only attributes,
+// such as passwords and secrets, should be listed in IgnoreChanges as they cannot be read back by the provider.
+type Resource struct {
+	Type          string
+	Name          string
+	ID            string
+	Attributes    map[string]cty.Value
+	Blocks        map[string]map[string]cty.Value
+	IgnoreChanges []string
+}
+
+// SetImports is a function that will set an import block and the matching resource block for each of the given
+// resources in the main.tf file.
+func SetImports(resources []Resource, newFile *hclwrite.File, rootBody *hclwrite.Body, file *os.File) error {
+	for _, resource := range resources {
+		address := hclwrite.Tokens{
+			{Type: hclsyntax.TokenIdent, Bytes: []byte(resource.Type + "." + resource.Name)},
+		}
+
+		importBlock := rootBody.AppendNewBlock(importBlock, nil)
+		importBlockBody := importBlock.Body()
+
+		importBlockBody.SetAttributeRaw(to, address)
+		importBlockBody.SetAttributeValue(id, cty.StringVal(resource.ID))
+
+		rootBody.AppendNewline()
+
+		resourceBlock := rootBody.AppendNewBlock(defaults.Resource, []string{resource.Type, resource.Name})
+		resourceBlockBody := resourceBlock.Body()
+
+		for _, attribute := range sortedKeys(resource.Attributes) {
+			resourceBlockBody.SetAttributeValue(attribute, resource.Attributes[attribute])
+		}
+
+		for _, blockName := range sortedKeys(resource.Blocks) {
+			nestedBlock := resourceBlockBody.AppendNewBlock(blockName, nil)
+			nestedBlockBody := nestedBlock.Body()
+
+			for _, attribute := range sortedKeys(resource.Blocks[blockName]) {
+				nestedBlockBody.SetAttributeValue(attribute, resource.Blocks[blockName][attribute])
+			}
+		}
+
+		if len(resource.IgnoreChanges) > 0 {
+			lifecycleBlock := resourceBlockBody.AppendNewBlock(lifecycle, nil)
+			lifecycleBlockBody := lifecycleBlock.Body()
+
+			ignored := hclwrite.Tokens{
+				{Type: hclsyntax.TokenIdent, Bytes: []byte("[" + strings.Join(resource.IgnoreChanges, ", ") + "]")},
+			}
+
+			lifecycleBlockBody.SetAttributeRaw(ignoreChanges, ignored)
+		}
+
+		rootBody.AppendNewline()
+	}
+
+	_, err := file.Write(newFile.Bytes())
+	if err != nil {
+		logrus.Infof("Failed to write import configurations to main.tf file. Error: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// sortedKeys is a helper function that will return the keys of the map in a stable order, so that the generated
+// main.tf does not change between runs.
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,139 @@
+package projects
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/framework/format"
+	"github.com/rancher/tfp-automation/framework/set/defaults"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	namespace = "rancher2_namespace"
+	project   = "rancher2_project"
+
+	clusterID              = "cluster_id"
+	containerResourceLimit = "container_resource_limit"
+	limit                  = "limit"
+	namespaceDefaultLimit  = "namespace_default_limit"
+	projectID              = "project_id"
+	projectLimit           = "project_limit"
+	resourceQuota          = "resource_quota"
+
+	configMaps             = "config_maps"
+	limitsCPU              = "limits_cpu"
+	limitsMemory           = "limits_memory"
+	persistentVolumeClaims = "persistent_volume_claims"
+	pods                   = "pods"
+	replicationControllers = "replication_controllers"
+	requestsCPU            = "requests_cpu"
+	requestsMemory         = "requests_memory"
+	requestsStorage        = "requests_storage"
+	secrets                = "secrets"
+	services               = "services"
+	servicesLoadBalancers  = "services_load_balancers"
+	servicesNodePorts      = "services_node_ports"
+)
+
+// SetProjects is a function that will set the projects declared in the terratest config, along with their resource
+// quotas, container default limits and namespaces, in the main.tf file. A namespace default limit is part of the
+// project resource quota, so it cannot be set without one.
+func SetProjects(newFile *hclwrite.File, rootBody *hclwrite.Body, terraformConfig *config.TerraformConfig, projects []config.Project,
+	isRKE1 bool) (*hclwrite.File, *hclwrite.Body, error) {
+	for _, tfpProject := range projects {
+		if tfpProject.NamespaceDefaultLimit != nil && tfpProject.ResourceQuota == nil {
+			return nil, nil, fmt.Errorf("project %s has a namespaceDefaultLimit without a resourceQuota", tfpProject.Name)
+		}
+	}
+
+	for _, tfpProject := range projects {
+		projectName := terraformConfig.ResourcePrefix + "-" + tfpProject.Name
+
+		projectBlock := rootBody.AppendNewBlock(defaults.Resource, []string{project, projectName})
+		projectBlockBody := projectBlock.Body()
+
+		projectBlockBody.SetAttributeValue(defaults.ResourceName, cty.StringVal(tfpProject.Name))
+
+		projectBlockBody.SetAttributeRaw(clusterID, format.ClusterID(terraformConfig.ResourcePrefix, isRKE1))
+
+		if tfpProject.ResourceQuota != nil {
+			resourceQuotaBlock := projectBlockBody.AppendNewBlock(resourceQuota, nil)
+			resourceQuotaBlockBody := resourceQuotaBlock.Body()
+
+			projectLimitBlock := resourceQuotaBlockBody.AppendNewBlock(projectLimit, nil)
+			setQuotaLimit(projectLimitBlock.Body(), tfpProject.ResourceQuota)
+
+			if tfpProject.NamespaceDefaultLimit != nil {
+				namespaceDefaultLimitBlock := resourceQuotaBlockBody.AppendNewBlock(namespaceDefaultLimit, nil)
+				setQuotaLimit(namespaceDefaultLimitBlock.Body(), tfpProject.NamespaceDefaultLimit)
+			}
+		}
+
+		if tfpProject.ContainerResourceLimit != nil {
+			containerResourceLimitBlock := projectBlockBody.AppendNewBlock(containerResourceLimit, nil)
+			setContainerResourceLimit(containerResourceLimitBlock.Body(), tfpProject.ContainerResourceLimit)
+		}
+
+		projectBlockBody.SetAttributeRaw(defaults.DependsOn, format.RawExpression(`[`+format.ClusterResource(terraformConfig.ResourcePrefix, isRKE1)+`]`))
+
+		rootBody.AppendNewline()
+
+		for _, tfpNamespace := range tfpProject.Namespaces {
+			namespaceBlock := rootBody.AppendNewBlock(defaults.Resource, []string{namespace, projectName + "-" + tfpNamespace.Name})
+			namespaceBlockBody := namespaceBlock.Body()
+
+			namespaceBlockBody.SetAttributeValue(defaults.ResourceName, cty.StringVal(tfpNamespace.Name))
+			namespaceBlockBody.SetAttributeRaw(projectID, format.RawExpression(project+"."+projectName+".id"))
+
+			if tfpNamespace.ResourceQuota != nil {
+				resourceQuotaBlock := namespaceBlockBody.AppendNewBlock(resourceQuota, nil)
+				limitBlock := resourceQuotaBlock.Body().AppendNewBlock(limit, nil)
+				setQuotaLimit(limitBlock.Body(), tfpNamespace.ResourceQuota)
+			}
+
+			if tfpNamespace.ContainerResourceLimit != nil {
+				containerResourceLimitBlock := namespaceBlockBody.AppendNewBlock(containerResourceLimit, nil)
+				setContainerResourceLimit(containerResourceLimitBlock.Body(), tfpNamespace.ContainerResourceLimit)
+			}
+
+			rootBody.AppendNewline()
+		}
+	}
+
+	return newFile, rootBody, nil
+}
+
+// setQuotaLimit is a helper function that will set the given resource quota limits, skipping the ones that are not set.
+func setQuotaLimit(body *hclwrite.Body, quotaLimit *management.ResourceQuotaLimit) {
+	setIfNotEmpty(body, configMaps, quotaLimit.ConfigMaps)
+	setIfNotEmpty(body, limitsCPU, quotaLimit.LimitsCPU)
+	setIfNotEmpty(body, limitsMemory, quotaLimit.LimitsMemory)
+	setIfNotEmpty(body, persistentVolumeClaims, quotaLimit.PersistentVolumeClaims)
+	setIfNotEmpty(body, pods, quotaLimit.Pods)
+	setIfNotEmpty(body, replicationControllers, quotaLimit.ReplicationControllers)
+	setIfNotEmpty(body, requestsCPU, quotaLimit.RequestsCPU)
+	setIfNotEmpty(body, requestsMemory, quotaLimit.RequestsMemory)
+	setIfNotEmpty(body, requestsStorage, quotaLimit.RequestsStorage)
+	setIfNotEmpty(body, secrets, quotaLimit.Secrets)
+	setIfNotEmpty(body, services, quotaLimit.Services)
+	setIfNotEmpty(body, servicesLoadBalancers, quotaLimit.ServicesLoadBalancers)
+	setIfNotEmpty(body, servicesNodePorts, quotaLimit.ServicesNodePorts)
+}
+
+// setContainerResourceLimit is a helper function that will set the given container default resource limits.
+func setContainerResourceLimit(body *hclwrite.Body, resourceLimit *management.ContainerResourceLimit) {
+	setIfNotEmpty(body, limitsCPU, resourceLimit.LimitsCPU)
+	setIfNotEmpty(body, limitsMemory, resourceLimit.LimitsMemory)
+	setIfNotEmpty(body, requestsCPU, resourceLimit.RequestsCPU)
+	setIfNotEmpty(body, requestsMemory, resourceLimit.RequestsMemory)
+}
+
+// setIfNotEmpty is a helper function that will set the attribute only when the value is not empty.
+func setIfNotEmpty(body *hclwrite.Body, attribute, value string) {
+	if value != "" {
+		body.SetAttributeValue(attribute, cty.StringVal(value))
+	}
+}
@@ -1,26 +1,19 @@
 package providers
 
 import (
-	"os"
-	"strings"
-
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/rancher/shepherd/clients/rancher"
 	"github.com/rancher/tfp-automation/config"
 	"github.com/rancher/tfp-automation/framework/set/defaults"
+	"github.com/rancher/tfp-automation/framework/versions"
 	"github.com/zclconf/go-cty/cty"
 )
 
 // SetCustomProviders is a helper function that will set the general Terraform provider configurations in the main.tf file.
 func SetCustomProviders(rancherConfig *rancher.Config, terraformConfig *config.TerraformConfig) (*hclwrite.File, *hclwrite.Body) {
-	awsProviderVersion := os.Getenv("AWS_PROVIDER_VERSION")
-	localProviderVersion := os.Getenv("LOCALS_PROVIDER_VERSION")
-	rancher2ProviderVersion := os.Getenv("RANCHER2_PROVIDER_VERSION")
-
-	rancher2Source := defaults.Rancher2Source
-	if strings.Contains(rancher2ProviderVersion, defaults.Rc) {
-		rancher2Source = defaults.Rancher2LocalSource
-	}
+	awsSource, awsProviderVersion := versions.GetProviderVersion(terraformConfig, defaults.Aws)
+	localSource, localProviderVersion := versions.GetProviderVersion(terraformConfig, defaults.Local)
+	rancher2Source, rancher2ProviderVersion := versions.GetProviderVersion(terraformConfig, defaults.Rancher2)
 
 	newFile := hclwrite.NewEmptyFile()
 	rootBody := newFile.Body()
@@ -32,12 +25,12 @@ func SetCustomProviders(rancherConfig *rancher.Config, terraformConfig *config.T
 	reqProvsBlockBody := reqProvsBlock.Body()
 
 	reqProvsBlockBody.SetAttributeValue(defaults.Aws, cty.ObjectVal(map[string]cty.Value{
-		defaults.Source:  cty.StringVal(defaults.AwsSource),
+		defaults.Source:  cty.StringVal(awsSource),
 		defaults.Version: cty.StringVal(awsProviderVersion),
 	}))
 
 	reqProvsBlockBody.SetAttributeValue(defaults.Local, cty.ObjectVal(map[string]cty.Value{
-		defaults.Source:  cty.StringVal(defaults.LocalSource),
+		defaults.Source:  cty.StringVal(localSource),
 		defaults.Version: cty.StringVal(localProviderVersion),
 	}))
 
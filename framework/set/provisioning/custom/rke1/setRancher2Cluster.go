@@ -4,6 +4,7 @@ import (
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/rancher/tfp-automation/config"
 	"github.com/rancher/tfp-automation/framework/set/defaults"
+	v2 "github.com/rancher/tfp-automation/framework/set/provisioning/nodedriver/rke2k3s"
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -14,6 +15,13 @@ func SetRancher2Cluster(rootBody *hclwrite.Body, terraformConfig *config.Terrafo
 
 	clusterBlockBody.SetAttributeValue(defaults.ResourceName, cty.StringVal(terraformConfig.ResourcePrefix))
 
+	if terraformConfig.AgentCustomization != nil {
+		err := v2.SetAgentCustomization(clusterBlockBody, terraformConfig)
+		if err != nil {
+			return err
+		}
+	}
+
 	rkeConfigBlock := clusterBlockBody.AppendNewBlock(defaults.RkeConfig, nil)
 	rkeConfigBlockBody := rkeConfigBlock.Body()
 
@@ -3,7 +3,6 @@ package rke2k3s
 import (
 	"strings"
 
-	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/rancher/tfp-automation/config"
@@ -25,14 +24,28 @@ func SetRancher2ClusterV2(rootBody *hclwrite.Body, terraformConfig *config.Terra
 		v2.SetProxyConfig(rancher2ClusterV2BlockBody, terraformConfig)
 	}
 
+	if terraformConfig.AgentCustomization != nil {
+		err := v2.SetAgentCustomization(rancher2ClusterV2BlockBody, terraformConfig)
+		if err != nil {
+			return err
+		}
+	}
+
 	rkeConfigBlock := rancher2ClusterV2BlockBody.AppendNewBlock(defaults.RkeConfig, nil)
 	rkeConfigBlockBody := rkeConfigBlock.Body()
 
+	machineGlobalConfig := map[string]any{}
 	if strings.Contains(terraformConfig.Module, "rke2") {
-		machineGlobalConfigValue := hclwrite.TokensForTraversal(hcl.Traversal{
-			hcl.TraverseRoot{Name: "<<EOF\ncni: " + terraformConfig.CNI + "\nEOF"},
-		})
-		rkeConfigBlockBody.SetAttributeRaw(defaults.MachineGlobalConfig, machineGlobalConfigValue)
+		machineGlobalConfig = v2.DefaultMachineGlobalConfig(terraformConfig)
+	}
+
+	err := v2.SetRKEConfig(rkeConfigBlockBody, terraformConfig, machineGlobalConfig)
+	if err != nil {
+		return err
+	}
+
+	if terraformConfig.UpgradeStrategy != nil {
+		v2.SetUpgradeStrategy(rkeConfigBlockBody, terraformConfig)
 	}
 
 	if terraformConfig.PrivateRegistries != nil {
@@ -33,6 +33,7 @@ const (
 	snapshot                = "snapshot"
 	s3BackupConfig          = "s3_backup_config"
 	bucketName              = "bucket_name"
+	customCA                = "custom_ca"
 	privateRegistryURL      = "url"
 	privateRegistryUsername = "user"
 	privateRegistryPassword = "password"
@@ -105,6 +106,13 @@ func SetRKE1(terraformConfig *config.TerraformConfig, k8sVersion, psact string,
 		v2.SetProxyConfig(clusterBlockBody, terraformConfig)
 	}
 
+	if terraformConfig.AgentCustomization != nil {
+		err := v2.SetAgentCustomization(clusterBlockBody, terraformConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	rkeConfigBlock := clusterBlockBody.AppendNewBlock(defaults.RkeConfig, nil)
 	rkeConfigBlockBody := rkeConfigBlock.Body()
 
@@ -145,6 +153,10 @@ func SetRKE1(terraformConfig *config.TerraformConfig, k8sVersion, psact string,
 
 	rootBody.AppendNewline()
 
+	if snapshots.CreateSnapshot {
+		setETCDBackup(rootBody, terraformConfig, snapshots)
+	}
+
 	_, err := file.Write(newFile.Bytes())
 	if err != nil {
 		logrus.Infof("Failed to write RKE1 configurations to main.tf file. Error: %v", err)
@@ -0,0 +1,56 @@
+package rke1
+
+import (
+	"strconv"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/framework/set/defaults"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	etcdBackup = "rancher2_etcd_backup"
+
+	clusterID = "cluster_id"
+	manual    = "manual"
+)
+
+// setETCDBackup is a function that will set a rancher2_etcd_backup resource in the main.tf file for each snapshot
+// generation of a RKE1 cluster. Every previous generation is kept, so that taking a new snapshot does not delete the
+// earlier ones.
+func setETCDBackup(rootBody *hclwrite.Body, terraformConfig *config.TerraformConfig, snapshots config.Snapshots) {
+	generation := snapshots.CreateSnapshotGeneration
+	if generation < 1 {
+		generation = 1
+	}
+
+	for i := int64(1); i <= generation; i++ {
+		backupName := ETCDBackupName(terraformConfig, i)
+
+		backupBlock := rootBody.AppendNewBlock(defaults.Resource, []string{etcdBackup, backupName})
+		backupBlockBody := backupBlock.Body()
+
+		dependsOn := hclwrite.Tokens{
+			{Type: hclsyntax.TokenIdent, Bytes: []byte("[" + clusterSync + "." + terraformConfig.ResourcePrefix + "]")},
+		}
+
+		backupBlockBody.SetAttributeRaw(defaults.DependsOn, dependsOn)
+
+		clusterBlockID := hclwrite.Tokens{
+			{Type: hclsyntax.TokenIdent, Bytes: []byte(defaults.Cluster + "." + terraformConfig.ResourcePrefix + ".id")},
+		}
+
+		backupBlockBody.SetAttributeRaw(clusterID, clusterBlockID)
+		backupBlockBody.SetAttributeValue(defaults.ResourceName, cty.StringVal(backupName))
+		backupBlockBody.SetAttributeValue(manual, cty.BoolVal(true))
+
+		rootBody.AppendNewline()
+	}
+}
+
+// ETCDBackupName is a function that will return the name of the etcd backup of the given snapshot generation.
+func ETCDBackupName(terraformConfig *config.TerraformConfig, generation int64) string {
+	return terraformConfig.ResourcePrefix + "-snapshot-" + strconv.FormatInt(generation, 10)
+}
@@ -21,16 +21,31 @@ func setEtcdConfig(servicesBlockBody *hclwrite.Body, terraformConfig *config.Ter
 	backupConfigBlockBody.SetAttributeValue(safeTimestamp, cty.BoolVal(terraformConfig.ETCDRKE1.BackupConfig.SafeTimestamp))
 	backupConfigBlockBody.SetAttributeValue(timeout, cty.NumberIntVal(terraformConfig.ETCDRKE1.BackupConfig.Timeout))
 
-	if terraformConfig.Module == modules.EC2RKE1 && terraformConfig.ETCDRKE1.BackupConfig.S3BackupConfig != nil {
+	s3Backup := terraformConfig.ETCDRKE1.BackupConfig.S3BackupConfig
+	if s3Backup != nil && (terraformConfig.Module == modules.EC2RKE1 || terraformConfig.S3Credentials != nil) {
 		s3ConfigBlock := backupConfigBlockBody.AppendNewBlock(s3BackupConfig, nil)
 		s3ConfigBlockBody := s3ConfigBlock.Body()
 
-		s3ConfigBlockBody.SetAttributeValue(defaults.AccessKey, cty.StringVal(terraformConfig.ETCDRKE1.BackupConfig.S3BackupConfig.AccessKey))
-		s3ConfigBlockBody.SetAttributeValue(bucketName, cty.StringVal(terraformConfig.ETCDRKE1.BackupConfig.S3BackupConfig.BucketName))
-		s3ConfigBlockBody.SetAttributeValue(defaults.Endpoint, cty.StringVal(terraformConfig.ETCDRKE1.BackupConfig.S3BackupConfig.Endpoint))
-		s3ConfigBlockBody.SetAttributeValue(defaults.Folder, cty.StringVal(terraformConfig.ETCDRKE1.BackupConfig.S3BackupConfig.Folder))
-		s3ConfigBlockBody.SetAttributeValue(defaults.Region, cty.StringVal(terraformConfig.ETCDRKE1.BackupConfig.S3BackupConfig.Region))
-		s3ConfigBlockBody.SetAttributeValue(defaults.SecretKey, cty.StringVal(terraformConfig.ETCDRKE1.BackupConfig.S3BackupConfig.SecretKey))
+		accessKey, secretKey, customCAValue := s3Backup.AccessKey, s3Backup.SecretKey, s3Backup.CustomCA
+		if terraformConfig.S3Credentials != nil {
+			accessKey, secretKey = terraformConfig.S3Credentials.AccessKey, terraformConfig.S3Credentials.SecretKey
+
+			if customCAValue == "" {
+				customCAValue = terraformConfig.S3Credentials.EndpointCA
+			}
+		}
+
+		s3ConfigBlockBody.SetAttributeValue(defaults.AccessKey, cty.StringVal(accessKey))
+		s3ConfigBlockBody.SetAttributeValue(bucketName, cty.StringVal(s3Backup.BucketName))
+
+		if customCAValue != "" {
+			s3ConfigBlockBody.SetAttributeValue(customCA, cty.StringVal(customCAValue))
+		}
+
+		s3ConfigBlockBody.SetAttributeValue(defaults.Endpoint, cty.StringVal(s3Backup.Endpoint))
+		s3ConfigBlockBody.SetAttributeValue(defaults.Folder, cty.StringVal(s3Backup.Folder))
+		s3ConfigBlockBody.SetAttributeValue(defaults.Region, cty.StringVal(s3Backup.Region))
+		s3ConfigBlockBody.SetAttributeValue(defaults.SecretKey, cty.StringVal(secretKey))
 	}
 
 	etcdBlockBody.SetAttributeValue(retention, cty.StringVal(terraformConfig.ETCDRKE1.Retention))
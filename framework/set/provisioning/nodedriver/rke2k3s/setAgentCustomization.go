@@ -0,0 +1,122 @@
+package rke2k3s
+
+import (
+	"encoding/json"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	provisioningv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/framework/set/defaults"
+	"github.com/zclconf/go-cty/cty"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	clusterAgentDeploymentCustomization = "cluster_agent_deployment_customization"
+	fleetAgentDeploymentCustomization   = "fleet_agent_deployment_customization"
+	appendTolerations                   = "append_tolerations"
+	overrideAffinity                    = "override_affinity"
+	overrideResourceRequirements        = "override_resource_requirements"
+
+	tolerationEffect   = "effect"
+	tolerationKey      = "key"
+	tolerationOperator = "operator"
+	tolerationSeconds  = "seconds"
+
+	cpuLimit      = "cpu_limit"
+	cpuRequest    = "cpu_request"
+	memoryLimit   = "memory_limit"
+	memoryRequest = "memory_request"
+)
+
+// SetAgentCustomization is a function that will set the agent environment variables and the cluster agent and fleet
+// agent deployment customizations in the main.tf file. The blocks are shared by the rancher2_cluster and
+// rancher2_cluster_v2 resources.
+func SetAgentCustomization(clusterBlockBody *hclwrite.Body, terraformConfig *config.TerraformConfig) error {
+	for _, envVar := range terraformConfig.AgentCustomization.AgentEnvVars {
+		agentEnvVarsBlock := clusterBlockBody.AppendNewBlock(defaults.AgentEnvVars, nil)
+		agentEnvVarsBlockBody := agentEnvVarsBlock.Body()
+
+		agentEnvVarsBlockBody.SetAttributeValue(defaults.ResourceName, cty.StringVal(envVar.Name))
+		agentEnvVarsBlockBody.SetAttributeValue(defaults.Value, cty.StringVal(envVar.Value))
+	}
+
+	if terraformConfig.AgentCustomization.ClusterAgent != nil {
+		err := setAgentDeploymentCustomization(clusterBlockBody, clusterAgentDeploymentCustomization, terraformConfig.AgentCustomization.ClusterAgent)
+		if err != nil {
+			return err
+		}
+	}
+
+	if terraformConfig.AgentCustomization.FleetAgent != nil {
+		err := setAgentDeploymentCustomization(clusterBlockBody, fleetAgentDeploymentCustomization, terraformConfig.AgentCustomization.FleetAgent)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setAgentDeploymentCustomization is a function that will set the tolerations, affinity and resource requirements of an
+// agent deployment in the main.tf file. The affinity is rendered as JSON, as expected by the provider.
+func setAgentDeploymentCustomization(clusterBlockBody *hclwrite.Body, blockName string, customization *provisioningv1.AgentDeploymentCustomization) error {
+	customizationBlock := clusterBlockBody.AppendNewBlock(blockName, nil)
+	customizationBlockBody := customizationBlock.Body()
+
+	for _, toleration := range customization.AppendTolerations {
+		tolerationBlock := customizationBlockBody.AppendNewBlock(appendTolerations, nil)
+		tolerationBlockBody := tolerationBlock.Body()
+
+		tolerationBlockBody.SetAttributeValue(tolerationKey, cty.StringVal(toleration.Key))
+
+		if toleration.Operator != "" {
+			tolerationBlockBody.SetAttributeValue(tolerationOperator, cty.StringVal(string(toleration.Operator)))
+		}
+
+		if toleration.Value != "" {
+			tolerationBlockBody.SetAttributeValue(defaults.Value, cty.StringVal(toleration.Value))
+		}
+
+		if toleration.Effect != "" {
+			tolerationBlockBody.SetAttributeValue(tolerationEffect, cty.StringVal(string(toleration.Effect)))
+		}
+
+		if toleration.TolerationSeconds != nil {
+			tolerationBlockBody.SetAttributeValue(tolerationSeconds, cty.NumberIntVal(*toleration.TolerationSeconds))
+		}
+	}
+
+	if customization.OverrideAffinity != nil {
+		affinity, err := json.Marshal(customization.OverrideAffinity)
+		if err != nil {
+			return err
+		}
+
+		customizationBlockBody.SetAttributeValue(overrideAffinity, cty.StringVal(string(affinity)))
+	}
+
+	if customization.OverrideResourceRequirements != nil {
+		resourcesBlock := customizationBlockBody.AppendNewBlock(overrideResourceRequirements, nil)
+		resourcesBlockBody := resourcesBlock.Body()
+
+		requirements := customization.OverrideResourceRequirements
+
+		setResourceQuantity(resourcesBlockBody, cpuLimit, requirements.Limits, corev1.ResourceCPU)
+		setResourceQuantity(resourcesBlockBody, cpuRequest, requirements.Requests, corev1.ResourceCPU)
+		setResourceQuantity(resourcesBlockBody, memoryLimit, requirements.Limits, corev1.ResourceMemory)
+		setResourceQuantity(resourcesBlockBody, memoryRequest, requirements.Requests, corev1.ResourceMemory)
+	}
+
+	return nil
+}
+
+// setResourceQuantity is a function that will set a resource requirement in the main.tf file if it is configured.
+func setResourceQuantity(resourcesBlockBody *hclwrite.Body, attribute string, resources corev1.ResourceList, resourceName corev1.ResourceName) {
+	quantity, ok := resources[resourceName]
+	if !ok {
+		return
+	}
+
+	resourcesBlockBody.SetAttributeValue(attribute, cty.StringVal(quantity.String()))
+}
@@ -77,6 +77,12 @@ func SetRKE2K3s(client *rancher.Client, terraformConfig *config.TerraformConfig,
 
 	rootBody.AppendNewline()
 
+	if usesS3Snapshots(terraformConfig) && terraformConfig.S3Credentials != nil {
+		setS3CloudCredential(rootBody, terraformConfig)
+
+		rootBody.AppendNewline()
+	}
+
 	if strings.Contains(psact, defaults.RancherBaseline) {
 		newFile, rootBody = resources.SetBaselinePSACT(newFile, rootBody, terraformConfig.ResourcePrefix)
 
@@ -125,6 +131,13 @@ func SetRKE2K3s(client *rancher.Client, terraformConfig *config.TerraformConfig,
 		SetProxyConfig(clusterBlockBody, terraformConfig)
 	}
 
+	if terraformConfig.AgentCustomization != nil {
+		err := SetAgentCustomization(clusterBlockBody, terraformConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	rkeConfigBlock := clusterBlockBody.AppendNewBlock(defaults.RkeConfig, nil)
 	rkeConfigBlockBody := rkeConfigBlock.Body()
 
@@ -136,11 +149,10 @@ func SetRKE2K3s(client *rancher.Client, terraformConfig *config.TerraformConfig,
 		rkeConfigBlockBody.SetAttributeRaw(defaults.ChartValues, chartValues)
 	}
 
-	machineGlobalConfigValue := hclwrite.TokensForTraversal(hcl.Traversal{
-		hcl.TraverseRoot{Name: "<<EOF\ncni: " + terraformConfig.CNI + "\ndisable-kube-proxy: " + terraformConfig.DisableKubeProxy + "\nEOF"},
-	})
-
-	rkeConfigBlockBody.SetAttributeRaw(defaults.MachineGlobalConfig, machineGlobalConfigValue)
+	err := SetRKEConfig(rkeConfigBlockBody, terraformConfig, DefaultMachineGlobalConfig(terraformConfig))
+	if err != nil {
+		return nil, nil, err
+	}
 
 	for count, pool := range nodePools {
 		setMachinePool(terraformConfig, count, pool, rkeConfigBlockBody)
@@ -160,12 +172,16 @@ func SetRKE2K3s(client *rancher.Client, terraformConfig *config.TerraformConfig,
 		SetPrivateRegistryConfig(registryBlockBody, terraformConfig)
 	}
 
+	if terraformConfig.UpgradeStrategy != nil {
+		SetUpgradeStrategy(rkeConfigBlockBody, terraformConfig)
+	}
+
 	if terraformConfig.ETCD != nil {
 		setEtcdConfig(rkeConfigBlockBody, terraformConfig)
 	}
 
 	if snapshots.CreateSnapshot {
-		SetCreateRKE2K3SSnapshot(terraformConfig, rkeConfigBlockBody)
+		SetCreateRKE2K3SSnapshot(terraformConfig, rkeConfigBlockBody, snapshots)
 	}
 
 	if snapshots.RestoreSnapshot {
@@ -174,7 +190,7 @@ func SetRKE2K3s(client *rancher.Client, terraformConfig *config.TerraformConfig,
 
 	rootBody.AppendNewline()
 
-	_, err := file.Write(newFile.Bytes())
+	_, err = file.Write(newFile.Bytes())
 	if err != nil {
 		logrus.Infof("Failed to write RKE2/K3s configurations to main.tf file. Error: %v", err)
 		return nil, nil, err
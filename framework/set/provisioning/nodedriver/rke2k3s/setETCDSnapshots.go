@@ -16,34 +16,32 @@ const (
 )
 
 // SetCreateRKE2K3SSnapshot is a function that will set the etcd_snapshot_create resource
-// block in the main.tf file for a RKE2/K3S cluster.
-func SetCreateRKE2K3SSnapshot(terraformConfig *config.TerraformConfig, rkeConfigBlockBody *hclwrite.Body) {
+// block in the main.tf file for a RKE2/K3S cluster. Rancher only takes a snapshot when the
+// generation changes, so the generation from the snapshot input is used, defaulting to 1.
+func SetCreateRKE2K3SSnapshot(terraformConfig *config.TerraformConfig, rkeConfigBlockBody *hclwrite.Body, snapshots config.Snapshots) {
 	createSnapshotBlock := rkeConfigBlockBody.AppendNewBlock(EtcdSnapshotCreate, nil)
 	createSnapshotBlockBody := createSnapshotBlock.Body()
 
-	generation := int64(1)
-
-	if createSnapshotBlockBody.GetAttribute(Generation) == nil {
-		createSnapshotBlockBody.SetAttributeValue(Generation, cty.NumberIntVal(generation))
-	} else {
-		createSnapshotBlockBody.SetAttributeValue(Generation, cty.NumberIntVal(generation+1))
-	}
+	createSnapshotBlockBody.SetAttributeValue(Generation, cty.NumberIntVal(snapshotGeneration(snapshots.CreateSnapshotGeneration)))
 }
 
 // SetRestoreRKE2K3SSnapshot is a function that will set the etcd_snapshot_restore
-// resource block in the main.tf file for a RKE2/K3S cluster.
+// resource block in the main.tf file for a RKE2/K3S cluster. Rancher only restores a snapshot
+// when the generation changes, so the generation from the snapshot input is used, defaulting to 1.
 func SetRestoreRKE2K3SSnapshot(terraformConfig *config.TerraformConfig, rkeConfigBlockBody *hclwrite.Body, snapshots config.Snapshots) {
 	restoreSnapshotBlock := rkeConfigBlockBody.AppendNewBlock(EtcdSnapshotRestore, nil)
 	restoreSnapshotBlockBody := restoreSnapshotBlock.Body()
 
-	generation := int64(1)
+	restoreSnapshotBlockBody.SetAttributeValue(Generation, cty.NumberIntVal(snapshotGeneration(snapshots.RestoreSnapshotGeneration)))
+	restoreSnapshotBlockBody.SetAttributeValue((defaults.ResourceName), cty.StringVal(snapshots.SnapshotName))
+	restoreSnapshotBlockBody.SetAttributeValue((RestoreRKEConfig), cty.StringVal(snapshots.SnapshotRestore))
+}
 
-	if restoreSnapshotBlockBody.GetAttribute(Generation) == nil {
-		restoreSnapshotBlockBody.SetAttributeValue(Generation, cty.NumberIntVal(generation))
-	} else {
-		restoreSnapshotBlockBody.SetAttributeValue(Generation, cty.NumberIntVal(generation+1))
+// snapshotGeneration is a helper function that will return the given generation, or 1 when it is not set.
+func snapshotGeneration(generation int64) int64 {
+	if generation < 1 {
+		return 1
 	}
 
-	restoreSnapshotBlockBody.SetAttributeValue((defaults.ResourceName), cty.StringVal(snapshots.SnapshotName))
-	restoreSnapshotBlockBody.SetAttributeValue((RestoreRKEConfig), cty.StringVal(snapshots.SnapshotRestore))
+	return generation
 }
@@ -20,12 +20,17 @@ func setEtcdConfig(rkeConfigBlockBody *hclwrite.Body, terraformConfig *config.Te
 	snapshotBlockBody.SetAttributeValue(snapshotScheduleCron, cty.StringVal(terraformConfig.ETCD.SnapshotScheduleCron))
 	snapshotBlockBody.SetAttributeValue(snapshotRetention, cty.NumberIntVal(int64(terraformConfig.ETCD.SnapshotRetention)))
 
-	if strings.Contains(terraformConfig.Module, modules.EC2) && terraformConfig.ETCD.S3 != nil {
+	if usesS3Snapshots(terraformConfig) {
 		s3ConfigBlock := snapshotBlockBody.AppendNewBlock(s3Config, nil)
 		s3ConfigBlockBody := s3ConfigBlock.Body()
 
+		cloudCredResourceName := terraformConfig.ResourcePrefix
+		if terraformConfig.S3Credentials != nil {
+			cloudCredResourceName = S3CloudCredentialName(terraformConfig)
+		}
+
 		cloudCredSecretName := hclwrite.Tokens{
-			{Type: hclsyntax.TokenIdent, Bytes: []byte(defaults.CloudCredential + "." + terraformConfig.ResourcePrefix + ".id")},
+			{Type: hclsyntax.TokenIdent, Bytes: []byte(defaults.CloudCredential + "." + cloudCredResourceName + ".id")},
 		}
 
 		s3ConfigBlockBody.SetAttributeValue(bucket, cty.StringVal(terraformConfig.ETCD.S3.Bucket))
@@ -39,3 +44,13 @@ func setEtcdConfig(rkeConfigBlockBody *hclwrite.Body, terraformConfig *config.Te
 
 	return nil
 }
+
+// usesS3Snapshots is a function that will determine if the etcd snapshots should be uploaded to S3. A dedicated S3
+// credential can be used with any provider, while the EC2 cloud credential is reused when no S3 credential is provided.
+func usesS3Snapshots(terraformConfig *config.TerraformConfig) bool {
+	if terraformConfig.ETCD == nil || terraformConfig.ETCD.S3 == nil {
+		return false
+	}
+
+	return terraformConfig.S3Credentials != nil || strings.Contains(terraformConfig.Module, modules.EC2)
+}
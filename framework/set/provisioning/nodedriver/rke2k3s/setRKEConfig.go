@@ -0,0 +1,255 @@
+package rke2k3s
+
+import (
+	"strconv"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/framework/set/defaults"
+	"github.com/zclconf/go-cty/cty"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	additionalManifest   = "additional_manifest"
+	localAuthEndpoint    = "local_auth_endpoint"
+	machineSelectorFiles = "machine_selector_files"
+	machineLabelSelector = "machine_label_selector"
+	matchExpressions     = "match_expressions"
+	matchLabels          = "match_labels"
+	selectorKey          = "key"
+	selectorOperator     = "operator"
+	selectorValues       = "values"
+
+	caCerts = "ca_certs"
+	fqdn    = "fqdn"
+
+	fileSources        = "file_sources"
+	fileSecret         = "secret"
+	fileConfigMap      = "configmap"
+	defaultPermissions = "default_permissions"
+	items              = "items"
+	itemDynamic        = "dynamic"
+	itemHash           = "hash"
+	itemKey            = "key"
+	itemPath           = "path"
+	itemPermissions    = "permissions"
+
+	cniKey              = "cni"
+	disableKubeProxyKey = "disable-kube-proxy"
+	etcdArgKey          = "etcd-arg"
+	etcdRoleLabel       = "rke.cattle.io/etcd-role"
+)
+
+// SetRKEConfig is a function that will set the machine global config and the rkeConfig section of the terraform config
+// in the rke_config block of the main.tf file. The given machine global config is the generated default, and is
+// overridden by the machineGlobalConfig keys of the rkeConfig section. Every config is serialized as YAML.
+func SetRKEConfig(rkeConfigBlockBody *hclwrite.Body, terraformConfig *config.TerraformConfig, machineGlobalConfig map[string]any) error {
+	rkeConfig := terraformConfig.RKEConfig
+	if rkeConfig == nil {
+		rkeConfig = &config.RKEConfig{}
+	}
+
+	for key, value := range rkeConfig.MachineGlobalConfig {
+		machineGlobalConfig[key] = value
+	}
+
+	if len(machineGlobalConfig) > 0 {
+		err := setYAMLAttribute(rkeConfigBlockBody, defaults.MachineGlobalConfig, machineGlobalConfig)
+		if err != nil {
+			return err
+		}
+	}
+
+	if rkeConfig.AdditionalManifest != "" {
+		rkeConfigBlockBody.SetAttributeValue(additionalManifest, cty.StringVal(rkeConfig.AdditionalManifest))
+	}
+
+	if rkeConfig.LocalAuthEndpoint != nil {
+		localAuthEndpointBlock := rkeConfigBlockBody.AppendNewBlock(localAuthEndpoint, nil)
+		localAuthEndpointBlockBody := localAuthEndpointBlock.Body()
+
+		localAuthEndpointBlockBody.SetAttributeValue(defaults.Enabled, cty.BoolVal(rkeConfig.LocalAuthEndpoint.Enabled))
+
+		if rkeConfig.LocalAuthEndpoint.FQDN != "" {
+			localAuthEndpointBlockBody.SetAttributeValue(fqdn, cty.StringVal(rkeConfig.LocalAuthEndpoint.FQDN))
+		}
+
+		if rkeConfig.LocalAuthEndpoint.CACerts != "" {
+			localAuthEndpointBlockBody.SetAttributeValue(caCerts, cty.StringVal(rkeConfig.LocalAuthEndpoint.CACerts))
+		}
+	}
+
+	if len(rkeConfig.ETCDArgs) > 0 {
+		err := setMachineSelectorConfig(rkeConfigBlockBody, ETCDArgsSelectorConfig(rkeConfig.ETCDArgs))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, selectorConfig := range rkeConfig.MachineSelectorConfig {
+		err := setMachineSelectorConfig(rkeConfigBlockBody, selectorConfig)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, selectorFiles := range rkeConfig.MachineSelectorFiles {
+		setMachineSelectorFiles(rkeConfigBlockBody, selectorFiles)
+	}
+
+	return nil
+}
+
+// DefaultMachineGlobalConfig is a function that will return the machine global config generated from the cni and
+// disable-kube-proxy fields of the terraform config. The disable-kube-proxy field is kept as a boolean when possible.
+func DefaultMachineGlobalConfig(terraformConfig *config.TerraformConfig) map[string]any {
+	machineGlobalConfig := map[string]any{}
+
+	if terraformConfig.CNI != "" {
+		machineGlobalConfig[cniKey] = terraformConfig.CNI
+	}
+
+	if terraformConfig.DisableKubeProxy != "" {
+		disableKubeProxy, err := strconv.ParseBool(terraformConfig.DisableKubeProxy)
+		if err != nil {
+			machineGlobalConfig[disableKubeProxyKey] = terraformConfig.DisableKubeProxy
+		} else {
+			machineGlobalConfig[disableKubeProxyKey] = disableKubeProxy
+		}
+	}
+
+	return machineGlobalConfig
+}
+
+// ETCDArgsSelectorConfig is a function that will return the machine selector config that passes the given etcd args to
+// the etcd nodes.
+func ETCDArgsSelectorConfig(etcdArgs []string) rkev1.RKESystemConfig {
+	return rkev1.RKESystemConfig{
+		MachineLabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{etcdRoleLabel: strconv.FormatBool(true)},
+		},
+		Config: rkev1.GenericMap{Data: map[string]any{etcdArgKey: etcdArgs}},
+	}
+}
+
+// setMachineSelectorConfig is a function that will set a machine selector config with its label selector in the
+// main.tf file.
+func setMachineSelectorConfig(rkeConfigBlockBody *hclwrite.Body, selectorConfig rkev1.RKESystemConfig) error {
+	machineSelectorBlock := rkeConfigBlockBody.AppendNewBlock(defaults.MachineSelectorConfig, nil)
+	machineSelectorBlockBody := machineSelectorBlock.Body()
+
+	if len(selectorConfig.Config.Data) > 0 {
+		err := setYAMLAttribute(machineSelectorBlockBody, defaults.Config, selectorConfig.Config.Data)
+		if err != nil {
+			return err
+		}
+	}
+
+	if selectorConfig.MachineLabelSelector != nil {
+		setMachineLabelSelector(machineSelectorBlockBody, selectorConfig.MachineLabelSelector)
+	}
+
+	return nil
+}
+
+// setMachineSelectorFiles is a function that will set the files of a machine selector, sourced from secrets or
+// configmaps, in the main.tf file.
+func setMachineSelectorFiles(rkeConfigBlockBody *hclwrite.Body, selectorFiles rkev1.RKEProvisioningFiles) {
+	machineSelectorFilesBlock := rkeConfigBlockBody.AppendNewBlock(machineSelectorFiles, nil)
+	machineSelectorFilesBlockBody := machineSelectorFilesBlock.Body()
+
+	for _, fileSource := range selectorFiles.FileSources {
+		fileSourcesBlock := machineSelectorFilesBlockBody.AppendNewBlock(fileSources, nil)
+		fileSourcesBlockBody := fileSourcesBlock.Body()
+
+		if fileSource.Secret.Name != "" {
+			setFileSource(fileSourcesBlockBody, fileSecret, fileSource.Secret)
+		}
+
+		if fileSource.ConfigMap.Name != "" {
+			setFileSource(fileSourcesBlockBody, fileConfigMap, fileSource.ConfigMap)
+		}
+	}
+
+	if selectorFiles.MachineLabelSelector != nil {
+		setMachineLabelSelector(machineSelectorFilesBlockBody, selectorFiles.MachineLabelSelector)
+	}
+}
+
+// setFileSource is a function that will set a secret or configmap file source and its items in the main.tf file.
+func setFileSource(fileSourcesBlockBody *hclwrite.Body, blockName string, fileSource rkev1.K8sObjectFileSource) {
+	fileSourceBlock := fileSourcesBlockBody.AppendNewBlock(blockName, nil)
+	fileSourceBlockBody := fileSourceBlock.Body()
+
+	fileSourceBlockBody.SetAttributeValue(defaults.ResourceName, cty.StringVal(fileSource.Name))
+
+	if fileSource.DefaultPermissions != "" {
+		fileSourceBlockBody.SetAttributeValue(defaultPermissions, cty.StringVal(fileSource.DefaultPermissions))
+	}
+
+	for _, item := range fileSource.Items {
+		itemBlock := fileSourceBlockBody.AppendNewBlock(items, nil)
+		itemBlockBody := itemBlock.Body()
+
+		itemBlockBody.SetAttributeValue(itemKey, cty.StringVal(item.Key))
+		itemBlockBody.SetAttributeValue(itemPath, cty.StringVal(item.Path))
+		itemBlockBody.SetAttributeValue(itemDynamic, cty.BoolVal(item.Dynamic))
+
+		if item.Permissions != "" {
+			itemBlockBody.SetAttributeValue(itemPermissions, cty.StringVal(item.Permissions))
+		}
+
+		if item.Hash != "" {
+			itemBlockBody.SetAttributeValue(itemHash, cty.StringVal(item.Hash))
+		}
+	}
+}
+
+// setMachineLabelSelector is a function that will set the match labels and match expressions of a machine label
+// selector in the main.tf file.
+func setMachineLabelSelector(blockBody *hclwrite.Body, labelSelector *metav1.LabelSelector) {
+	labelSelectorBlock := blockBody.AppendNewBlock(machineLabelSelector, nil)
+	labelSelectorBlockBody := labelSelectorBlock.Body()
+
+	if len(labelSelector.MatchLabels) > 0 {
+		labels := map[string]cty.Value{}
+		for key, value := range labelSelector.MatchLabels {
+			labels[key] = cty.StringVal(value)
+		}
+
+		labelSelectorBlockBody.SetAttributeValue(matchLabels, cty.MapVal(labels))
+	}
+
+	for _, expression := range labelSelector.MatchExpressions {
+		expressionBlock := labelSelectorBlockBody.AppendNewBlock(matchExpressions, nil)
+		expressionBlockBody := expressionBlock.Body()
+
+		expressionBlockBody.SetAttributeValue(selectorKey, cty.StringVal(expression.Key))
+		expressionBlockBody.SetAttributeValue(selectorOperator, cty.StringVal(string(expression.Operator)))
+
+		if len(expression.Values) > 0 {
+			var values []cty.Value
+			for _, value := range expression.Values {
+				values = append(values, cty.StringVal(value))
+			}
+
+			expressionBlockBody.SetAttributeValue(selectorValues, cty.ListVal(values))
+		}
+	}
+}
+
+// setYAMLAttribute is a function that will serialize the given config as YAML and set it as an attribute in the
+// main.tf file. Numbers are kept as integers, as the config is converted to YAML from its JSON representation.
+func setYAMLAttribute(blockBody *hclwrite.Body, attribute string, value any) error {
+	configYAML, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	blockBody.SetAttributeValue(attribute, cty.StringVal(string(configYAML)))
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package rke2k3s
+
+import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/framework/set/defaults"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	s3CredentialConfig   = "s3_credential_config"
+	s3CredentialSuffix   = "-s3"
+	defaultBucket        = "default_bucket"
+	defaultEndpoint      = "default_endpoint"
+	defaultEndpointCA    = "default_endpoint_ca"
+	defaultRegion        = "default_region"
+	defaultSkipSSLVerify = "default_skip_ssl_verify"
+)
+
+// S3CloudCredentialName is a function that will return the name of the dedicated S3 cloud credential of the cluster.
+func S3CloudCredentialName(terraformConfig *config.TerraformConfig) string {
+	return terraformConfig.ResourcePrefix + s3CredentialSuffix
+}
+
+// setS3CloudCredential is a function that will set the dedicated S3 cloud credential, used for etcd snapshots,
+// in the main.tf file. Unlike the node driver cloud credential, it can be used with any provider.
+func setS3CloudCredential(rootBody *hclwrite.Body, terraformConfig *config.TerraformConfig) {
+	s3CredentialName := S3CloudCredentialName(terraformConfig)
+
+	cloudCredBlock := rootBody.AppendNewBlock(defaults.Resource, []string{defaults.CloudCredential, s3CredentialName})
+	cloudCredBlockBody := cloudCredBlock.Body()
+
+	cloudCredBlockBody.SetAttributeValue(defaults.ResourceName, cty.StringVal(s3CredentialName))
+
+	s3CredBlock := cloudCredBlockBody.AppendNewBlock(s3CredentialConfig, nil)
+	s3CredBlockBody := s3CredBlock.Body()
+
+	s3CredBlockBody.SetAttributeValue(defaults.AccessKey, cty.StringVal(terraformConfig.S3Credentials.AccessKey))
+	s3CredBlockBody.SetAttributeValue(defaults.SecretKey, cty.StringVal(terraformConfig.S3Credentials.SecretKey))
+
+	if terraformConfig.S3Credentials.Bucket != "" {
+		s3CredBlockBody.SetAttributeValue(defaultBucket, cty.StringVal(terraformConfig.S3Credentials.Bucket))
+	}
+
+	if terraformConfig.S3Credentials.Endpoint != "" {
+		s3CredBlockBody.SetAttributeValue(defaultEndpoint, cty.StringVal(terraformConfig.S3Credentials.Endpoint))
+	}
+
+	if terraformConfig.S3Credentials.EndpointCA != "" {
+		s3CredBlockBody.SetAttributeValue(defaultEndpointCA, cty.StringVal(terraformConfig.S3Credentials.EndpointCA))
+	}
+
+	if terraformConfig.S3Credentials.Region != "" {
+		s3CredBlockBody.SetAttributeValue(defaultRegion, cty.StringVal(terraformConfig.S3Credentials.Region))
+	}
+
+	s3CredBlockBody.SetAttributeValue(defaultSkipSSLVerify, cty.BoolVal(terraformConfig.S3Credentials.SkipSSLVerify))
+}
@@ -0,0 +1,73 @@
+package rke2k3s
+
+import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/framework/set/defaults"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	controlPlaneDrainOptions        = "control_plane_drain_options"
+	workerDrainOptions              = "worker_drain_options"
+	deleteEmptyDirData              = "delete_empty_dir_data"
+	disableEviction                 = "disable_eviction"
+	force                           = "force"
+	gracePeriod                     = "grace_period"
+	ignoreDaemonSets                = "ignore_daemon_sets"
+	ignoreErrors                    = "ignore_errors"
+	skipWaitForDeleteTimeoutSeconds = "skip_wait_for_delete_timeout_seconds"
+	drainTimeout                    = "timeout"
+)
+
+// SetUpgradeStrategy is a function that will set the upgrade strategy configurations in the main.tf file.
+func SetUpgradeStrategy(rkeConfigBlockBody *hclwrite.Body, terraformConfig *config.TerraformConfig) {
+	upgradeStrategyBlock := rkeConfigBlockBody.AppendNewBlock(upgradeStrategy, nil)
+	upgradeStrategyBlockBody := upgradeStrategyBlock.Body()
+
+	if terraformConfig.UpgradeStrategy.ControlPlaneConcurrency != "" {
+		upgradeStrategyBlockBody.SetAttributeValue(controlPlaneConcurrency, cty.StringVal(terraformConfig.UpgradeStrategy.ControlPlaneConcurrency))
+	}
+
+	if terraformConfig.UpgradeStrategy.WorkerConcurrency != "" {
+		upgradeStrategyBlockBody.SetAttributeValue(workerConcurrency, cty.StringVal(terraformConfig.UpgradeStrategy.WorkerConcurrency))
+	}
+
+	if terraformConfig.UpgradeStrategy.ControlPlaneDrainOptions.Enabled {
+		setDrainOptions(upgradeStrategyBlockBody, controlPlaneDrainOptions, terraformConfig.UpgradeStrategy.ControlPlaneDrainOptions)
+	}
+
+	if terraformConfig.UpgradeStrategy.WorkerDrainOptions.Enabled {
+		setDrainOptions(upgradeStrategyBlockBody, workerDrainOptions, terraformConfig.UpgradeStrategy.WorkerDrainOptions)
+	}
+}
+
+// setDrainOptions is a function that will set the drain options of the upgrade strategy in the main.tf file. The grace
+// period, timeout and skip wait for delete timeout are only set when configured, so that the provider defaults apply.
+func setDrainOptions(upgradeStrategyBlockBody *hclwrite.Body, blockName string, drainOptions rkev1.DrainOptions) {
+	drainOptionsBlock := upgradeStrategyBlockBody.AppendNewBlock(blockName, nil)
+	drainOptionsBlockBody := drainOptionsBlock.Body()
+
+	drainOptionsBlockBody.SetAttributeValue(defaults.Enabled, cty.BoolVal(drainOptions.Enabled))
+	drainOptionsBlockBody.SetAttributeValue(deleteEmptyDirData, cty.BoolVal(drainOptions.DeleteEmptyDirData))
+	drainOptionsBlockBody.SetAttributeValue(disableEviction, cty.BoolVal(drainOptions.DisableEviction))
+	drainOptionsBlockBody.SetAttributeValue(force, cty.BoolVal(drainOptions.Force))
+	drainOptionsBlockBody.SetAttributeValue(ignoreErrors, cty.BoolVal(drainOptions.IgnoreErrors))
+
+	if drainOptions.IgnoreDaemonSets != nil {
+		drainOptionsBlockBody.SetAttributeValue(ignoreDaemonSets, cty.BoolVal(*drainOptions.IgnoreDaemonSets))
+	}
+
+	if drainOptions.GracePeriod != 0 {
+		drainOptionsBlockBody.SetAttributeValue(gracePeriod, cty.NumberIntVal(int64(drainOptions.GracePeriod)))
+	}
+
+	if drainOptions.Timeout != 0 {
+		drainOptionsBlockBody.SetAttributeValue(drainTimeout, cty.NumberIntVal(int64(drainOptions.Timeout)))
+	}
+
+	if drainOptions.SkipWaitForDeleteTimeoutSeconds != 0 {
+		drainOptionsBlockBody.SetAttributeValue(skipWaitForDeleteTimeoutSeconds, cty.NumberIntVal(int64(drainOptions.SkipWaitForDeleteTimeoutSeconds)))
+	}
+}
@@ -0,0 +1,209 @@
+package rbac
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/framework/format"
+	"github.com/rancher/tfp-automation/framework/set/defaults"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	globalRole   = "rancher2_global_role"
+	roleTemplate = "rancher2_role_template"
+
+	apiGroups        = "api_groups"
+	context          = "context"
+	groupPrincipalID = "group_principal_id"
+	newUserDefault   = "new_user_default"
+	nonResourceURLs  = "non_resource_urls"
+	resourceNames    = "resource_names"
+	resources        = "resources"
+	roleTemplateIDs  = "role_template_ids"
+	rules            = "rules"
+	verbs            = "verbs"
+)
+
+// SetRBAC is a function that will set the role templates, global roles and bindings declared in the terratest rbac
+// config in the main.tf file. Bindings are made to the test user, unless a group principal is given.
+func SetRBAC(newFile *hclwrite.File, rootBody *hclwrite.Body, file *os.File, terraformConfig *config.TerraformConfig,
+	rbacConfig *config.RBAC, isRKE1 bool) (*hclwrite.File, *hclwrite.Body, error) {
+	roleTemplates := map[string]bool{}
+	for _, template := range rbacConfig.RoleTemplates {
+		setRoleTemplate(rootBody, terraformConfig, template)
+		roleTemplates[template.Name] = true
+	}
+
+	globalRoles := map[string]bool{}
+	for _, role := range rbacConfig.GlobalRoles {
+		setGlobalRole(rootBody, terraformConfig, role)
+		globalRoles[role.Name] = true
+	}
+
+	projects := map[string]bool{}
+
+	for _, binding := range rbacConfig.Bindings {
+		bindingName := terraformConfig.ResourcePrefix + "-" + binding.Name
+
+		var bindingBlockBody *hclwrite.Body
+		var dependsOn []string
+
+		switch binding.Scope {
+		case config.GlobalScope:
+			bindingBlock := rootBody.AppendNewBlock(defaults.Resource, []string{globalRoleBinding, bindingName})
+			bindingBlockBody = bindingBlock.Body()
+
+			if globalRoles[binding.Role] {
+				globalRoleName := terraformConfig.ResourcePrefix + "-" + binding.Role
+				bindingBlockBody.SetAttributeRaw(globalRoleID, format.RawExpression(globalRole+"."+globalRoleName+".id"))
+				dependsOn = append(dependsOn, globalRole+"."+globalRoleName)
+			} else {
+				bindingBlockBody.SetAttributeValue(globalRoleID, cty.StringVal(binding.Role))
+			}
+		case config.ClusterScope:
+			bindingBlock := rootBody.AppendNewBlock(defaults.Resource, []string{clusterRoleTemplateBinding, bindingName})
+			bindingBlockBody = bindingBlock.Body()
+
+			bindingBlockBody.SetAttributeRaw(clusterID, format.ClusterID(terraformConfig.ResourcePrefix, isRKE1))
+
+			dependsOn = append(dependsOn, format.ClusterResource(terraformConfig.ResourcePrefix, isRKE1))
+		case config.ProjectScope:
+			projectLabel := binding.Project
+			if projectLabel == "" {
+				projectLabel = projectName
+			}
+
+			projectResource := terraformConfig.ResourcePrefix + "-" + projectLabel
+
+			if !projects[projectLabel] {
+				projectBlock := rootBody.AppendNewBlock(defaults.Resource, []string{project, projectResource})
+				projectBlockBody := projectBlock.Body()
+
+				projectBlockBody.SetAttributeValue(defaults.ResourceName, cty.StringVal(projectLabel))
+
+				projectBlockBody.SetAttributeRaw(clusterID, format.ClusterID(terraformConfig.ResourcePrefix, isRKE1))
+				projectBlockBody.SetAttributeRaw(defaults.DependsOn, format.RawExpression(`[`+format.ClusterResource(terraformConfig.ResourcePrefix, isRKE1)+`]`))
+				rootBody.AppendNewline()
+
+				projects[projectLabel] = true
+			}
+
+			bindingBlock := rootBody.AppendNewBlock(defaults.Resource, []string{projectRoleTemplateBinding, bindingName})
+			bindingBlockBody = bindingBlock.Body()
+
+			bindingBlockBody.SetAttributeRaw(projectID, format.RawExpression(project+"."+projectResource+".id"))
+			dependsOn = append(dependsOn, project+"."+projectResource)
+		default:
+			return nil, nil, fmt.Errorf("unsupported scope %q for binding %s", binding.Scope, binding.Name)
+		}
+
+		bindingBlockBody.SetAttributeValue(defaults.ResourceName, cty.StringVal(bindingName))
+
+		if binding.Scope != config.GlobalScope {
+			if roleTemplates[binding.Role] {
+				roleTemplateName := terraformConfig.ResourcePrefix + "-" + binding.Role
+				bindingBlockBody.SetAttributeRaw(roleTemplateID, format.RawExpression(roleTemplate+"."+roleTemplateName+".id"))
+				dependsOn = append(dependsOn, roleTemplate+"."+roleTemplateName)
+			} else {
+				bindingBlockBody.SetAttributeValue(roleTemplateID, cty.StringVal(binding.Role))
+			}
+		}
+
+		if binding.GroupPrincipalID != "" {
+			bindingBlockBody.SetAttributeValue(groupPrincipalID, cty.StringVal(binding.GroupPrincipalID))
+		} else {
+			bindingBlockBody.SetAttributeRaw(userID, format.RawExpression(rancherUser+"."+rancherUser+".id"))
+		}
+
+		if len(dependsOn) > 0 {
+			dependsOnValue := `[`
+			for i, resource := range dependsOn {
+				if i > 0 {
+					dependsOnValue += `, `
+				}
+
+				dependsOnValue += resource
+			}
+
+			bindingBlockBody.SetAttributeRaw(defaults.DependsOn, format.RawExpression(dependsOnValue+`]`))
+		}
+
+		rootBody.AppendNewline()
+	}
+
+	return newFile, rootBody, nil
+}
+
+// setRoleTemplate is a helper function that will set a custom role template in the main.tf file.
+func setRoleTemplate(rootBody *hclwrite.Body, terraformConfig *config.TerraformConfig, template config.RoleTemplate) {
+	roleTemplateBlock := rootBody.AppendNewBlock(defaults.Resource, []string{roleTemplate, terraformConfig.ResourcePrefix + "-" + template.Name})
+	roleTemplateBlockBody := roleTemplateBlock.Body()
+
+	roleTemplateBlockBody.SetAttributeValue(defaults.ResourceName, cty.StringVal(template.Name))
+
+	if template.Context != "" {
+		roleTemplateBlockBody.SetAttributeValue(context, cty.StringVal(template.Context))
+	}
+
+	if len(template.RoleTemplateIDs) > 0 {
+		roleTemplateBlockBody.SetAttributeValue(roleTemplateIDs, stringList(template.RoleTemplateIDs))
+	}
+
+	setRules(roleTemplateBlockBody, template.Rules)
+
+	rootBody.AppendNewline()
+}
+
+// setGlobalRole is a helper function that will set a custom global role in the main.tf file.
+func setGlobalRole(rootBody *hclwrite.Body, terraformConfig *config.TerraformConfig, role config.GlobalRole) {
+	globalRoleBlock := rootBody.AppendNewBlock(defaults.Resource, []string{globalRole, terraformConfig.ResourcePrefix + "-" + role.Name})
+	globalRoleBlockBody := globalRoleBlock.Body()
+
+	globalRoleBlockBody.SetAttributeValue(defaults.ResourceName, cty.StringVal(role.Name))
+	globalRoleBlockBody.SetAttributeValue(newUserDefault, cty.BoolVal(role.NewUserDefault))
+
+	setRules(globalRoleBlockBody, role.Rules)
+
+	rootBody.AppendNewline()
+}
+
+// setRules is a helper function that will set the policy rules of a role template or global role.
+func setRules(body *hclwrite.Body, policyRules []config.RBACRule) {
+	for _, rule := range policyRules {
+		rulesBlock := body.AppendNewBlock(rules, nil)
+		rulesBlockBody := rulesBlock.Body()
+
+		if len(rule.APIGroups) > 0 {
+			rulesBlockBody.SetAttributeValue(apiGroups, stringList(rule.APIGroups))
+		}
+
+		if len(rule.NonResourceURLs) > 0 {
+			rulesBlockBody.SetAttributeValue(nonResourceURLs, stringList(rule.NonResourceURLs))
+		}
+
+		if len(rule.ResourceNames) > 0 {
+			rulesBlockBody.SetAttributeValue(resourceNames, stringList(rule.ResourceNames))
+		}
+
+		if len(rule.Resources) > 0 {
+			rulesBlockBody.SetAttributeValue(resources, stringList(rule.Resources))
+		}
+
+		if len(rule.Verbs) > 0 {
+			rulesBlockBody.SetAttributeValue(verbs, stringList(rule.Verbs))
+		}
+	}
+}
+
+// stringList is a helper function that will convert a slice of strings into a cty list.
+func stringList(values []string) cty.Value {
+	var listValues []cty.Value
+	for _, value := range values {
+		listValues = append(listValues, cty.StringVal(value))
+	}
+
+	return cty.ListVal(listValues)
+}
@@ -0,0 +1,77 @@
+#!/bin/bash
+
+BACKUP_NAME=$1
+CHART_REPO=$2
+RESOURCE_SET=$3
+CHART_VERSION=$4
+S3_ENDPOINT=$5
+S3_BUCKET=$6
+S3_REGION=$7
+S3_ACCESS_KEY=$8
+S3_SECRET_KEY=$9
+S3_SKIP_TLS_VERIFY=${10}
+
+set -ex
+
+NAMESPACE=cattle-resources-system
+S3_SECRET=rancher-backup-s3
+CHART_VERSION_CONFIGMAP=rancher-chart-version
+LOCAL_PATH_VERSION=v0.0.31
+
+if [ -n "${CHART_VERSION}" ]; then
+    VERSION_FLAG="--version ${CHART_VERSION}"
+fi
+
+echo "Adding rancher-backup Helm chart repo"
+helm repo add rancher-charts ${CHART_REPO} --force-update
+helm repo update
+
+echo "Installing rancher-backup CRDs"
+helm upgrade --install rancher-backup-crd rancher-charts/rancher-backup-crd --namespace ${NAMESPACE} --create-namespace ${VERSION_FLAG} --wait
+
+if [ -n "${S3_ENDPOINT}" ]; then
+    echo "Creating S3 credential secret"
+    kubectl -n ${NAMESPACE} create secret generic ${S3_SECRET} --from-literal=accessKey=${S3_ACCESS_KEY} \
+                                                               --from-literal=secretKey=${S3_SECRET_KEY} \
+                                                               --dry-run=client -o yaml | kubectl apply -f -
+
+    echo "Installing rancher-backup with S3 storage"
+    helm upgrade --install rancher-backup rancher-charts/rancher-backup --namespace ${NAMESPACE} ${VERSION_FLAG} \
+                                                                        --set s3.enabled=true \
+                                                                        --set s3.credentialSecretName=${S3_SECRET} \
+                                                                        --set s3.credentialSecretNamespace=${NAMESPACE} \
+                                                                        --set s3.bucketName=${S3_BUCKET} \
+                                                                        --set s3.folder=rancher-backup \
+                                                                        --set s3.region=${S3_REGION} \
+                                                                        --set s3.endpoint=${S3_ENDPOINT} \
+                                                                        --set s3.insecureTLSSkipVerify=${S3_SKIP_TLS_VERIFY} \
+                                                                        --wait
+else
+    echo "Installing local-path provisioner"
+    kubectl apply -f https://raw.githubusercontent.com/rancher/local-path-provisioner/${LOCAL_PATH_VERSION}/deploy/local-path-storage.yaml
+
+    echo "Installing rancher-backup with persistent volume storage"
+    helm upgrade --install rancher-backup rancher-charts/rancher-backup --namespace ${NAMESPACE} ${VERSION_FLAG} \
+                                                                        --set persistence.enabled=true \
+                                                                        --set persistence.storageClass=local-path \
+                                                                        --wait
+fi
+
+echo "Recording the Rancher chart version"
+RANCHER_CHART=$(helm list -n cattle-system --filter ^rancher$ -o json | grep -o "\"chart\":\"rancher-[^\"]*\"" | cut -d\" -f4)
+kubectl -n ${NAMESPACE} create configmap ${CHART_VERSION_CONFIGMAP} --from-literal=version=${RANCHER_CHART#rancher-} \
+                                                                   --dry-run=client -o yaml | kubectl apply -f -
+
+echo "Creating backup ${BACKUP_NAME}"
+kubectl apply -f - <<BACKUP
+apiVersion: resources.cattle.io/v1
+kind: Backup
+metadata:
+  name: ${BACKUP_NAME}
+spec:
+  resourceSetName: ${RESOURCE_SET}
+BACKUP
+
+echo "Waiting for backup ${BACKUP_NAME} to complete"
+kubectl wait --for=condition=Ready backups.resources.cattle.io/${BACKUP_NAME} --timeout=15m
+kubectl get backups.resources.cattle.io ${BACKUP_NAME} -o jsonpath={.status.filename}
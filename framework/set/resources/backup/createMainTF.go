@@ -0,0 +1,78 @@
+package backup
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/framework/set/resources/providers/aws"
+	"github.com/rancher/tfp-automation/framework/set/resources/sanity"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	terraformConst = "terraform"
+)
+
+// CreateBackup is a helper function that will create the main.tf file for backing up the Rancher server with the
+// rancher-backup operator.
+func CreateBackup(t *testing.T, terraformOptions *terraform.Options, keyPath string, terraformConfig *config.TerraformConfig,
+	serverNode, backupName string) error {
+	var file *os.File
+	file = sanity.OpenFile(file, keyPath)
+	defer file.Close()
+
+	newFile, rootBody := createProviderBlocks(terraformConfig)
+
+	file = sanity.OpenFile(file, keyPath)
+	logrus.Infof("Backing up Rancher to %s...", backupName)
+	_, err := CreateRancherBackup(file, newFile, rootBody, terraformConfig, serverNode, backupName)
+	if err != nil {
+		return err
+	}
+
+	terraform.InitAndApply(t, terraformOptions)
+
+	return nil
+}
+
+// RestoreBackup is a helper function that will create the main.tf file for restoring the Rancher server from a backup
+// and rolling it back to the version it was originally installed with.
+func RestoreBackup(t *testing.T, terraformOptions *terraform.Options, keyPath string, terraformConfig *config.TerraformConfig,
+	serverNode, backupName string) error {
+	var file *os.File
+	file = sanity.OpenFile(file, keyPath)
+	defer file.Close()
+
+	newFile, rootBody := createProviderBlocks(terraformConfig)
+
+	file = sanity.OpenFile(file, keyPath)
+	logrus.Infof("Restoring Rancher from %s...", backupName)
+	_, err := RestoreRancherBackup(file, newFile, rootBody, terraformConfig, serverNode, backupName)
+	if err != nil {
+		return err
+	}
+
+	terraform.InitAndApply(t, terraformOptions)
+
+	return nil
+}
+
+// createProviderBlocks is a helper function that will create the terraform and provider blocks of the main.tf file.
+func createProviderBlocks(terraformConfig *config.TerraformConfig) (*hclwrite.File, *hclwrite.Body) {
+	newFile := hclwrite.NewEmptyFile()
+	rootBody := newFile.Body()
+
+	tfBlock := rootBody.AppendNewBlock(terraformConst, nil)
+	tfBlockBody := tfBlock.Body()
+
+	aws.CreateAWSTerraformProviderBlock(tfBlockBody, terraformConfig)
+	rootBody.AppendNewline()
+
+	aws.CreateAWSProviderBlock(rootBody, terraformConfig)
+	rootBody.AppendNewline()
+
+	return newFile, rootBody
+}
@@ -0,0 +1,134 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/framework/set/defaults"
+	"github.com/rancher/tfp-automation/framework/set/resources/rke2"
+	"github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	createBackup  = "create_backup"
+	restoreBackup = "restore_backup"
+
+	defaultChartRepository = "https://charts.rancher.io"
+	defaultResourceSetName = "rancher-resource-set-full"
+)
+
+// CreateRancherBackup is a function that will install the rancher-backup operator on the Rancher server and take a
+// backup with the given name. The backup is stored in the standalone MinIO bucket when MinIO is configured, and in a
+// local-path persistent volume otherwise.
+func CreateRancherBackup(file *os.File, newFile *hclwrite.File, rootBody *hclwrite.Body, terraformConfig *config.TerraformConfig,
+	rke2ServerOnePublicIP, backupName string) (*os.File, error) {
+	scriptContent, err := readScript("backup.sh")
+	if err != nil {
+		return nil, err
+	}
+
+	chartRepository := defaultChartRepository
+	resourceSetName := defaultResourceSetName
+	chartVersion := ""
+
+	if terraformConfig.StandaloneBackup != nil {
+		if terraformConfig.StandaloneBackup.ChartRepository != "" {
+			chartRepository = terraformConfig.StandaloneBackup.ChartRepository
+		}
+
+		if terraformConfig.StandaloneBackup.ResourceSetName != "" {
+			resourceSetName = terraformConfig.StandaloneBackup.ResourceSetName
+		}
+
+		chartVersion = terraformConfig.StandaloneBackup.ChartVersion
+	}
+
+	_, provisionerBlockBody := rke2.CreateNullResource(rootBody, terraformConfig, rke2ServerOnePublicIP, createBackup)
+
+	command := "bash -c '/tmp/backup.sh " + backupName + " " + chartRepository + " " + resourceSetName + " \"" + chartVersion + "\""
+
+	if terraformConfig.StandaloneMinIO != nil && terraformConfig.S3Credentials != nil && terraformConfig.S3Credentials.Endpoint != "" {
+		s3Credentials := terraformConfig.S3Credentials
+
+		skipTLSVerify := "false"
+		if s3Credentials.SkipSSLVerify {
+			skipTLSVerify = "true"
+		}
+
+		command += " " + s3Credentials.Endpoint + " " + terraformConfig.StandaloneMinIO.Bucket + " \"" + s3Credentials.Region + "\" " +
+			s3Credentials.AccessKey + " " + s3Credentials.SecretKey + " " + skipTLSVerify
+	}
+
+	command += "'"
+
+	provisionerBlockBody.SetAttributeValue(defaults.Inline, cty.ListVal([]cty.Value{
+		cty.StringVal("echo '" + string(scriptContent) + "' > /tmp/backup.sh"),
+		cty.StringVal("chmod +x /tmp/backup.sh"),
+		cty.StringVal(command),
+	}))
+
+	_, err = file.Write(newFile.Bytes())
+	if err != nil {
+		logrus.Infof("Failed to append configurations to main.tf file. Error: %v", err)
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// RestoreRancherBackup is a function that will scale down Rancher, restore the backup with the given name and reinstall
+// the Rancher chart with the version Rancher was originally installed with.
+func RestoreRancherBackup(file *os.File, newFile *hclwrite.File, rootBody *hclwrite.Body, terraformConfig *config.TerraformConfig,
+	rke2ServerOnePublicIP, backupName string) (*os.File, error) {
+	scriptContent, err := readScript("restore.sh")
+	if err != nil {
+		return nil, err
+	}
+
+	_, provisionerBlockBody := rke2.CreateNullResource(rootBody, terraformConfig, rke2ServerOnePublicIP, restoreBackup)
+
+	command := "bash -c '/tmp/restore.sh " + backupName + " " + terraformConfig.Standalone.RancherChartRepository + " " +
+		terraformConfig.Standalone.Repo + " " + terraformConfig.Standalone.RancherHostname + " " +
+		terraformConfig.Standalone.RancherTagVersion + " " + terraformConfig.Standalone.RancherImage
+
+	if terraformConfig.Standalone.RancherAgentImage != "" {
+		command += " " + terraformConfig.Standalone.RancherAgentImage
+	}
+
+	command += "'"
+
+	provisionerBlockBody.SetAttributeValue(defaults.Inline, cty.ListVal([]cty.Value{
+		cty.StringVal("echo '" + string(scriptContent) + "' > /tmp/restore.sh"),
+		cty.StringVal("chmod +x /tmp/restore.sh"),
+		cty.StringVal(command),
+	}))
+
+	_, err = file.Write(newFile.Bytes())
+	if err != nil {
+		logrus.Infof("Failed to append configurations to main.tf file. Error: %v", err)
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// readScript is a helper function that will read the given script of the backup package.
+func readScript(script string) ([]byte, error) {
+	var err error
+	userDir := os.Getenv("GOROOT")
+	if userDir == "" {
+		userDir, err = os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+
+		userDir = filepath.Join(userDir, "go/")
+	}
+
+	scriptPath := filepath.Join(userDir, "src/github.com/rancher/tfp-automation/framework/set/resources/backup", script)
+
+	return os.ReadFile(scriptPath)
+}
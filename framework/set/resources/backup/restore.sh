@@ -0,0 +1,68 @@
+#!/bin/bash
+
+BACKUP_NAME=$1
+RANCHER_CHART_REPO=$2
+REPO=$3
+HOSTNAME=$4
+RANCHER_TAG_VERSION=$5
+RANCHER_IMAGE=$6
+RANCHER_AGENT_IMAGE=${7}
+
+set -ex
+
+RESTORE_NAME=restore-${BACKUP_NAME}
+BACKUP_FILE=$(kubectl get backups.resources.cattle.io ${BACKUP_NAME} -o jsonpath={.status.filename})
+RANCHER_CHART_VERSION=$(kubectl -n cattle-resources-system get configmap rancher-chart-version -o jsonpath={.data.version})
+
+if [ -z "${RANCHER_CHART_VERSION}" ]; then
+    echo "The Rancher chart version was not recorded by the backup"
+    exit 1
+fi
+
+echo "Scaling down Rancher"
+kubectl -n cattle-system scale --replicas=0 deployment/rancher
+while kubectl -n cattle-system get pods -l app=rancher -o name | grep -q pod; do
+    sleep 5
+done
+
+echo "Restoring backup ${BACKUP_FILE}"
+kubectl apply -f - <<RESTORE
+apiVersion: resources.cattle.io/v1
+kind: Restore
+metadata:
+  name: ${RESTORE_NAME}
+spec:
+  backupFilename: ${BACKUP_FILE}
+  prune: false
+RESTORE
+
+echo "Waiting for restore ${RESTORE_NAME} to complete"
+kubectl wait --for=condition=Ready restores.resources.cattle.io/${RESTORE_NAME} --timeout=30m
+
+echo "Adding Helm chart repo"
+helm repo add rancher-${REPO} ${RANCHER_CHART_REPO}${REPO} --force-update
+helm repo update
+
+echo "Reinstalling Rancher ${RANCHER_TAG_VERSION} with chart version ${RANCHER_CHART_VERSION}"
+if [ -n "$RANCHER_AGENT_IMAGE" ]; then
+    helm upgrade --install rancher rancher-${REPO}/rancher --namespace cattle-system --version ${RANCHER_CHART_VERSION} \
+                                                                                 --set global.cattle.psp.enabled=false \
+                                                                                 --set hostname=${HOSTNAME} \
+                                                                                 --set rancherImageTag=${RANCHER_TAG_VERSION} \
+                                                                                 --set rancherImage=${RANCHER_IMAGE} \
+                                                                                 --set "extraEnv[0].name=CATTLE_AGENT_IMAGE" \
+                                                                                 --set "extraEnv[0].value=${RANCHER_AGENT_IMAGE}:${RANCHER_TAG_VERSION}" \
+                                                                                 --devel
+
+else
+    helm upgrade --install rancher rancher-${REPO}/rancher --namespace cattle-system --version ${RANCHER_CHART_VERSION} \
+                                                                                 --set global.cattle.psp.enabled=false \
+                                                                                 --set hostname=${HOSTNAME} \
+                                                                                 --set rancherImage=${RANCHER_IMAGE} \
+                                                                                 --set rancherImageTag=${RANCHER_TAG_VERSION} \
+                                                                                 --devel
+fi
+
+echo "Waiting for Rancher to be rolled out"
+kubectl -n cattle-system rollout status deploy/rancher
+kubectl -n cattle-system get deploy rancher
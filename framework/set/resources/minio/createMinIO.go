@@ -0,0 +1,76 @@
+package minio
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/framework/set/defaults"
+	"github.com/rancher/tfp-automation/framework/set/resources/rke2"
+	"github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	installMinIO = "install_minio"
+	defaultPort  = "9000"
+)
+
+// CreateMinIO is a helper function that will install MinIO on the given standalone host, so that it can be used as
+// an S3 stand-in for etcd snapshots. The MinIO root credentials are the configured S3 credentials.
+func CreateMinIO(file *os.File, newFile *hclwrite.File, rootBody *hclwrite.Body, terraformConfig *config.TerraformConfig,
+	minIOPublicIP string) (*os.File, error) {
+	var err error
+	userDir := os.Getenv("GOROOT")
+	if userDir == "" {
+		userDir, err = os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+
+		userDir = filepath.Join(userDir, "go/")
+	}
+
+	minIOScriptPath := filepath.Join(userDir, "src/github.com/rancher/tfp-automation/framework/set/resources/minio/minio.sh")
+
+	minIOScriptContent, err := os.ReadFile(minIOScriptPath)
+	if err != nil {
+		return nil, err
+	}
+
+	_, provisionerBlockBody := rke2.CreateNullResource(rootBody, terraformConfig, minIOPublicIP, installMinIO)
+
+	command := "bash -c '/tmp/minio.sh " + terraformConfig.S3Credentials.AccessKey + " " +
+		terraformConfig.S3Credentials.SecretKey + " " + terraformConfig.StandaloneMinIO.Bucket + " " +
+		minIOPublicIP + " " + Port(terraformConfig) + " " + terraformConfig.Standalone.OSUser
+
+	if terraformConfig.StandaloneMinIO.Version != "" {
+		command += " " + terraformConfig.StandaloneMinIO.Version
+	}
+
+	command += "'"
+
+	provisionerBlockBody.SetAttributeValue(defaults.Inline, cty.ListVal([]cty.Value{
+		cty.StringVal("echo '" + string(minIOScriptContent) + "' > /tmp/minio.sh"),
+		cty.StringVal("chmod +x /tmp/minio.sh"),
+		cty.StringVal(command),
+	}))
+
+	_, err = file.Write(newFile.Bytes())
+	if err != nil {
+		logrus.Infof("Failed to append configurations to main.tf file. Error: %v", err)
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// Port is a helper function that will return the port MinIO listens on, defaulting to 9000.
+func Port(terraformConfig *config.TerraformConfig) string {
+	if terraformConfig.StandaloneMinIO.Port != "" {
+		return terraformConfig.StandaloneMinIO.Port
+	}
+
+	return defaultPort
+}
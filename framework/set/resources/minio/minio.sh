@@ -0,0 +1,66 @@
+#!/usr/bin/bash
+
+ACCESS_KEY=$1
+SECRET_KEY=$2
+BUCKET=$3
+HOST=$4
+PORT=$5
+USER=$6
+VERSION=${7}
+
+set -ex
+
+MINIO_DIR=/home/${USER}/minio
+CERTS_DIR=/home/${USER}/.minio/certs
+RELEASE_URL=https://dl.min.io/server/minio/release/linux-amd64
+CLIENT_URL=https://dl.min.io/client/mc/release/linux-amd64
+
+echo "Installing MinIO"
+if [ -n "${VERSION}" ]; then
+    sudo curl -fsSL -o /usr/local/bin/minio ${RELEASE_URL}/archive/minio.${VERSION}
+else
+    sudo curl -fsSL -o /usr/local/bin/minio ${RELEASE_URL}/minio
+fi
+
+sudo curl -fsSL -o /usr/local/bin/mc ${CLIENT_URL}/mc
+sudo chmod +x /usr/local/bin/minio /usr/local/bin/mc
+
+echo "Generating self-signed certificate for ${HOST}"
+mkdir -p ${MINIO_DIR} ${CERTS_DIR}
+openssl req -x509 -newkey rsa:4096 -sha256 -days 365 -nodes \
+    -keyout ${CERTS_DIR}/private.key -out ${CERTS_DIR}/public.crt \
+    -subj "/CN=${HOST}" -addext "subjectAltName=IP:${HOST}"
+
+sudo chown -R ${USER} ${MINIO_DIR} /home/${USER}/.minio
+
+echo "Starting MinIO"
+sudo tee /etc/systemd/system/minio.service > /dev/null <<SERVICE
+[Unit]
+Description=MinIO
+After=network-online.target
+
+[Service]
+User=${USER}
+Environment=MINIO_ROOT_USER=${ACCESS_KEY}
+Environment=MINIO_ROOT_PASSWORD=${SECRET_KEY}
+ExecStart=/usr/local/bin/minio server ${MINIO_DIR} --address :${PORT} --certs-dir ${CERTS_DIR}
+Restart=always
+
+[Install]
+WantedBy=multi-user.target
+SERVICE
+
+sudo systemctl daemon-reload
+sudo systemctl enable --now minio
+
+echo "Waiting for MinIO to be ready"
+until curl -fsk https://${HOST}:${PORT}/minio/health/ready; do
+    sleep 5
+done
+
+echo "Creating bucket ${BUCKET}"
+mc alias set --insecure local https://${HOST}:${PORT} ${ACCESS_KEY} ${SECRET_KEY}
+mc mb --insecure --ignore-existing local/${BUCKET}
+
+echo "MinIO endpoint CA:"
+cat ${CERTS_DIR}/public.crt
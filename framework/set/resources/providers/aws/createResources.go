@@ -12,7 +12,7 @@ import (
 // CreateAWSResources is a helper function that will create the AWS resources needed for the RKE2 cluster.
 func CreateAWSResources(file *os.File, newFile *hclwrite.File, tfBlockBody, rootBody *hclwrite.Body, terraformConfig *config.TerraformConfig,
 	terratestConfig *config.TerratestConfig, instances []string) (*os.File, error) {
-	CreateAWSTerraformProviderBlock(tfBlockBody)
+	CreateAWSTerraformProviderBlock(tfBlockBody, terraformConfig)
 	rootBody.AppendNewline()
 
 	CreateAWSProviderBlock(rootBody, terraformConfig)
@@ -68,7 +68,7 @@ func CreateAWSResources(file *os.File, newFile *hclwrite.File, tfBlockBody, root
 // CreateAirgappedAWSResources is a helper function that will create the AWS resources needed for the airagpped RKE2 cluster.
 func CreateAirgappedAWSResources(file *os.File, newFile *hclwrite.File, tfBlockBody, rootBody *hclwrite.Body, terraformConfig *config.TerraformConfig,
 	terratestConfig *config.TerratestConfig, instances []string) (*os.File, error) {
-	CreateAWSTerraformProviderBlock(tfBlockBody)
+	CreateAWSTerraformProviderBlock(tfBlockBody, terraformConfig)
 	rootBody.AppendNewline()
 
 	CreateAWSProviderBlock(rootBody, terraformConfig)
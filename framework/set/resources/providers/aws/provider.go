@@ -1,12 +1,11 @@
 package aws
 
 import (
-	"os"
-
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/rancher/tfp-automation/config"
 	"github.com/rancher/tfp-automation/framework/set/defaults"
+	"github.com/rancher/tfp-automation/framework/versions"
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -22,14 +21,14 @@ const (
 )
 
 // CreateAWSTerraformProviderBlock will up the terraform block with the required aws provider.
-func CreateAWSTerraformProviderBlock(tfBlockBody *hclwrite.Body) {
-	awsProviderVersion := os.Getenv("AWS_PROVIDER_VERSION")
+func CreateAWSTerraformProviderBlock(tfBlockBody *hclwrite.Body, terraformConfig *config.TerraformConfig) {
+	awsSource, awsProviderVersion := versions.GetProviderVersion(terraformConfig, defaults.Aws)
 
 	reqProvsBlock := tfBlockBody.AppendNewBlock(requiredProviders, nil)
 	reqProvsBlockBody := reqProvsBlock.Body()
 
 	reqProvsBlockBody.SetAttributeValue(defaults.Aws, cty.ObjectVal(map[string]cty.Value{
-		defaults.Source:  cty.StringVal(defaults.AwsSource),
+		defaults.Source:  cty.StringVal(awsSource),
 		defaults.Version: cty.StringVal(awsProviderVersion),
 	}))
 }
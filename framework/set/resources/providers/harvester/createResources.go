@@ -12,7 +12,7 @@ import (
 // CreateHarvesterResources is a helper function that will create the Harvester resources needed for the RKE2 cluster.
 func CreateHarvesterResources(file *os.File, newFile *hclwrite.File, tfBlockBody, rootBody *hclwrite.Body, terraformConfig *config.TerraformConfig,
 	terratestConfig *config.TerratestConfig, instances []string) (*os.File, error) {
-	CreateTerraformProviderBlock(tfBlockBody)
+	CreateTerraformProviderBlock(tfBlockBody, terraformConfig)
 	rootBody.AppendNewline()
 
 	CreateHarvesterProviderBlock(rootBody, terraformConfig)
@@ -2,13 +2,13 @@ package harvester
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/rancher/tfp-automation/config"
 	"github.com/rancher/tfp-automation/framework/set/defaults"
+	"github.com/rancher/tfp-automation/framework/versions"
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -24,20 +24,20 @@ const (
 )
 
 // CreateTerraformProviderBlock will up the terraform block with the required harvester provider.
-func CreateTerraformProviderBlock(tfBlockBody *hclwrite.Body) {
-	harvesterProviderVersion := os.Getenv("HARVESTER_PROVIDER_VERSION")
-	kubernetesProviderVersion := os.Getenv("KUBERNETES_PROVIDER_VERSION")
+func CreateTerraformProviderBlock(tfBlockBody *hclwrite.Body, terraformConfig *config.TerraformConfig) {
+	harvesterSource, harvesterProviderVersion := versions.GetProviderVersion(terraformConfig, defaults.Harvester)
+	kubernetesSource, kubernetesProviderVersion := versions.GetProviderVersion(terraformConfig, defaults.Kubernetes)
 
 	reqProvsBlock := tfBlockBody.AppendNewBlock(requiredProviders, nil)
 	reqProvsBlockBody := reqProvsBlock.Body()
 
 	reqProvsBlockBody.SetAttributeValue("harvester", cty.ObjectVal(map[string]cty.Value{
-		defaults.Source:  cty.StringVal(defaults.HarvesterSource),
+		defaults.Source:  cty.StringVal(harvesterSource),
 		defaults.Version: cty.StringVal(harvesterProviderVersion),
 	}))
 
 	reqProvsBlockBody.SetAttributeValue("kubernetes", cty.ObjectVal(map[string]cty.Value{
-		defaults.Source:  cty.StringVal(defaults.KubernetesSource),
+		defaults.Source:  cty.StringVal(kubernetesSource),
 		defaults.Version: cty.StringVal(kubernetesProviderVersion),
 	}))
 }
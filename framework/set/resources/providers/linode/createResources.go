@@ -11,7 +11,7 @@ import (
 // CreateLinodeResources is a helper function that will create the Linode resources needed for the RKE2 cluster.
 func CreateLinodeResources(file *os.File, newFile *hclwrite.File, tfBlockBody, rootBody *hclwrite.Body, terraformConfig *config.TerraformConfig,
 	terratestConfig *config.TerratestConfig, instances []string) (*os.File, error) {
-	CreateLinodeTerraformProviderBlock(tfBlockBody)
+	CreateLinodeTerraformProviderBlock(tfBlockBody, terraformConfig)
 	rootBody.AppendNewline()
 
 	CreateLinodeProviderBlock(rootBody, terraformConfig)
@@ -1,13 +1,12 @@
 package linode
 
 import (
-	"os"
-
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/rancher/tfp-automation/config"
 	"github.com/rancher/tfp-automation/defaults/resourceblocks/nodeproviders/linode"
 	"github.com/rancher/tfp-automation/framework/set/defaults"
+	"github.com/rancher/tfp-automation/framework/versions"
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -24,14 +23,14 @@ const (
 )
 
 // CreateLinodeTerraformProviderBlock will up the terraform block with the required linode provider.
-func CreateLinodeTerraformProviderBlock(tfBlockBody *hclwrite.Body) {
-	linodeProviderVersion := os.Getenv("LINODE_PROVIDER_VERSION")
+func CreateLinodeTerraformProviderBlock(tfBlockBody *hclwrite.Body, terraformConfig *config.TerraformConfig) {
+	linodeSource, linodeProviderVersion := versions.GetProviderVersion(terraformConfig, defaults.Linode)
 
 	reqProvsBlock := tfBlockBody.AppendNewBlock(requiredProviders, nil)
 	reqProvsBlockBody := reqProvsBlock.Body()
 
 	reqProvsBlockBody.SetAttributeValue(defaults.Linode, cty.ObjectVal(map[string]cty.Value{
-		defaults.Source:  cty.StringVal(defaults.LinodeSource),
+		defaults.Source:  cty.StringVal(linodeSource),
 		defaults.Version: cty.StringVal(linodeProviderVersion),
 	}))
 }
@@ -1,7 +1,6 @@
 package rancher2
 
 import (
-	"os"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2/hclsyntax"
@@ -12,35 +11,30 @@ import (
 	"github.com/rancher/tfp-automation/defaults/configs"
 	"github.com/rancher/tfp-automation/defaults/modules"
 	"github.com/rancher/tfp-automation/framework/set/defaults"
+	"github.com/rancher/tfp-automation/framework/versions"
 	"github.com/sirupsen/logrus"
 	"github.com/zclconf/go-cty/cty"
 )
 
 const (
-	apiURL              = "api_url"
-	ec2                 = "ec2"
-	globalRoleBinding   = "rancher2_global_role_binding"
-	globalRoleID        = "global_role_id"
-	insecure            = "insecure"
-	name                = "name"
-	provider            = "provider"
-	rancher2            = "rancher2"
-	rancherRKE          = "rancher/rke"
-	rancherSource       = "source"
-	rancherUser         = "rancher2_user"
-	rc                  = "-rc"
-	requiredProviders   = "required_providers"
-	terraform           = "terraform"
-	testPassword        = "password"
-	tokenKey            = "token_key"
-	version             = "version"
-	user                = "user"
-	userID              = "user_id"
-	username            = "username"
-	providerEnvVar      = "RANCHER2_PROVIDER_VERSION"
-	awsProviderEnvVar   = "AWS_PROVIDER_VERSION"
-	localProviderEnvVar = "LOCALS_PROVIDER_VERSION"
-	rkeEnvVar           = "RKE_PROVIDER_VERSION"
+	apiURL            = "api_url"
+	ec2               = "ec2"
+	globalRoleBinding = "rancher2_global_role_binding"
+	globalRoleID      = "global_role_id"
+	insecure          = "insecure"
+	name              = "name"
+	provider          = "provider"
+	rancher2          = "rancher2"
+	rancherSource     = "source"
+	rancherUser       = "rancher2_user"
+	requiredProviders = "required_providers"
+	terraform         = "terraform"
+	testPassword      = "password"
+	tokenKey          = "token_key"
+	version           = "version"
+	user              = "user"
+	userID            = "user_id"
+	username          = "username"
 )
 
 // SetProvidersAndUsersTF is a helper function that will set the general Terraform configurations in the main.tf file.
@@ -61,6 +55,17 @@ func SetProvidersAndUsersTF(testUser, testPassword string, authProvider bool, ne
 	return newFile, rootBody
 }
 
+// SetProvidersTF is a helper function that will only set the Terraform provider configurations in the main.tf file.
+func SetProvidersTF(newFile *hclwrite.File, rootBody *hclwrite.Body, configMap []map[string]any) (*hclwrite.File, *hclwrite.Body) {
+	createRequiredProviders(rootBody, configMap, false)
+
+	rootBody.AppendNewline()
+
+	createProvider(rootBody, configMap, false)
+
+	return newFile, rootBody
+}
+
 // createRequiredProviders creates the required_providers block.
 func createRequiredProviders(rootBody *hclwrite.Body, configMap []map[string]any, customModule bool) {
 	tfBlock := rootBody.AppendNewBlock(terraform, nil)
@@ -74,6 +79,11 @@ func createRequiredProviders(rootBody *hclwrite.Body, configMap []map[string]any
 
 	source, rancherProviderVersion, awsProviderVersion, linodeProviderVersion, localProviderVersion, rkeProviderVersion := getRequiredProviderVersions(configMap)
 
+	awsSource, _ := versions.GetProviderVersion(terraformConfig, defaults.Aws)
+	linodeSource, _ := versions.GetProviderVersion(terraformConfig, defaults.Linode)
+	localSource, _ := versions.GetProviderVersion(terraformConfig, defaults.Local)
+	rkeSource, _ := versions.GetProviderVersion(terraformConfig, defaults.RKE)
+
 	if rancherProviderVersion != "" {
 		reqProvsBlockBody.SetAttributeValue(rancher2, cty.ObjectVal(map[string]cty.Value{
 			rancherSource: cty.StringVal(source),
@@ -83,28 +93,28 @@ func createRequiredProviders(rootBody *hclwrite.Body, configMap []map[string]any
 
 	if awsProviderVersion != "" && terraformConfig.Provider == defaults.Aws && customModule {
 		reqProvsBlockBody.SetAttributeValue(defaults.Aws, cty.ObjectVal(map[string]cty.Value{
-			defaults.Source:  cty.StringVal(defaults.AwsSource),
+			defaults.Source:  cty.StringVal(awsSource),
 			defaults.Version: cty.StringVal(awsProviderVersion),
 		}))
 	}
 
 	if linodeProviderVersion != "" && terraformConfig.Provider == defaults.Linode && customModule {
 		reqProvsBlockBody.SetAttributeValue(defaults.Linode, cty.ObjectVal(map[string]cty.Value{
-			defaults.Source:  cty.StringVal(defaults.LinodeSource),
+			defaults.Source:  cty.StringVal(linodeSource),
 			defaults.Version: cty.StringVal(linodeProviderVersion),
 		}))
 	}
 
 	if localProviderVersion != "" {
 		reqProvsBlockBody.SetAttributeValue(defaults.Local, cty.ObjectVal(map[string]cty.Value{
-			defaults.Source:  cty.StringVal(defaults.LocalSource),
+			defaults.Source:  cty.StringVal(localSource),
 			defaults.Version: cty.StringVal(localProviderVersion),
 		}))
 	}
 
 	if rkeProviderVersion != "" {
 		reqProvsBlockBody.SetAttributeValue(defaults.RKE, cty.ObjectVal(map[string]cty.Value{
-			defaults.Source:  cty.StringVal(rancherRKE),
+			defaults.Source:  cty.StringVal(rkeSource),
 			defaults.Version: cty.StringVal(rkeProviderVersion),
 		}))
 	}
@@ -182,7 +192,8 @@ func createGlobalRoleBinding(rootBody *hclwrite.Body, testUser string, userID st
 	globalRoleBindingBlockBody.SetAttributeRaw(userID, standardUser)
 }
 
-// Determines the required providers from the list of configs.
+// Determines the required providers from the list of configs. Every config must resolve to the same source and version
+// of a provider, since the main.tf has a single required_providers block.
 func getRequiredProviderVersions(configMap []map[string]any) (source, rancherProviderVersion, rkeProviderVersion, localProviderVersion,
 	awsProviderVersion, linodeProviderVersion string) {
 	for _, cattleConfig := range configMap {
@@ -190,35 +201,48 @@ func getRequiredProviderVersions(configMap []map[string]any) (source, rancherPro
 		operations.LoadObjectFromMap(config.TerraformConfigurationFileKey, cattleConfig, terraformConfig)
 		module := terraformConfig.Module
 
-		rancherProviderVersion = os.Getenv(providerEnvVar)
-		if rancherProviderVersion == "" {
-			logrus.Fatalf("Expected env var not set %s", providerEnvVar)
+		configSource, configRancherProviderVersion := versions.GetProviderVersion(terraformConfig, rancher2)
+		if configRancherProviderVersion == "" {
+			logrus.Fatalf("Expected provider version not set in providers config or env var %s", versions.Rancher2ProviderEnvVar)
 		}
 
-		source = "rancher/rancher2"
-		if strings.Contains(rancherProviderVersion, rc) {
-			source = "terraform.local/local/rancher2"
-		}
+		source = requiredProviderValue(rancher2+" source", source, configSource)
+		rancherProviderVersion = requiredProviderValue(rancher2, rancherProviderVersion, configRancherProviderVersion)
 
 		if module == modules.ImportEC2RKE1 {
-			rkeProviderVersion = os.Getenv(rkeEnvVar)
-			if rkeProviderVersion == "" {
-				logrus.Fatalf("Expected env var not set %s", rkeEnvVar)
+			_, configRKEProviderVersion := versions.GetProviderVersion(terraformConfig, defaults.RKE)
+			if configRKEProviderVersion == "" {
+				logrus.Fatalf("Expected provider version not set in providers config or env var %s", versions.RKEProviderEnvVar)
 			}
+
+			rkeProviderVersion = requiredProviderValue(defaults.RKE, rkeProviderVersion, configRKEProviderVersion)
 		}
 
 		if strings.Contains(module, defaults.Custom) || strings.Contains(module, defaults.Import) || strings.Contains(module, defaults.Airgap) ||
 			strings.Contains(module, ec2) {
-			awsProviderVersion = os.Getenv(awsProviderEnvVar)
-			if awsProviderVersion == "" {
-				logrus.Fatalf("Expected env var not set %s", awsProviderEnvVar)
+			_, configAWSProviderVersion := versions.GetProviderVersion(terraformConfig, defaults.Aws)
+			if configAWSProviderVersion == "" {
+				logrus.Fatalf("Expected provider version not set in providers config or env var %s", versions.AWSProviderEnvVar)
 			}
 
-			localProviderVersion = os.Getenv(localProviderEnvVar)
-			if localProviderVersion == "" {
-				logrus.Fatalf("Expected env var not set %s", localProviderEnvVar)
+			_, configLocalProviderVersion := versions.GetProviderVersion(terraformConfig, defaults.Local)
+			if configLocalProviderVersion == "" {
+				logrus.Fatalf("Expected provider version not set in providers config or env var %s", versions.LocalProviderEnvVar)
 			}
+
+			awsProviderVersion = requiredProviderValue(defaults.Aws, awsProviderVersion, configAWSProviderVersion)
+			localProviderVersion = requiredProviderValue(defaults.Local, localProviderVersion, configLocalProviderVersion)
 		}
 	}
 	return source, rancherProviderVersion, awsProviderVersion, linodeProviderVersion, localProviderVersion, rkeProviderVersion
 }
+
+// requiredProviderValue is a helper function that will return the provider value resolved from the next config, failing
+// if a previous config resolved a different one.
+func requiredProviderValue(name, current, next string) string {
+	if current != "" && current != next {
+		logrus.Fatalf("Configs resolve different values for provider %s: %s and %s", name, current, next)
+	}
+
+	return next
+}
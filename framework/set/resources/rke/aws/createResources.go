@@ -10,10 +10,7 @@ import (
 )
 
 const (
-	awsProviderEnvVar = "AWS_PROVIDER_VERSION"
-	rc                = "-rc"
 	requiredProviders = "required_providers"
-	rkeProviderEnvVar = "RKE_PROVIDER_VERSION"
 	rkeServerOne      = "rke_server1"
 	rkeServerTwo      = "rke_server2"
 	rkeServerThree    = "rke_server3"
@@ -23,7 +20,7 @@ const (
 // CreateAWSResources is a helper function that will create the AWS resources needed for the RKE1 cluster.
 func CreateAWSResources(file *os.File, newFile *hclwrite.File, tfBlockBody, rootBody *hclwrite.Body, terraformConfig *config.TerraformConfig,
 	terratestConfig *config.TerratestConfig) (*os.File, error) {
-	createTerraformProviderBlock(tfBlockBody)
+	createTerraformProviderBlock(tfBlockBody, terraformConfig)
 	rootBody.AppendNewline()
 
 	aws.CreateAWSProviderBlock(rootBody, terraformConfig)
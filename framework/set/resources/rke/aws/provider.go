@@ -1,42 +1,36 @@
 package aws
 
 import (
-	"os"
-	"strings"
-
 	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/tfp-automation/config"
 	"github.com/rancher/tfp-automation/framework/set/defaults"
+	"github.com/rancher/tfp-automation/framework/versions"
 	"github.com/sirupsen/logrus"
 	"github.com/zclconf/go-cty/cty"
 )
 
 // createTerraformProviderBlock will up the terraform block with the required aws provider.
-func createTerraformProviderBlock(tfBlockBody *hclwrite.Body) {
-	awsProviderVersion := os.Getenv(awsProviderEnvVar)
+func createTerraformProviderBlock(tfBlockBody *hclwrite.Body, terraformConfig *config.TerraformConfig) {
+	awsSource, awsProviderVersion := versions.GetProviderVersion(terraformConfig, defaults.Aws)
 	if awsProviderVersion == "" {
-		logrus.Fatalf("Expected env var not set %s", awsProviderEnvVar)
+		logrus.Fatalf("Expected provider version not set in providers config or env var %s", versions.AWSProviderEnvVar)
 	}
 
-	rkeProviderVersion := os.Getenv(rkeProviderEnvVar)
+	rkeSource, rkeProviderVersion := versions.GetProviderVersion(terraformConfig, defaults.RKE)
 	if rkeProviderVersion == "" {
-		logrus.Fatalf("Expected env var not set %s", rkeProviderEnvVar)
+		logrus.Fatalf("Expected provider version not set in providers config or env var %s", versions.RKEProviderEnvVar)
 	}
 
 	reqProvsBlock := tfBlockBody.AppendNewBlock(requiredProviders, nil)
 	reqProvsBlockBody := reqProvsBlock.Body()
 
 	reqProvsBlockBody.SetAttributeValue(defaults.Aws, cty.ObjectVal(map[string]cty.Value{
-		defaults.Source:  cty.StringVal(defaults.AwsSource),
+		defaults.Source:  cty.StringVal(awsSource),
 		defaults.Version: cty.StringVal(awsProviderVersion),
 	}))
 
-	source := "rancher/rke"
-	if strings.Contains(rkeProviderVersion, rc) {
-		source = "terraform.local/local/rke"
-	}
-
 	reqProvsBlockBody.SetAttributeValue(defaults.RKE, cty.ObjectVal(map[string]cty.Value{
-		defaults.Source:  cty.StringVal(source),
+		defaults.Source:  cty.StringVal(rkeSource),
 		defaults.Version: cty.StringVal(rkeProviderVersion),
 	}))
 }
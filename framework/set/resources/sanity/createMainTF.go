@@ -9,6 +9,7 @@ import (
 	"github.com/rancher/tfp-automation/config"
 	"github.com/rancher/tfp-automation/defaults/configs"
 	"github.com/rancher/tfp-automation/defaults/providers"
+	"github.com/rancher/tfp-automation/framework/set/resources/minio"
 	tunnel "github.com/rancher/tfp-automation/framework/set/resources/providers"
 	"github.com/rancher/tfp-automation/framework/set/resources/rke2"
 	"github.com/rancher/tfp-automation/framework/set/resources/sanity/rancher"
@@ -85,6 +86,21 @@ func CreateMainTF(t *testing.T, terraformOptions *terraform.Options, keyPath str
 
 	terraform.InitAndApply(t, terraformOptions)
 
+	if terraformConfig.StandaloneMinIO != nil && terraformConfig.S3Credentials != nil {
+		file = OpenFile(file, keyPath)
+		logrus.Infof("Creating MinIO server...")
+		file, err = minio.CreateMinIO(file, newFile, rootBody, terraformConfig, rke2ServerOnePublicIP)
+		if err != nil {
+			return "", err
+		}
+
+		terraform.InitAndApply(t, terraformOptions)
+
+		if terraformConfig.S3Credentials.Endpoint == "" {
+			terraformConfig.S3Credentials.Endpoint = rke2ServerOnePublicIP + ":" + minio.Port(terraformConfig)
+		}
+	}
+
 	return rke2ServerOnePublicIP, nil
 }
 
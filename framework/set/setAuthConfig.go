@@ -8,9 +8,12 @@ import (
 	"github.com/rancher/tfp-automation/defaults/authproviders"
 	"github.com/rancher/tfp-automation/framework/set/authproviders/ad"
 	"github.com/rancher/tfp-automation/framework/set/authproviders/azureAD"
+	"github.com/rancher/tfp-automation/framework/set/authproviders/freeipa"
 	"github.com/rancher/tfp-automation/framework/set/authproviders/github"
 	"github.com/rancher/tfp-automation/framework/set/authproviders/ldap"
+	"github.com/rancher/tfp-automation/framework/set/authproviders/oidc"
 	"github.com/rancher/tfp-automation/framework/set/authproviders/okta"
+	"github.com/rancher/tfp-automation/framework/set/authproviders/saml"
 	resources "github.com/rancher/tfp-automation/framework/set/resources/rancher2"
 
 	"github.com/sirupsen/logrus"
@@ -29,18 +32,36 @@ func AuthConfig(testUser, testPassword string, configMap []map[string]any, newFi
 	case authProvider == authproviders.AD:
 		err = ad.SetAD(terraform, newFile, rootBody, file)
 		return err
+	case authProvider == authproviders.ADFS:
+		err = saml.SetADFS(rancherConfig, terraform, newFile, rootBody, file)
+		return err
 	case authProvider == authproviders.AzureAD:
 		err = azureAD.SetAzureAD(rancherConfig, terraform, newFile, rootBody, file)
 		return err
+	case authProvider == authproviders.FreeIPA:
+		err = freeipa.SetFreeIPA(terraform, newFile, rootBody, file)
+		return err
+	case authProvider == authproviders.GenericOIDC:
+		err = oidc.SetGenericOIDC(rancherConfig, terraform, newFile, rootBody, file)
+		return err
 	case authProvider == authproviders.GitHub:
 		err = github.SetGithub(terraform, newFile, rootBody, file)
 		return err
+	case authProvider == authproviders.Keycloak:
+		err = saml.SetKeycloak(rancherConfig, terraform, newFile, rootBody, file)
+		return err
+	case authProvider == authproviders.KeycloakOIDC:
+		err = oidc.SetKeycloakOIDC(rancherConfig, terraform, newFile, rootBody, file)
+		return err
 	case authProvider == authproviders.Okta:
 		err = okta.SetOkta(rancherConfig, terraform, newFile, rootBody, file)
 		return err
 	case authProvider == authproviders.OpenLDAP:
 		err = ldap.SetOpenLDAP(terraform, newFile, rootBody, file)
 		return err
+	case authProvider == authproviders.Ping:
+		err = saml.SetPing(rancherConfig, terraform, newFile, rootBody, file)
+		return err
 	default:
 		logrus.Errorf("Unsupported auth provider: %v", authProvider)
 	}
@@ -1,6 +1,7 @@
 package set
 
 import (
+	"fmt"
 	"os"
 	"strings"
 
@@ -12,7 +13,10 @@ import (
 	"github.com/rancher/tfp-automation/defaults/configs"
 	"github.com/rancher/tfp-automation/defaults/keypath"
 	"github.com/rancher/tfp-automation/defaults/modules"
+	"github.com/rancher/tfp-automation/framework/set/apps"
 	"github.com/rancher/tfp-automation/framework/set/defaults"
+	"github.com/rancher/tfp-automation/framework/set/fleet"
+	"github.com/rancher/tfp-automation/framework/set/projects"
 	"github.com/rancher/tfp-automation/framework/set/provisioning/airgap"
 	"github.com/rancher/tfp-automation/framework/set/provisioning/custom/locals"
 	custom "github.com/rancher/tfp-automation/framework/set/provisioning/custom/rke1"
@@ -67,6 +71,8 @@ func ConfigTF(client *rancher.Client, testUser, testPassword string, rbacRole co
 			customClusterNames = append(customClusterNames, terraform.ResourcePrefix)
 		}
 
+		isNodeDriver, isRKE1 := false, false
+
 		switch {
 		case module == clustertypes.AKS:
 			newFile, file, err = hosted.SetAKS(terraform, kubernetesVersion, nodePools, newFile, rootBody, file)
@@ -89,24 +95,15 @@ func ConfigTF(client *rancher.Client, testUser, testPassword string, rbacRole co
 				return clusterNames, nil, err
 			}
 
-			if rbacRole != "" {
-				newFile, rootBody, err = rbac.RoleCheck(client, newFile, rootBody, file, terraform, rbacRole, true)
-				if err != nil {
-					return clusterNames, nil, err
-				}
-			}
+			isNodeDriver = true
+			isRKE1 = true
 		case (strings.Contains(module, clustertypes.RKE2) || strings.Contains(module, clustertypes.K3S)) && !strings.Contains(module, defaults.Custom) && !strings.Contains(module, defaults.Import) && !strings.Contains(module, defaults.Airgap):
 			newFile, file, err = nodedriverV2.SetRKE2K3s(client, terraform, kubernetesVersion, psact, nodePools, snapshotInput, newFile, rootBody, file, rbacRole)
 			if err != nil {
 				return clusterNames, nil, err
 			}
 
-			if rbacRole != "" {
-				newFile, rootBody, err = rbac.RoleCheck(client, newFile, rootBody, file, terraform, rbacRole, false)
-				if err != nil {
-					return clusterNames, nil, err
-				}
-			}
+			isNodeDriver = true
 		case module == modules.CustomEC2RKE1:
 			newFile, file, err = custom.SetCustomRKE1(terraform, terratest, configMap, newFile, rootBody, file)
 			if err != nil {
@@ -159,6 +156,13 @@ func ConfigTF(client *rancher.Client, testUser, testPassword string, rbacRole co
 			logrus.Errorf("Unsupported module: %v", module)
 		}
 
+		if isNodeDriver {
+			newFile, rootBody, err = setClusterResources(newFile, rootBody, file, terraform, terratest, rbacRole, isRKE1)
+			if err != nil {
+				return clusterNames, nil, err
+			}
+		}
+
 		if i == len(configMap)-1 && containsCustomModule && !strings.Contains(module, defaults.Airgap) && !isWindows {
 			file, err = locals.SetLocals(rootBody, terraform, configMap, newFile, file, customClusterNames)
 			rootBody.AppendNewline()
@@ -191,3 +195,49 @@ func ConfigTF(client *rancher.Client, testUser, testPassword string, rbacRole co
 
 	return clusterNames, customClusterNames, nil
 }
+
+// setClusterResources is a helper function that will set the projects, Fleet git repos, Marketplace apps and RBAC
+// bindings declared for a node driver cluster in the main.tf file.
+func setClusterResources(newFile *hclwrite.File, rootBody *hclwrite.Body, file *os.File, terraform *config.TerraformConfig,
+	terratest *config.TerratestConfig, rbacRole configuration.Role, isRKE1 bool) (*hclwrite.File, *hclwrite.Body, error) {
+	var err error
+
+	if len(terratest.Projects) > 0 {
+		newFile, rootBody, err = projects.SetProjects(newFile, rootBody, terraform, terratest.Projects, isRKE1)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if terratest.Fleet != nil {
+		newFile, rootBody, err = fleet.SetFleet(newFile, rootBody, terraform, terratest.Fleet)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if terratest.Marketplace != nil {
+		newFile, rootBody, err = apps.SetApps(newFile, rootBody, terraform, terratest.Marketplace, isRKE1)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if rbacRole == configuration.DeclaredBindings {
+		if terratest.RBAC == nil {
+			return nil, nil, fmt.Errorf("terratest.rbac must be set to bind the %s role", rbacRole)
+		}
+
+		newFile, rootBody, err = rbac.SetRBAC(newFile, rootBody, file, terraform, terratest.RBAC, isRKE1)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else if rbacRole != "" {
+		newFile, rootBody, err = rbac.RoleCheck(newFile, rootBody, file, terraform, rbacRole, isRKE1)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return newFile, rootBody, nil
+}
@@ -0,0 +1,17 @@
+package set
+
+import (
+	"os"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/tfp-automation/framework/set/imports"
+	resources "github.com/rancher/tfp-automation/framework/set/resources/rancher2"
+)
+
+// ImportConfig is a function that will set the main.tf file to import the given existing Rancher objects.
+func ImportConfig(configMap []map[string]any, importedResources []imports.Resource, newFile *hclwrite.File, rootBody *hclwrite.Body,
+	file *os.File) error {
+	newFile, rootBody = resources.SetProvidersTF(newFile, rootBody, configMap)
+
+	return imports.SetImports(importedResources, newFile, rootBody, file)
+}
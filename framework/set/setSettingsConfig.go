@@ -0,0 +1,22 @@
+package set
+
+import (
+	"os"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/tfp-automation/config"
+	resources "github.com/rancher/tfp-automation/framework/set/resources/rancher2"
+	"github.com/rancher/tfp-automation/framework/set/settings"
+)
+
+// SettingsConfig is a function that will set the main.tf file with the global settings, feature flags and tokens
+// declared in the terratest config.
+func SettingsConfig(configMap []map[string]any, newFile *hclwrite.File, rootBody *hclwrite.Body, file *os.File) error {
+	newFile, rootBody = resources.SetProvidersTF(newFile, rootBody, configMap)
+
+	rootBody.AppendNewline()
+
+	_, _, terratest := config.LoadTFPConfigs(configMap[0])
+
+	return settings.SetSettings(terratest.GlobalSettings, newFile, rootBody, file)
+}
@@ -0,0 +1,95 @@
+package settings
+
+import (
+	"os"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/framework/set/defaults"
+	"github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	feature = "rancher2_feature"
+	setting = "rancher2_setting"
+	token   = "rancher2_token"
+
+	clusterID   = "cluster_id"
+	description = "description"
+	output      = "output"
+	sensitive   = "sensitive"
+	tokenKey    = "token"
+	ttl         = "ttl"
+
+	TokenIDSuffix = "_id"
+)
+
+// SetSettings is a function that will set the global settings, feature flags and tokens in the main.tf file. An output
+// is set for the value and the ID of each token, named after the token and the token with the `_id` suffix.
+func SetSettings(globalSettings *config.GlobalSettings, newFile *hclwrite.File, rootBody *hclwrite.Body, file *os.File) error {
+	for _, tfpSetting := range globalSettings.Settings {
+		settingBlock := rootBody.AppendNewBlock(defaults.Resource, []string{setting, tfpSetting.Name})
+		settingBlockBody := settingBlock.Body()
+
+		settingBlockBody.SetAttributeValue(defaults.ResourceName, cty.StringVal(tfpSetting.Name))
+		settingBlockBody.SetAttributeValue(defaults.Value, cty.StringVal(tfpSetting.Value))
+
+		rootBody.AppendNewline()
+	}
+
+	for _, tfpFeature := range globalSettings.Features {
+		featureBlock := rootBody.AppendNewBlock(defaults.Resource, []string{feature, tfpFeature.Name})
+		featureBlockBody := featureBlock.Body()
+
+		featureBlockBody.SetAttributeValue(defaults.ResourceName, cty.StringVal(tfpFeature.Name))
+		featureBlockBody.SetAttributeValue(defaults.Value, cty.BoolVal(tfpFeature.Value))
+
+		rootBody.AppendNewline()
+	}
+
+	for _, tfpToken := range globalSettings.Tokens {
+		tokenBlock := rootBody.AppendNewBlock(defaults.Resource, []string{token, tfpToken.Name})
+		tokenBlockBody := tokenBlock.Body()
+
+		tokenBlockBody.SetAttributeValue(description, cty.StringVal(tfpToken.Description))
+
+		if tfpToken.ClusterID != "" {
+			tokenBlockBody.SetAttributeValue(clusterID, cty.StringVal(tfpToken.ClusterID))
+		}
+
+		if tfpToken.TTL > 0 {
+			tokenBlockBody.SetAttributeValue(ttl, cty.NumberIntVal(tfpToken.TTL))
+		}
+
+		rootBody.AppendNewline()
+
+		setOutput(rootBody, tfpToken.Name, token+"."+tfpToken.Name+"."+tokenKey, true)
+		setOutput(rootBody, tfpToken.Name+TokenIDSuffix, token+"."+tfpToken.Name+".id", false)
+	}
+
+	_, err := file.Write(newFile.Bytes())
+	if err != nil {
+		logrus.Infof("Failed to write settings configurations to main.tf file. Error: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// setOutput is a helper function that will set an output of the given expression in the main.tf file.
+func setOutput(rootBody *hclwrite.Body, name, expression string, isSensitive bool) {
+	outputBlock := rootBody.AppendNewBlock(output, []string{name})
+	outputBlockBody := outputBlock.Body()
+
+	outputBlockBody.SetAttributeRaw(defaults.Value, hclwrite.Tokens{
+		{Type: hclsyntax.TokenIdent, Bytes: []byte(expression)},
+	})
+
+	if isSensitive {
+		outputBlockBody.SetAttributeValue(sensitive, cty.BoolVal(true))
+	}
+
+	rootBody.AppendNewline()
+}
@@ -8,35 +8,51 @@ import (
 	"github.com/gruntwork-io/terratest/modules/terraform"
 	"github.com/rancher/tfp-automation/config"
 	"github.com/rancher/tfp-automation/defaults/keypath"
+	"github.com/rancher/tfp-automation/framework/artifacts"
+	"github.com/rancher/tfp-automation/framework/versions"
 	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
 )
 
 // Setup is a function that will set the Terraform configuration and return the Terraform options.
 func Setup(t *testing.T, terraformConfig *config.TerraformConfig, terratestConfig *config.TerratestConfig, keyPath string) *terraform.Options {
-	var terratestLogger logger.Logger
+	var terratestLogger *logger.Logger
 
 	if strings.Contains(keyPath, keypath.RancherKeyPath) {
-		terratestLogger = getLogger(terratestConfig.TFLogging)
+		terratestLogger = getLogger(terratestConfig.TFLogging, keyPath)
 	} else {
-		terratestLogger = getLogger(terratestConfig.StandaloneLogging)
+		terratestLogger = getLogger(terratestConfig.StandaloneLogging, keyPath)
 	}
 
 	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
 		TerraformDir: keyPath,
 		NoColor:      true,
-		Logger:       &terratestLogger,
+		Logger:       terratestLogger,
 	})
 
+	err := versions.ValidateProviders(terraformConfig)
+	require.NoError(t, err)
+
+	cliConfigPath, err := versions.SetCLIConfig(terraformConfig, keyPath)
+	require.NoError(t, err)
+
+	if cliConfigPath != "" {
+		if terraformOptions.EnvVars == nil {
+			terraformOptions.EnvVars = map[string]string{}
+		}
+
+		terraformOptions.EnvVars[versions.CLIConfigEnvVar] = cliConfigPath
+	}
+
 	return terraformOptions
 }
 
-func getLogger(tfLogging bool) logger.Logger {
+func getLogger(tfLogging bool, keyPath string) *logger.Logger {
 	if tfLogging {
 		logrus.Infof("Logging enabled. Terraform logs will be displayed.")
-		return *logger.Default
+	} else {
+		logrus.Infof("Logging disabled. Terraform logs will be suppressed and only written to the test artifacts.")
 	}
 
-	logrus.Infof("Logging disabled. Terraform logs will be suppressed.")
-
-	return *logger.Discard
+	return artifacts.NewLogger(keyPath, tfLogging)
 }
@@ -0,0 +1,112 @@
+package versions
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	CLIConfigEnvVar = "TF_CLI_CONFIG_FILE"
+	CLIConfigFile   = "/.terraformrc"
+
+	devOverrides           = "dev_overrides"
+	direct                 = "direct"
+	exclude                = "exclude"
+	filesystemMirror       = "filesystem_mirror"
+	include                = "include"
+	path                   = "path"
+	providerInstallation   = "provider_installation"
+	defaultRegistryPattern = "registry.terraform.io/"
+)
+
+// SetCLIConfig is a function that will write a Terraform CLI configuration file to the given key path that installs
+// the pinned providers from the filesystem mirror and dev overrides. The path of the file is returned so that it can
+// be passed to Terraform through TF_CLI_CONFIG_FILE. An empty path is returned if no CLI configuration is needed.
+func SetCLIConfig(terraformConfig *config.TerraformConfig, keyPath string) (string, error) {
+	providers, err := ResolveProviders(terraformConfig)
+	if err != nil || providers == nil {
+		return "", err
+	}
+
+	overrides := map[string]string{}
+	mirrored := []string{}
+
+	for name, provider := range providers.Versions {
+		source, _ := ProviderVersion(providers, name)
+
+		if provider.DevOverride != "" {
+			overrides[source] = provider.DevOverride
+		} else if providers.FilesystemMirror != "" {
+			if strings.Count(source, "/") == 1 {
+				source = defaultRegistryPattern + source
+			}
+
+			mirrored = append(mirrored, source)
+		}
+	}
+
+	if len(overrides) == 0 && len(mirrored) == 0 {
+		return "", nil
+	}
+
+	sort.Strings(mirrored)
+
+	newFile := hclwrite.NewEmptyFile()
+	rootBody := newFile.Body()
+
+	installationBlock := rootBody.AppendNewBlock(providerInstallation, nil)
+	installationBlockBody := installationBlock.Body()
+
+	if len(overrides) > 0 {
+		overridesBlock := installationBlockBody.AppendNewBlock(devOverrides, nil)
+		overridesBlockBody := overridesBlock.Body()
+
+		overrideSources := []string{}
+		for source := range overrides {
+			overrideSources = append(overrideSources, source)
+		}
+
+		sort.Strings(overrideSources)
+
+		for _, source := range overrideSources {
+			overridesBlockBody.SetAttributeRaw(`"`+source+`"`, hclwrite.TokensForValue(cty.StringVal(overrides[source])))
+		}
+	}
+
+	var mirroredValues []cty.Value
+	for _, source := range mirrored {
+		mirroredValues = append(mirroredValues, cty.StringVal(source))
+	}
+
+	if len(mirroredValues) > 0 {
+		mirrorBlock := installationBlockBody.AppendNewBlock(filesystemMirror, nil)
+		mirrorBlockBody := mirrorBlock.Body()
+
+		mirrorBlockBody.SetAttributeValue(path, cty.StringVal(providers.FilesystemMirror))
+		mirrorBlockBody.SetAttributeValue(include, cty.ListVal(mirroredValues))
+	}
+
+	directBlock := installationBlockBody.AppendNewBlock(direct, nil)
+	directBlockBody := directBlock.Body()
+
+	if len(mirroredValues) > 0 {
+		directBlockBody.SetAttributeValue(exclude, cty.ListVal(mirroredValues))
+	}
+
+	cliConfigPath := filepath.Join(keyPath, CLIConfigFile)
+
+	err = os.WriteFile(cliConfigPath, newFile.Bytes(), 0644)
+	if err != nil {
+		logrus.Errorf("Failed to write Terraform CLI configuration file. Error: %v", err)
+		return "", err
+	}
+
+	return cliConfigPath, nil
+}
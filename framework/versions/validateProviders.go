@@ -0,0 +1,98 @@
+package versions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	goversion "github.com/hashicorp/go-version"
+	"github.com/rancher/tfp-automation/config"
+)
+
+const (
+	defaultRegistry = "registry.terraform.io"
+	providerBinary  = "terraform-provider-"
+)
+
+// ValidateProviders is a function that will verify that every pinned provider can be installed before Terraform is
+// run. Dev overrides must point to a directory containing the provider binary and, when a filesystem mirror is set,
+// the mirror must contain a version of the provider that satisfies the version constraint, or any version when the
+// provider is not pinned to one. An error is also returned if the versions manifest cannot be loaded.
+func ValidateProviders(terraformConfig *config.TerraformConfig) error {
+	providers, err := ResolveProviders(terraformConfig)
+	if err != nil {
+		return fmt.Errorf("failed to load provider versions manifest %s: %v", terraformConfig.Providers.Manifest, err)
+	}
+
+	if providers == nil {
+		return nil
+	}
+
+	for name, provider := range providers.Versions {
+		source, version := ProviderVersion(providers, name)
+		providerType := source[strings.LastIndex(source, "/")+1:]
+
+		if provider.DevOverride != "" {
+			_, err := os.Stat(filepath.Join(provider.DevOverride, providerBinary+providerType))
+			if err != nil {
+				return fmt.Errorf("dev override for provider %s is not usable: %v", name, err)
+			}
+
+			continue
+		}
+
+		if providers.FilesystemMirror == "" {
+			continue
+		}
+
+		err := validateMirror(providers.FilesystemMirror, source, version)
+		if err != nil {
+			return fmt.Errorf("provider %s: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+// validateMirror is a helper function that will check that the filesystem mirror contains a version of the provider
+// source, for the current platform, that satisfies the version constraint. Any version is accepted when the
+// constraint is empty. The mirror uses Terraform's unpacked layout:
+// HOSTNAME/NAMESPACE/TYPE/VERSION/TARGET.
+func validateMirror(mirror, source, versionConstraint string) error {
+	if strings.Count(source, "/") == 1 {
+		source = defaultRegistry + "/" + source
+	}
+
+	providerPath := filepath.Join(mirror, source)
+
+	entries, err := os.ReadDir(providerPath)
+	if err != nil {
+		return fmt.Errorf("source %s not found in filesystem mirror %s: %v", source, mirror, err)
+	}
+
+	var constraints goversion.Constraints
+	if versionConstraint != "" {
+		constraints, err = goversion.NewConstraint(versionConstraint)
+		if err != nil {
+			return fmt.Errorf("invalid version constraint %q: %v", versionConstraint, err)
+		}
+	}
+
+	platform := runtime.GOOS + "_" + runtime.GOARCH
+
+	for _, entry := range entries {
+		mirroredVersion, err := goversion.NewVersion(entry.Name())
+		if err != nil || (constraints != nil && !constraints.Check(mirroredVersion)) {
+			continue
+		}
+
+		_, err = os.Stat(filepath.Join(providerPath, entry.Name(), platform))
+		if err == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("no version of %s matching %q for %s found in filesystem mirror %s", source, versionConstraint, platform, mirror)
+}
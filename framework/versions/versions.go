@@ -0,0 +1,150 @@
+package versions
+
+import (
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/framework/set/defaults"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	AWSProviderEnvVar        = "AWS_PROVIDER_VERSION"
+	HarvesterProviderEnvVar  = "HARVESTER_PROVIDER_VERSION"
+	KubernetesProviderEnvVar = "KUBERNETES_PROVIDER_VERSION"
+	LinodeProviderEnvVar     = "LINODE_PROVIDER_VERSION"
+	LocalProviderEnvVar      = "LOCALS_PROVIDER_VERSION"
+	Rancher2ProviderEnvVar   = "RANCHER2_PROVIDER_VERSION"
+	RKEProviderEnvVar        = "RKE_PROVIDER_VERSION"
+
+	localSourcePrefix = "terraform.local/local/"
+)
+
+var envVars = map[string]string{
+	defaults.Aws:        AWSProviderEnvVar,
+	defaults.Harvester:  HarvesterProviderEnvVar,
+	defaults.Kubernetes: KubernetesProviderEnvVar,
+	defaults.Linode:     LinodeProviderEnvVar,
+	defaults.Local:      LocalProviderEnvVar,
+	defaults.Rancher2:   Rancher2ProviderEnvVar,
+	defaults.RKE:        RKEProviderEnvVar,
+}
+
+var sources = map[string]string{
+	defaults.Aws:        defaults.AwsSource,
+	defaults.Harvester:  defaults.HarvesterSource,
+	defaults.Kubernetes: defaults.KubernetesSource,
+	defaults.Linode:     defaults.LinodeSource,
+	defaults.Local:      defaults.LocalSource,
+	defaults.Rancher2:   defaults.Rancher2Source,
+	defaults.RKE:        defaults.RKESource,
+}
+
+var (
+	manifests   = map[string]*config.Providers{}
+	manifestsMu sync.Mutex
+)
+
+// GetProviderVersion is a function that will resolve the source and version constraint of the given provider. The
+// providers section of the cattle-config takes precedence, then the versions manifest, and lastly the legacy
+// <PROVIDER>_PROVIDER_VERSION environment variable. An empty version is returned if the provider is not pinned anywhere.
+// The manifest is validated by Setup, so an invalid manifest is only logged here and its providers are ignored.
+func GetProviderVersion(terraformConfig *config.TerraformConfig, name string) (string, string) {
+	providers, err := ResolveProviders(terraformConfig)
+	if err != nil {
+		logrus.Errorf("Failed to load provider versions manifest %s. Error: %v", terraformConfig.Providers.Manifest, err)
+		providers = terraformConfig.Providers
+	}
+
+	return ProviderVersion(providers, name)
+}
+
+// ProviderVersion is a function that will resolve the source and version constraint of the given provider from the
+// already resolved providers, falling back to the <PROVIDER>_PROVIDER_VERSION environment variable and the default
+// source.
+func ProviderVersion(providers *config.Providers, name string) (string, string) {
+	var provider config.TerraformProvider
+	if providers != nil {
+		provider = providers.Versions[name]
+	}
+
+	version := provider.Version
+	if version == "" {
+		version = os.Getenv(envVars[name])
+	}
+
+	source := provider.Source
+	if source == "" {
+		source = sources[name]
+
+		if (name == defaults.Rancher2 || name == defaults.RKE) && strings.Contains(version, defaults.Rc) {
+			source = localSourcePrefix + name
+		}
+	}
+
+	return source, version
+}
+
+// ResolveProviders is a function that will return the pinned providers and filesystem mirror, merging the versions
+// manifest file with the providers section of the cattle-config. Values declared in the cattle-config win over the
+// manifest. The manifest is only read once per path. Nil is returned if no providers section is configured.
+func ResolveProviders(terraformConfig *config.TerraformConfig) (*config.Providers, error) {
+	if terraformConfig == nil || terraformConfig.Providers == nil {
+		return nil, nil
+	}
+
+	providers := &config.Providers{
+		FilesystemMirror: terraformConfig.Providers.FilesystemMirror,
+		Versions:         map[string]config.TerraformProvider{},
+	}
+
+	if terraformConfig.Providers.Manifest != "" {
+		manifest, err := loadManifest(terraformConfig.Providers.Manifest)
+		if err != nil {
+			return nil, err
+		}
+
+		if providers.FilesystemMirror == "" {
+			providers.FilesystemMirror = manifest.FilesystemMirror
+		}
+
+		for name, provider := range manifest.Versions {
+			providers.Versions[name] = provider
+		}
+	}
+
+	for name, provider := range terraformConfig.Providers.Versions {
+		providers.Versions[name] = provider
+	}
+
+	return providers, nil
+}
+
+// loadManifest is a helper function that will read a versions manifest file, caching it by path. The manifest has the
+// same shape as the providers section of the cattle-config.
+func loadManifest(manifestPath string) (*config.Providers, error) {
+	manifestsMu.Lock()
+	defer manifestsMu.Unlock()
+
+	if manifest, ok := manifests[manifestPath]; ok {
+		return manifest, nil
+	}
+
+	content, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := new(config.Providers)
+	err = yaml.Unmarshal(content, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	manifests[manifestPath] = manifest
+
+	return manifest, nil
+}
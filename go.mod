@@ -26,8 +26,10 @@ require (
 
 require (
 	github.com/antihax/optional v1.0.0
+	github.com/aws/aws-sdk-go v1.55.5
 	github.com/gruntwork-io/terratest v0.42.0
 	github.com/imdario/mergo v0.3.16
+	github.com/rancher/fleet/pkg/apis v0.12.0
 	github.com/rancher/norman v0.6.0
 	github.com/rancher/rancher v0.0.0-20250328200651-bdbb4ea81874
 	github.com/rancher/rancher/pkg/apis v0.0.0
@@ -66,7 +68,6 @@ require (
 	cloud.google.com/go/storage v1.43.0 // indirect
 	github.com/agext/levenshtein v1.2.3 // indirect
 	github.com/apparentlymart/go-textseg/v13 v13.0.0 // indirect
-	github.com/aws/aws-sdk-go v1.55.5 // indirect
 	github.com/bgentry/go-netrc v0.0.0-20140422174119-9fd32a8b3d3d // indirect
 	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
 	github.com/golang/groupcache v0.0.0-20210331224755-41bb18bfe9da // indirect
@@ -80,9 +81,9 @@ require (
 	github.com/hashicorp/go-getter v1.7.1 // indirect
 	github.com/hashicorp/go-multierror v1.1.1 // indirect
 	github.com/hashicorp/go-safetemp v1.0.0 // indirect
-	github.com/hashicorp/go-version v1.6.0 // indirect
+	github.com/hashicorp/go-version v1.6.0
 	github.com/hashicorp/hcl/v2 v2.17.0
-	github.com/hashicorp/terraform-json v0.20.0 // indirect
+	github.com/hashicorp/terraform-json v0.20.0
 	github.com/jinzhu/copier v0.0.0-20190924061706-b57f9002281a // indirect
 	github.com/jmespath/go-jmespath v0.4.0 // indirect
 	github.com/klauspost/compress v1.17.9 // indirect
@@ -154,7 +155,6 @@ require (
 	github.com/rancher/aks-operator v1.11.0 // indirect
 	github.com/rancher/apiserver v0.6.0 // indirect
 	github.com/rancher/eks-operator v1.11.0 // indirect
-	github.com/rancher/fleet/pkg/apis v0.12.0 // indirect
 	github.com/rancher/gke-operator v1.11.0 // indirect
 	github.com/rancher/lasso v0.2.1 // indirect
 	github.com/rancher/rke v1.8.0-rc.4 // indirect
@@ -188,5 +188,5 @@ require (
 	sigs.k8s.io/kustomize/api v0.18.0 // indirect
 	sigs.k8s.io/kustomize/kyaml v0.18.1 // indirect
 	sigs.k8s.io/structured-merge-diff/v4 v4.4.3 // indirect
-	sigs.k8s.io/yaml v1.4.0 // indirect
+	sigs.k8s.io/yaml v1.4.0
 )
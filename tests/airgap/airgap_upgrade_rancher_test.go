@@ -2,6 +2,7 @@ package airgap
 
 import (
 	"os"
+	"slices"
 	"strings"
 	"testing"
 
@@ -21,7 +22,6 @@ import (
 	"github.com/rancher/tfp-automation/framework/cleanup"
 	"github.com/rancher/tfp-automation/framework/set/resources/airgap"
 	"github.com/rancher/tfp-automation/framework/set/resources/rancher2"
-	"github.com/rancher/tfp-automation/framework/set/resources/upgrade"
 	qase "github.com/rancher/tfp-automation/pipeline/qase/results"
 	"github.com/rancher/tfp-automation/tests/extensions/provisioning"
 	"github.com/stretchr/testify/require"
@@ -119,26 +119,23 @@ func (a *TfpAirgapUpgradeRancherTestSuite) TfpSetupSuite() map[string]any {
 }
 
 func (a *TfpAirgapUpgradeRancherTestSuite) TestTfpUpgradeAirgapRancher() {
-	var clusterIDs []string
-
-	a.provisionAndVerifyCluster("Pre-Upgrade Airgap ", clusterIDs, false)
+	clusterIDs := a.provisionAndVerifyCluster("Pre-Upgrade Airgap ", false)
 
-	a.terraformConfig.Standalone.UpgradeRancher = true
+	a.terraformConfig.Standalone.UpgradeAirgapRancher = true
 
 	keyPath := rancher2.SetKeyPath(keypath.UpgradeKeyPath, a.terraformConfig.Provider)
-	err := upgrade.CreateMainTF(a.T(), a.upgradeTerraformOptions, keyPath, a.terraformConfig, a.terratestConfig, "", "", a.bastion, a.registry)
-	require.NoError(a.T(), err)
+	provisioning.RancherUpgrade(a.T(), a.client, a.terraformConfig, a.terratestConfig, clusterIDs, a.terraformOptions, a.upgradeTerraformOptions, keyPath, "", "", a.bastion, a.registry)
 
-	provisioning.VerifyClustersState(a.T(), a.client, clusterIDs)
-
-	a.provisionAndVerifyCluster("Post-Upgrade Airgap ", clusterIDs, true)
+	a.provisionAndVerifyCluster("Post-Upgrade Airgap ", true)
 
 	if a.terratestConfig.LocalQaseReporting {
 		qase.ReportTest()
 	}
 }
 
-func (a *TfpAirgapUpgradeRancherTestSuite) provisionAndVerifyCluster(name string, clusterIDs []string, deleteClusters bool) []string {
+func (a *TfpAirgapUpgradeRancherTestSuite) provisionAndVerifyCluster(name string, deleteClusters bool) []string {
+	var clusterIDs []string
+
 	tests := []struct {
 		name   string
 		module string
@@ -175,14 +172,22 @@ func (a *TfpAirgapUpgradeRancherTestSuite) provisionAndVerifyCluster(name string
 		tt.name = name + tt.name + " Kubernetes version: " + terratest.KubernetesVersion
 
 		a.Run((tt.name), func() {
-			clusterIDs, customClusterNames = provisioning.Provision(a.T(), a.client, rancher, terraform, testUser, testPassword, a.terraformOptions, configMap, newFile, rootBody, file, false, true, true, customClusterNames)
-			provisioning.VerifyClustersState(a.T(), a.client, clusterIDs)
-			provisioning.VerifyRegistry(a.T(), a.client, clusterIDs[0], terraform)
+			var provisionedClusterIDs []string
+			provisionedClusterIDs, customClusterNames = provisioning.Provision(a.T(), a.client, rancher, terraform, testUser, testPassword, a.terraformOptions, configMap, newFile, rootBody, file, false, true, true, customClusterNames)
+			provisioning.VerifyClustersState(a.T(), a.client, provisionedClusterIDs)
+			provisioning.VerifyRegistry(a.T(), a.client, provisionedClusterIDs[0], terraform)
+			clusterIDs = append(clusterIDs, provisionedClusterIDs...)
 
 			if strings.Contains(terraform.Module, modules.AirgapRKE2Windows) {
-				clusterIDs, _ = provisioning.Provision(a.T(), a.client, rancher, terraform, testUser, testPassword, a.terraformOptions, configMap, newFile, rootBody, file, true, true, true, customClusterNames)
-				provisioning.VerifyClustersState(a.T(), a.client, clusterIDs)
-				provisioning.VerifyRegistry(a.T(), a.client, clusterIDs[0], terraform)
+				provisionedClusterIDs, _ = provisioning.Provision(a.T(), a.client, rancher, terraform, testUser, testPassword, a.terraformOptions, configMap, newFile, rootBody, file, true, true, true, customClusterNames)
+				provisioning.VerifyClustersState(a.T(), a.client, provisionedClusterIDs)
+				provisioning.VerifyRegistry(a.T(), a.client, provisionedClusterIDs[0], terraform)
+
+				for _, clusterID := range provisionedClusterIDs {
+					if !slices.Contains(clusterIDs, clusterID) {
+						clusterIDs = append(clusterIDs, clusterID)
+					}
+				}
 			}
 		})
 	}
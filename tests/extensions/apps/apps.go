@@ -0,0 +1,55 @@
+package apps
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/shepherd/clients/rancher"
+	"github.com/rancher/shepherd/pkg/config/operations"
+	"github.com/rancher/tfp-automation/config"
+	framework "github.com/rancher/tfp-automation/framework/set"
+	"github.com/stretchr/testify/require"
+)
+
+// UpgradeApps is a function that will run terraform apply and upgrade every app declared in the terratest config that
+// has an upgraded chart version.
+func UpgradeApps(t *testing.T, client *rancher.Client, testUser, testPassword string, terraformOptions *terraform.Options,
+	configMap []map[string]any, newFile *hclwrite.File, rootBody *hclwrite.Body, file *os.File) {
+	_, _, terratest := config.LoadTFPConfigs(configMap[0])
+
+	marketplace := terratest.Marketplace
+	for i, app := range marketplace.Apps {
+		if app.UpgradedChartVersion != "" {
+			marketplace.Apps[i].ChartVersion = app.UpgradedChartVersion
+		}
+	}
+
+	applyMarketplace(t, client, testUser, testPassword, terraformOptions, marketplace, configMap, newFile, rootBody, file)
+}
+
+// UninstallApps is a function that will run terraform apply without the apps declared in the terratest config, which
+// uninstalls them while keeping the chart repositories.
+func UninstallApps(t *testing.T, client *rancher.Client, testUser, testPassword string, terraformOptions *terraform.Options,
+	configMap []map[string]any, newFile *hclwrite.File, rootBody *hclwrite.Body, file *os.File) {
+	_, _, terratest := config.LoadTFPConfigs(configMap[0])
+
+	marketplace := terratest.Marketplace
+	marketplace.Apps = nil
+
+	applyMarketplace(t, client, testUser, testPassword, terraformOptions, marketplace, configMap, newFile, rootBody, file)
+}
+
+// applyMarketplace is a helper function that will update the marketplace in the config, regenerate the main.tf file and
+// run terraform apply.
+func applyMarketplace(t *testing.T, client *rancher.Client, testUser, testPassword string, terraformOptions *terraform.Options,
+	marketplace *config.Marketplace, configMap []map[string]any, newFile *hclwrite.File, rootBody *hclwrite.Body, file *os.File) {
+	_, err := operations.ReplaceValue([]string{"terratest", "marketplace"}, marketplace, configMap[0])
+	require.NoError(t, err)
+
+	_, _, err = framework.ConfigTF(client, testUser, testPassword, "", configMap, newFile, rootBody, file, false, false, false, nil)
+	require.NoError(t, err)
+
+	terraform.Apply(t, terraformOptions)
+}
@@ -0,0 +1,150 @@
+package apps
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	catalogv1 "github.com/rancher/rancher/pkg/apis/catalog.cattle.io/v1"
+	"github.com/rancher/shepherd/clients/rancher"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	"github.com/rancher/shepherd/extensions/defaults"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	kwait "k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	appSteveType         = "catalog.cattle.io.app"
+	daemonSetSteveType   = "apps.daemonset"
+	deploymentSteveType  = "apps.deployment"
+	statefulSetSteveType = "apps.statefulset"
+
+	daemonSetKind   = "DaemonSet"
+	deploymentKind  = "Deployment"
+	deployedState   = "deployed"
+	statefulSetKind = "StatefulSet"
+)
+
+// VerifyApps is a function that will verify that every app declared in the terratest config is deployed on each of the
+// downstream clusters with the expected chart version and that its workloads are ready.
+func VerifyApps(t *testing.T, client *rancher.Client, clusterIDs []string, apps []config.App) {
+	for _, clusterID := range clusterIDs {
+		steveClient, err := client.Steve.ProxyDownstream(clusterID)
+		require.NoError(t, err)
+
+		for _, tfpApp := range apps {
+			logrus.Infof("Verifying app %s on cluster %s...", tfpApp.Name, clusterID)
+
+			app, err := waitForApp(steveClient, tfpApp)
+			require.NoError(t, err)
+
+			for _, resource := range app.Spec.Resources {
+				err = waitForWorkload(steveClient, resource)
+				require.NoError(t, err)
+			}
+		}
+	}
+}
+
+// VerifyAppsRemoved is a function that will verify that every app declared in the terratest config was uninstalled from
+// each of the downstream clusters.
+func VerifyAppsRemoved(t *testing.T, client *rancher.Client, clusterIDs []string, apps []config.App) {
+	for _, clusterID := range clusterIDs {
+		steveClient, err := client.Steve.ProxyDownstream(clusterID)
+		require.NoError(t, err)
+
+		for _, tfpApp := range apps {
+			logrus.Infof("Verifying app %s was uninstalled from cluster %s...", tfpApp.Name, clusterID)
+
+			err = kwait.PollUntilContextTimeout(context.TODO(), defaults.FiveSecondTimeout, defaults.FiveMinuteTimeout, true, func(ctx context.Context) (bool, error) {
+				_, err := steveClient.SteveType(appSteveType).ByID(tfpApp.Namespace + "/" + tfpApp.Name)
+				return err != nil, nil
+			})
+			require.NoError(t, err)
+		}
+	}
+}
+
+// waitForApp is a helper function that will wait until the app is deployed with the expected chart version.
+func waitForApp(steveClient *steveV1.Client, tfpApp config.App) (*catalogv1.App, error) {
+	app := &catalogv1.App{}
+
+	err := kwait.PollUntilContextTimeout(context.TODO(), defaults.FiveSecondTimeout, defaults.TenMinuteTimeout, true, func(ctx context.Context) (bool, error) {
+		appObject, err := steveClient.SteveType(appSteveType).ByID(tfpApp.Namespace + "/" + tfpApp.Name)
+		if err != nil {
+			return false, nil
+		}
+
+		err = steveV1.ConvertToK8sType(appObject.JSONResp, app)
+		if err != nil {
+			return false, err
+		}
+
+		if app.Status.Summary.State != deployedState || app.Spec.Chart == nil || app.Spec.Chart.Metadata == nil {
+			return false, nil
+		}
+
+		return tfpApp.ChartVersion == "" || app.Spec.Chart.Metadata.Version == tfpApp.ChartVersion, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("app %s was not deployed with chart version %q: %w", tfpApp.Name, tfpApp.ChartVersion, err)
+	}
+
+	return app, nil
+}
+
+// waitForWorkload is a helper function that will wait until the given release resource is ready, skipping resources
+// that are not deployments, daemon sets or stateful sets.
+func waitForWorkload(steveClient *steveV1.Client, resource catalogv1.ReleaseResource) error {
+	var steveType string
+
+	switch resource.Kind {
+	case deploymentKind:
+		steveType = deploymentSteveType
+	case daemonSetKind:
+		steveType = daemonSetSteveType
+	case statefulSetKind:
+		steveType = statefulSetSteveType
+	default:
+		return nil
+	}
+
+	logrus.Infof("Waiting for %s %s/%s to be ready...", resource.Kind, resource.Namespace, resource.Name)
+
+	return kwait.PollUntilContextTimeout(context.TODO(), defaults.FiveSecondTimeout, defaults.TenMinuteTimeout, true, func(ctx context.Context) (bool, error) {
+		workloadObject, err := steveClient.SteveType(steveType).ByID(resource.Namespace + "/" + resource.Name)
+		if err != nil {
+			return false, nil
+		}
+
+		switch resource.Kind {
+		case deploymentKind:
+			deployment := &appsv1.Deployment{}
+			err = steveV1.ConvertToK8sType(workloadObject.JSONResp, deployment)
+			if err != nil {
+				return false, err
+			}
+
+			return deployment.Spec.Replicas != nil && deployment.Status.ReadyReplicas == *deployment.Spec.Replicas, nil
+		case daemonSetKind:
+			daemonSet := &appsv1.DaemonSet{}
+			err = steveV1.ConvertToK8sType(workloadObject.JSONResp, daemonSet)
+			if err != nil {
+				return false, err
+			}
+
+			return daemonSet.Status.DesiredNumberScheduled > 0 && daemonSet.Status.NumberReady == daemonSet.Status.DesiredNumberScheduled, nil
+		default:
+			statefulSet := &appsv1.StatefulSet{}
+			err = steveV1.ConvertToK8sType(workloadObject.JSONResp, statefulSet)
+			if err != nil {
+				return false, err
+			}
+
+			return statefulSet.Spec.Replicas != nil && statefulSet.Status.ReadyReplicas == *statefulSet.Spec.Replicas, nil
+		}
+	})
+}
@@ -0,0 +1,224 @@
+package fleet
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/shepherd/clients/rancher"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	"github.com/rancher/shepherd/extensions/defaults"
+	"github.com/rancher/shepherd/extensions/defaults/namespaces"
+	shepherdFleet "github.com/rancher/shepherd/extensions/fleet"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/framework/set/fleet"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kwait "k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	bundleSteveType       = "fleet.cattle.io.bundle"
+	clusterGroupSteveType = "fleet.cattle.io.clustergroup"
+	clusterNameLabel      = "management.cattle.io/cluster-name"
+)
+
+// VerifyFleet is a function that will create a Fleet cluster group selecting the provisioned clusters and a Fleet git
+// repo targeting it for each git repo declared in the terratest config. It verifies that the bundles are deployed to
+// every cluster, then deletes the git repos and verifies that the deployed resources are removed. The cluster group and
+// git repos are also deleted if the verification fails.
+func VerifyFleet(t *testing.T, client *rancher.Client, terraformConfig *config.TerraformConfig, clusterIDs []string, fleetConfig *config.Fleet) {
+	clusterGroup, err := createClusterGroup(client, fleetConfig.ClusterGroup, clusterIDs)
+	require.NoError(t, err)
+
+	defer client.Steve.SteveType(clusterGroupSteveType).Delete(clusterGroup)
+
+	err = waitForClusterGroup(client, clusterGroup.ID, len(clusterIDs))
+	require.NoError(t, err)
+
+	for _, gitRepo := range fleetConfig.GitRepos {
+		verifyGitRepo(t, client, terraformConfig, clusterIDs, gitRepo, fleetConfig.ClusterGroup)
+	}
+}
+
+// verifyGitRepo is a helper function that will create a Fleet git repo targeting the given cluster group, verify that
+// its resources are deployed to every cluster, then delete it and verify that its resources are removed.
+func verifyGitRepo(t *testing.T, client *rancher.Client, terraformConfig *config.TerraformConfig, clusterIDs []string, gitRepo config.GitRepo,
+	clusterGroup string) {
+	logrus.Infof("Creating Fleet git repo %s targeting cluster group %s...", gitRepo.Name, clusterGroup)
+
+	repoObject, err := createGitRepo(client, terraformConfig, gitRepo, clusterGroup)
+	require.NoError(t, err)
+
+	repoDeleted := false
+	defer func() {
+		if !repoDeleted {
+			client.Steve.SteveType(shepherdFleet.FleetGitRepoResourceType).Delete(repoObject)
+		}
+	}()
+
+	resources, err := waitForGitRepoReady(client, repoObject.ID, len(clusterIDs))
+	require.NoError(t, err)
+	require.NotEmpty(t, resources, "git repo %s did not deploy any resources", gitRepo.Name)
+
+	for _, clusterID := range clusterIDs {
+		logrus.Infof("Verifying git repo %s resources on cluster %s...", gitRepo.Name, clusterID)
+
+		err = verifyResources(client, clusterID, resources, true)
+		require.NoError(t, err)
+	}
+
+	logrus.Infof("Deleting Fleet git repo %s...", gitRepo.Name)
+
+	err = client.Steve.SteveType(shepherdFleet.FleetGitRepoResourceType).Delete(repoObject)
+	require.NoError(t, err)
+
+	repoDeleted = true
+
+	err = waitForBundlesRemoved(client, gitRepo.Name)
+	require.NoError(t, err)
+
+	for _, clusterID := range clusterIDs {
+		logrus.Infof("Verifying git repo %s resources were removed from cluster %s...", gitRepo.Name, clusterID)
+
+		err = verifyResources(client, clusterID, resources, false)
+		require.NoError(t, err)
+	}
+}
+
+// createClusterGroup is a helper function that will create a Fleet cluster group selecting the given clusters.
+func createClusterGroup(client *rancher.Client, name string, clusterIDs []string) (*steveV1.SteveAPIObject, error) {
+	clusterGroup := &v1alpha1.ClusterGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespaces.FleetDefault,
+		},
+		Spec: v1alpha1.ClusterGroupSpec{
+			Selector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{
+						Key:      clusterNameLabel,
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   clusterIDs,
+					},
+				},
+			},
+		},
+	}
+
+	return client.Steve.SteveType(clusterGroupSteveType).Create(clusterGroup)
+}
+
+// createGitRepo is a helper function that will create a Fleet git repo targeting the given cluster group, using the
+// git credential secret created by Terraform when the git repo has a username.
+func createGitRepo(client *rancher.Client, terraformConfig *config.TerraformConfig, gitRepo config.GitRepo, clusterGroup string) (*steveV1.SteveAPIObject, error) {
+	repo := &v1alpha1.GitRepo{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      gitRepo.Name,
+			Namespace: namespaces.FleetDefault,
+		},
+		Spec: v1alpha1.GitRepoSpec{
+			Repo:            gitRepo.Repo,
+			Branch:          gitRepo.Branch,
+			Paths:           gitRepo.Paths,
+			TargetNamespace: gitRepo.TargetNamespace,
+			Targets: []v1alpha1.GitTarget{
+				{
+					ClusterGroup: clusterGroup,
+				},
+			},
+		},
+	}
+
+	if gitRepo.Username != "" {
+		repo.Spec.ClientSecretName = fleet.GitRepoSecretName(terraformConfig, gitRepo)
+	}
+
+	return client.Steve.SteveType(shepherdFleet.FleetGitRepoResourceType).Create(repo)
+}
+
+// waitForClusterGroup is a helper function that will wait until the cluster group selects the expected number of clusters.
+func waitForClusterGroup(client *rancher.Client, clusterGroupID string, expectedClusters int) error {
+	return kwait.PollUntilContextTimeout(context.TODO(), defaults.FiveSecondTimeout, defaults.FiveMinuteTimeout, true, func(ctx context.Context) (bool, error) {
+		clusterGroupObject, err := client.Steve.SteveType(clusterGroupSteveType).ByID(clusterGroupID)
+		if err != nil {
+			return false, nil
+		}
+
+		clusterGroup := &v1alpha1.ClusterGroup{}
+		err = steveV1.ConvertToK8sType(clusterGroupObject.JSONResp, clusterGroup)
+		if err != nil {
+			return false, err
+		}
+
+		return clusterGroup.Status.ClusterCount == expectedClusters, nil
+	})
+}
+
+// waitForGitRepoReady is a helper function that will wait until the git repo is ready on the expected number of
+// clusters and return the resources it deployed.
+func waitForGitRepoReady(client *rancher.Client, repoID string, expectedClusters int) ([]v1alpha1.Resource, error) {
+	var resources []v1alpha1.Resource
+
+	err := kwait.PollUntilContextTimeout(context.TODO(), defaults.FiveSecondTimeout, defaults.TenMinuteTimeout, true, func(ctx context.Context) (bool, error) {
+		repoObject, err := client.Steve.SteveType(shepherdFleet.FleetGitRepoResourceType).ByID(repoID)
+		if err != nil {
+			return false, nil
+		}
+
+		gitRepo := &v1alpha1.GitRepo{}
+		err = steveV1.ConvertToK8sType(repoObject.JSONResp, gitRepo)
+		if err != nil {
+			return false, err
+		}
+
+		if gitRepo.Status.DesiredReadyClusters != expectedClusters || gitRepo.Status.ReadyClusters != expectedClusters {
+			return false, nil
+		}
+
+		resources = gitRepo.Status.Resources
+
+		return true, nil
+	})
+
+	return resources, err
+}
+
+// waitForBundlesRemoved is a helper function that will wait until every bundle created for the git repo is removed.
+func waitForBundlesRemoved(client *rancher.Client, repoName string) error {
+	query := map[string][]string{"labelSelector": {v1alpha1.RepoLabel + "=" + repoName}}
+
+	return kwait.PollUntilContextTimeout(context.TODO(), defaults.FiveSecondTimeout, defaults.FiveMinuteTimeout, true, func(ctx context.Context) (bool, error) {
+		bundles, err := client.Steve.SteveType(bundleSteveType).NamespacedSteveClient(namespaces.FleetDefault).List(query)
+		if err != nil {
+			return false, nil
+		}
+
+		return len(bundles.Data) == 0, nil
+	})
+}
+
+// verifyResources is a helper function that will wait until every resource deployed by the git repo is present on, or
+// removed from, the downstream cluster.
+func verifyResources(client *rancher.Client, clusterID string, resources []v1alpha1.Resource, shouldExist bool) error {
+	steveClient, err := client.Steve.ProxyDownstream(clusterID)
+	if err != nil {
+		return err
+	}
+
+	return kwait.PollUntilContextTimeout(context.TODO(), defaults.FiveSecondTimeout, defaults.FiveMinuteTimeout, true, func(ctx context.Context) (bool, error) {
+		for _, resource := range resources {
+			_, err := steveClient.SteveType(resource.Type).ByID(resource.ID)
+			if shouldExist && err != nil {
+				return false, nil
+			}
+
+			if !shouldExist && err == nil {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	})
+}
@@ -0,0 +1,199 @@
+package imports
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/extensions/cloudcredentials"
+	"github.com/rancher/shepherd/extensions/cloudcredentials/aws"
+	"github.com/rancher/shepherd/extensions/defaults/stevetypes"
+	"github.com/rancher/shepherd/extensions/users"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/defaults/resourceblocks/nodeproviders/amazon"
+	"github.com/rancher/tfp-automation/framework/set/defaults"
+	"github.com/rancher/tfp-automation/framework/set/imports"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	globalRoleBinding          = "rancher2_global_role_binding"
+	project                    = "rancher2_project"
+	projectRoleTemplateBinding = "rancher2_project_role_template_binding"
+	clusterRoleTemplateBinding = "rancher2_cluster_role_template_binding"
+	rancherUser                = "rancher2_user"
+
+	clusterMember = "cluster-member"
+	projectMember = "project-member"
+	standardUser  = "user"
+
+	clusterID      = "cluster_id"
+	globalRoleID   = "global_role_id"
+	password       = "password"
+	projectID      = "project_id"
+	roleTemplateID = "role_template_id"
+	userID         = "user_id"
+	username       = "username"
+
+	cloudCredentialNameAnnotation = "field.cattle.io/name"
+	notFound                      = "404 Not Found"
+)
+
+// CreateRancherObjects is a function that will create a cluster, project, user, cloud credential and role bindings
+// with the Rancher client, outside of Terraform, and return them as resources to be imported. The AWS cloud credential
+// is only created when AWS credentials are set in the terraform config. Every object is registered for deletion in the
+// client session, so that it is removed even if the test fails before Terraform takes ownership of it.
+func CreateRancherObjects(t *testing.T, client *rancher.Client, terraformConfig *config.TerraformConfig) []imports.Resource {
+	var resources []imports.Resource
+
+	prefix := terraformConfig.ResourcePrefix
+
+	logrus.Infof("Creating Rancher objects to import...")
+	userConfig := users.UserConfig()
+
+	user, err := users.CreateUserWithRole(client, userConfig)
+	require.NoError(t, err)
+
+	resources = append(resources, imports.Resource{
+		Type: rancherUser,
+		Name: prefix,
+		ID:   user.ID,
+		Attributes: map[string]cty.Value{
+			defaults.ResourceName: cty.StringVal(user.Name),
+			username:              cty.StringVal(user.Username),
+			password:              cty.StringVal(userConfig.Password),
+			defaults.Enabled:      cty.BoolVal(true),
+		},
+		IgnoreChanges: []string{password},
+	})
+
+	globalBinding, err := client.Management.GlobalRoleBinding.Create(&management.GlobalRoleBinding{
+		GlobalRoleID: standardUser,
+		UserID:       user.ID,
+	})
+	require.NoError(t, err)
+
+	resources = append(resources, imports.Resource{
+		Type: globalRoleBinding,
+		Name: prefix,
+		ID:   globalBinding.ID,
+		Attributes: map[string]cty.Value{
+			defaults.ResourceName: cty.StringVal(globalBinding.Name),
+			globalRoleID:          cty.StringVal(standardUser),
+			userID:                cty.StringVal(user.ID),
+		},
+	})
+
+	cluster, err := client.Management.Cluster.Create(&management.Cluster{
+		Name: namegen.AppendRandomString(prefix),
+	})
+	require.NoError(t, err)
+
+	resources = append(resources, imports.Resource{
+		Type: defaults.Cluster,
+		Name: prefix,
+		ID:   cluster.ID,
+		Attributes: map[string]cty.Value{
+			defaults.ResourceName: cty.StringVal(cluster.Name),
+		},
+	})
+
+	clusterBinding, err := client.Management.ClusterRoleTemplateBinding.Create(&management.ClusterRoleTemplateBinding{
+		ClusterID:      cluster.ID,
+		RoleTemplateID: clusterMember,
+		UserID:         user.ID,
+	})
+	require.NoError(t, err)
+
+	resources = append(resources, imports.Resource{
+		Type: clusterRoleTemplateBinding,
+		Name: prefix,
+		ID:   clusterBinding.ID,
+		Attributes: map[string]cty.Value{
+			defaults.ResourceName: cty.StringVal(clusterBinding.Name),
+			clusterID:             cty.StringVal(cluster.ID),
+			roleTemplateID:        cty.StringVal(clusterMember),
+			userID:                cty.StringVal(user.ID),
+		},
+	})
+
+	createdProject, err := client.Management.Project.Create(&management.Project{
+		ClusterID: cluster.ID,
+		Name:      namegen.AppendRandomString(prefix),
+	})
+	require.NoError(t, err)
+
+	resources = append(resources, imports.Resource{
+		Type: project,
+		Name: prefix,
+		ID:   createdProject.ID,
+		Attributes: map[string]cty.Value{
+			defaults.ResourceName: cty.StringVal(createdProject.Name),
+			clusterID:             cty.StringVal(cluster.ID),
+		},
+	})
+
+	projectBinding, err := client.Management.ProjectRoleTemplateBinding.Create(&management.ProjectRoleTemplateBinding{
+		ProjectID:      createdProject.ID,
+		RoleTemplateID: projectMember,
+		UserID:         user.ID,
+	})
+	require.NoError(t, err)
+
+	resources = append(resources, imports.Resource{
+		Type: projectRoleTemplateBinding,
+		Name: prefix,
+		ID:   projectBinding.ID,
+		Attributes: map[string]cty.Value{
+			defaults.ResourceName: cty.StringVal(projectBinding.Name),
+			projectID:             cty.StringVal(createdProject.ID),
+			roleTemplateID:        cty.StringVal(projectMember),
+			userID:                cty.StringVal(user.ID),
+		},
+	})
+
+	if terraformConfig.AWSCredentials.AWSAccessKey != "" {
+		credentials := cloudcredentials.CloudCredential{
+			AmazonEC2CredentialConfig: &cloudcredentials.AmazonEC2CredentialConfig{
+				AccessKey:     terraformConfig.AWSCredentials.AWSAccessKey,
+				SecretKey:     terraformConfig.AWSCredentials.AWSSecretKey,
+				DefaultRegion: terraformConfig.AWSConfig.Region,
+			},
+		}
+
+		cloudCredential, err := aws.CreateAWSCloudCredentials(client, credentials)
+		require.NoError(t, err)
+
+		client.Session.RegisterCleanupFunc(func() error {
+			err := client.Steve.SteveType(stevetypes.Secret).Delete(cloudCredential)
+			if err != nil && strings.Contains(err.Error(), notFound) {
+				return nil
+			}
+
+			return err
+		})
+
+		resources = append(resources, imports.Resource{
+			Type: defaults.CloudCredential,
+			Name: prefix,
+			ID:   strings.Replace(cloudCredential.ID, "/", ":", 1),
+			Attributes: map[string]cty.Value{
+				defaults.ResourceName: cty.StringVal(cloudCredential.Annotations[cloudCredentialNameAnnotation]),
+			},
+			Blocks: map[string]map[string]cty.Value{
+				amazon.EC2CredentialConfig: {
+					defaults.AccessKey:   cty.StringVal(terraformConfig.AWSCredentials.AWSAccessKey),
+					defaults.SecretKey:   cty.StringVal(terraformConfig.AWSCredentials.AWSSecretKey),
+					amazon.DefaultRegion: cty.StringVal(terraformConfig.AWSConfig.Region),
+				},
+			},
+			IgnoreChanges: []string{amazon.EC2CredentialConfig},
+		})
+	}
+
+	return resources
+}
@@ -0,0 +1,30 @@
+package imports
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/shepherd/clients/rancher"
+	"github.com/rancher/tfp-automation/config"
+	framework "github.com/rancher/tfp-automation/framework/set"
+	"github.com/rancher/tfp-automation/tests/extensions/provisioning"
+	"github.com/stretchr/testify/require"
+)
+
+// ImportRoundTrip is a function that will create Rancher objects outside of Terraform, import them with Terraform
+// import blocks and verify that the plan is empty both before and after the import is applied.
+func ImportRoundTrip(t *testing.T, client *rancher.Client, terraformConfig *config.TerraformConfig, terraformOptions *terraform.Options,
+	configMap []map[string]any, newFile *hclwrite.File, rootBody *hclwrite.Body, file *os.File) {
+	importedResources := CreateRancherObjects(t, client, terraformConfig)
+
+	err := framework.ImportConfig(configMap, importedResources, newFile, rootBody, file)
+	require.NoError(t, err)
+
+	terraform.Init(t, terraformOptions)
+	provisioning.VerifyNoDrift(t, terraformOptions)
+
+	terraform.Apply(t, terraformOptions)
+	provisioning.VerifyNoDrift(t, terraformOptions)
+}
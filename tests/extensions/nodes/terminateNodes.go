@@ -0,0 +1,126 @@
+package nodes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rancher/norman/types"
+	"github.com/rancher/shepherd/clients/rancher"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	"github.com/rancher/shepherd/extensions/clusters"
+	"github.com/rancher/shepherd/extensions/defaults"
+	"github.com/rancher/shepherd/extensions/defaults/namespaces"
+	"github.com/rancher/shepherd/extensions/sshkeys"
+	"github.com/sirupsen/logrus"
+	kwait "k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	DeleteMachineAnnotation = "cluster.x-k8s.io/delete-machine"
+	nodeRoleLabelPrefix     = "node-role.kubernetes.io/"
+	nodeSteveType           = "node"
+	poweroffCommand         = "sudo systemctl poweroff --no-block"
+	unavailable             = "unavailable"
+)
+
+// GetNodesByRole is a function that will return the downstream nodes of the given cluster that have the given role,
+// e.g. etcd or control-plane.
+func GetNodesByRole(client *rancher.Client, clusterID, role string) ([]steveV1.SteveAPIObject, error) {
+	steveclient, err := client.Steve.ProxyDownstream(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := map[string][]string{"labelSelector": {nodeRoleLabelPrefix + role + "=true"}}
+
+	nodes, err := steveclient.SteveType(nodeSteveType).List(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return nodes.Data, nil
+}
+
+// TerminateNodes is a function that will power off the given downstream nodes over SSH, outside of Terraform and
+// Rancher, to simulate the loss of the nodes.
+func TerminateNodes(client *rancher.Client, clusterName string, nodes []steveV1.SteveAPIObject) error {
+	_, clusterSteveObject, err := clusters.GetProvisioningClusterByName(client, clusterName, namespaces.FleetDefault)
+	if err != nil {
+		return err
+	}
+
+	sshUser, err := sshkeys.GetSSHUser(client, clusterSteveObject)
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes {
+		sshNode, err := sshkeys.GetSSHNodeFromMachine(client, sshUser, &node)
+		if err != nil {
+			return err
+		}
+
+		logrus.Infof("Terminating node %s...", node.Name)
+
+		// The SSH session is closed by the node powering off, so the command's error is not reliable.
+		_, _ = sshNode.ExecuteCommand(poweroffCommand)
+	}
+
+	return nil
+}
+
+// WaitForNodesUnavailable is a function that will wait until Rancher reports the given downstream nodes as unavailable.
+func WaitForNodesUnavailable(client *rancher.Client, clusterID string, nodeNames []string) error {
+	return kwait.PollUntilContextTimeout(context.TODO(), defaults.FiveSecondTimeout, defaults.TenMinuteTimeout, true, func(ctx context.Context) (bool, error) {
+		nodes, err := client.Management.Node.List(&types.ListOpts{
+			Filters: map[string]interface{}{
+				"clusterId": clusterID,
+			},
+		})
+		if err != nil {
+			return false, nil
+		}
+
+		states := map[string]string{}
+		for _, node := range nodes.Data {
+			states[node.NodeName] = node.State
+		}
+
+		for _, nodeName := range nodeNames {
+			if states[nodeName] != unavailable {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	})
+}
+
+// MarkMachinesForDeletion is a function that will annotate the machines of the given downstream nodes, so that they are
+// the first machines removed when their machine pool is scaled down.
+func MarkMachinesForDeletion(client *rancher.Client, nodes []steveV1.SteveAPIObject) error {
+	for _, node := range nodes {
+		machineName, ok := node.Annotations[sshkeys.ClusterMachineAnnotation]
+		if !ok {
+			return fmt.Errorf("node %s has no %s annotation", node.Name, sshkeys.ClusterMachineAnnotation)
+		}
+
+		machine, err := client.Steve.SteveType(sshkeys.ClusterMachineConstraintResourceSteveType).ByID(namespaces.FleetDefault + "/" + machineName)
+		if err != nil {
+			return err
+		}
+
+		if machine.Annotations == nil {
+			machine.Annotations = map[string]string{}
+		}
+
+		machine.Annotations[DeleteMachineAnnotation] = "true"
+
+		_, err = client.Steve.SteveType(sshkeys.ClusterMachineConstraintResourceSteveType).Update(machine, machine)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
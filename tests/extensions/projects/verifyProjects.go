@@ -0,0 +1,279 @@
+package projects
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/rancher/norman/clientbase"
+	"github.com/rancher/norman/types"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	"github.com/rancher/shepherd/extensions/defaults"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kwait "k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	containerImage = "nginx"
+	exceededQuota  = "exceeded quota"
+	namespaceType  = "namespace"
+	podType        = "pod"
+)
+
+// VerifyProjects is a function that will verify that the projects declared in the terratest config have the expected
+// resource quotas and container default limits, that the container default limits are injected into pods and that
+// pods over the namespace quota are rejected.
+func VerifyProjects(t *testing.T, client *rancher.Client, clusterID string, projects []config.Project) {
+	steveClient, err := client.Steve.ProxyDownstream(clusterID)
+	require.NoError(t, err)
+
+	for _, tfpProject := range projects {
+		logrus.Infof("Verifying project %s...", tfpProject.Name)
+
+		projectList, err := client.Management.Project.List(&types.ListOpts{
+			Filters: map[string]interface{}{"clusterId": clusterID, "name": tfpProject.Name},
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, projectList.Data, "Project %s not found in cluster %s", tfpProject.Name, clusterID)
+
+		project := projectList.Data[0]
+
+		if tfpProject.ResourceQuota != nil {
+			require.NotNil(t, project.ResourceQuota, "Project %s has no resource quota", tfpProject.Name)
+
+			mismatches, err := compareQuotaLimits(tfpProject.ResourceQuota, project.ResourceQuota.Limit)
+			require.NoError(t, err)
+			require.Empty(t, mismatches)
+		}
+
+		if tfpProject.NamespaceDefaultLimit != nil {
+			require.NotNil(t, project.NamespaceDefaultResourceQuota, "Project %s has no namespace default limit", tfpProject.Name)
+
+			mismatches, err := compareQuotaLimits(tfpProject.NamespaceDefaultLimit, project.NamespaceDefaultResourceQuota.Limit)
+			require.NoError(t, err)
+			require.Empty(t, mismatches)
+		}
+
+		if tfpProject.ContainerResourceLimit != nil {
+			mismatches, err := compareContainerLimits(tfpProject.ContainerResourceLimit, project.ContainerDefaultResourceLimit)
+			require.NoError(t, err)
+			require.Empty(t, mismatches)
+		}
+
+		for _, tfpNamespace := range tfpProject.Namespaces {
+			logrus.Infof("Verifying namespace %s...", tfpNamespace.Name)
+
+			err = kwait.PollUntilContextTimeout(context.TODO(), defaults.FiveSecondTimeout, defaults.TwoMinuteTimeout, true, func(ctx context.Context) (bool, error) {
+				_, err := steveClient.SteveType(namespaceType).ByID(tfpNamespace.Name)
+				return err == nil, nil
+			})
+			require.NoError(t, err, "Namespace %s not found", tfpNamespace.Name)
+
+			containerLimit := tfpNamespace.ContainerResourceLimit
+			if containerLimit == nil {
+				containerLimit = tfpProject.ContainerResourceLimit
+			}
+
+			if containerLimit != nil {
+				verifyDefaultLimits(t, steveClient, tfpNamespace.Name, containerLimit)
+			}
+
+			namespaceQuota := tfpNamespace.ResourceQuota
+			if namespaceQuota == nil {
+				namespaceQuota = tfpProject.NamespaceDefaultLimit
+			}
+
+			if namespaceQuota != nil && namespaceQuota.LimitsCPU != "" {
+				verifyOverQuotaRejected(t, steveClient, tfpNamespace.Name, namespaceQuota)
+			}
+		}
+	}
+}
+
+// verifyDefaultLimits is a helper function that will create a pod without resources in the namespace and verify that
+// the container default limits were injected.
+func verifyDefaultLimits(t *testing.T, steveClient *steveV1.Client, namespace string, containerLimit *management.ContainerResourceLimit) {
+	podResp, err := steveClient.SteveType(podType).Create(newPod(namespace, corev1.ResourceRequirements{}))
+	require.NoError(t, err)
+
+	defer deletePod(t, steveClient, podResp)
+
+	pod := &corev1.Pod{}
+	err = steveV1.ConvertToK8sType(podResp.JSONResp, pod)
+	require.NoError(t, err)
+
+	resources := pod.Spec.Containers[0].Resources
+	quantities := []struct {
+		name     string
+		expected string
+		actual   *resource.Quantity
+	}{
+		{"limits cpu", containerLimit.LimitsCPU, resources.Limits.Cpu()},
+		{"limits memory", containerLimit.LimitsMemory, resources.Limits.Memory()},
+		{"requests cpu", containerLimit.RequestsCPU, resources.Requests.Cpu()},
+		{"requests memory", containerLimit.RequestsMemory, resources.Requests.Memory()},
+	}
+
+	mismatches := []string{}
+	for _, quantity := range quantities {
+		mismatch, err := compareQuantity(quantity.name, quantity.expected, quantity.actual)
+		require.NoError(t, err)
+
+		mismatches = append(mismatches, mismatch...)
+	}
+
+	require.Empty(t, mismatches, "Container default limits were not injected into pod in namespace %s", namespace)
+}
+
+// verifyOverQuotaRejected is a helper function that will create a pod with a CPU limit over the namespace quota and
+// verify that it is denied by the resource quota admission.
+func verifyOverQuotaRejected(t *testing.T, steveClient *steveV1.Client, namespace string, namespaceQuota *management.ResourceQuotaLimit) {
+	overQuota, err := resource.ParseQuantity(namespaceQuota.LimitsCPU)
+	require.NoError(t, err)
+
+	overQuota.Add(overQuota)
+
+	resources := corev1.ResourceRequirements{
+		Limits:   corev1.ResourceList{corev1.ResourceCPU: overQuota},
+		Requests: corev1.ResourceList{corev1.ResourceCPU: overQuota},
+	}
+
+	podResp, err := steveClient.SteveType(podType).Create(newPod(namespace, resources))
+	if err == nil {
+		deletePod(t, steveClient, podResp)
+	}
+
+	apiErr := &clientbase.APIError{}
+	require.ErrorAs(t, err, &apiErr, "Pod with a CPU limit of %s was not rejected by the quota of namespace %s", overQuota.String(), namespace)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+	require.Contains(t, apiErr.Body, exceededQuota)
+}
+
+// deletePod is a helper function that will delete the pod and wait for it to be removed, so that it does not count
+// against the namespace quota of the following checks.
+func deletePod(t *testing.T, steveClient *steveV1.Client, podResp *steveV1.SteveAPIObject) {
+	err := steveClient.SteveType(podType).Delete(podResp)
+	require.NoError(t, err)
+
+	err = kwait.PollUntilContextTimeout(context.TODO(), defaults.FiveSecondTimeout, defaults.TwoMinuteTimeout, true, func(ctx context.Context) (bool, error) {
+		_, err := steveClient.SteveType(podType).ByID(podResp.ID)
+		return clientbase.IsNotFound(err), nil
+	})
+	require.NoError(t, err, "Pod %s was not removed", podResp.ID)
+}
+
+// newPod is a helper function that will return a pod template with the given resources.
+func newPod(namespace string, resources corev1.ResourceRequirements) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      namegen.AppendRandomString("tfp-quota"),
+			Namespace: namespace,
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:      containerImage,
+					Image:     containerImage,
+					Resources: resources,
+				},
+			},
+		},
+	}
+}
+
+// compareQuotaLimits is a helper function that will return the resource quota limits that do not match.
+func compareQuotaLimits(expected, actual *management.ResourceQuotaLimit) ([]string, error) {
+	if actual == nil {
+		actual = &management.ResourceQuotaLimit{}
+	}
+
+	return compareValues([][3]string{
+		{"config maps", expected.ConfigMaps, actual.ConfigMaps},
+		{"limits cpu", expected.LimitsCPU, actual.LimitsCPU},
+		{"limits memory", expected.LimitsMemory, actual.LimitsMemory},
+		{"persistent volume claims", expected.PersistentVolumeClaims, actual.PersistentVolumeClaims},
+		{"pods", expected.Pods, actual.Pods},
+		{"replication controllers", expected.ReplicationControllers, actual.ReplicationControllers},
+		{"requests cpu", expected.RequestsCPU, actual.RequestsCPU},
+		{"requests memory", expected.RequestsMemory, actual.RequestsMemory},
+		{"requests storage", expected.RequestsStorage, actual.RequestsStorage},
+		{"secrets", expected.Secrets, actual.Secrets},
+		{"services", expected.Services, actual.Services},
+		{"services load balancers", expected.ServicesLoadBalancers, actual.ServicesLoadBalancers},
+		{"services node ports", expected.ServicesNodePorts, actual.ServicesNodePorts},
+	})
+}
+
+// compareContainerLimits is a helper function that will return the container default limits that do not match.
+func compareContainerLimits(expected, actual *management.ContainerResourceLimit) ([]string, error) {
+	if actual == nil {
+		actual = &management.ContainerResourceLimit{}
+	}
+
+	return compareValues([][3]string{
+		{"limits cpu", expected.LimitsCPU, actual.LimitsCPU},
+		{"limits memory", expected.LimitsMemory, actual.LimitsMemory},
+		{"requests cpu", expected.RequestsCPU, actual.RequestsCPU},
+		{"requests memory", expected.RequestsMemory, actual.RequestsMemory},
+	})
+}
+
+// compareValues is a helper function that will compare a list of name, expected and actual quantities given as
+// strings, and return the ones that do not match.
+func compareValues(values [][3]string) ([]string, error) {
+	mismatches := []string{}
+
+	for _, value := range values {
+		mismatch, err := compareValue(value[0], value[1], value[2])
+		if err != nil {
+			return nil, err
+		}
+
+		mismatches = append(mismatches, mismatch...)
+	}
+
+	return mismatches, nil
+}
+
+// compareValue is a helper function that will compare two quantities given as strings. Values that are not set in the
+// config are skipped.
+func compareValue(name, expected, actual string) ([]string, error) {
+	if expected == "" {
+		return nil, nil
+	}
+
+	actualQuantity, err := resource.ParseQuantity(actual)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: expected %s, got %q", name, expected, actual)}, nil
+	}
+
+	return compareQuantity(name, expected, &actualQuantity)
+}
+
+// compareQuantity is a helper function that will compare the expected quantity with the actual one. An error is
+// returned if the expected quantity from the config cannot be parsed.
+func compareQuantity(name, expected string, actual *resource.Quantity) ([]string, error) {
+	if expected == "" {
+		return nil, nil
+	}
+
+	expectedQuantity, err := resource.ParseQuantity(expected)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s quantity %q: %w", name, expected, err)
+	}
+
+	if expectedQuantity.Cmp(*actual) != 0 {
+		return []string{fmt.Sprintf("%s: expected %s, got %s", name, expectedQuantity.String(), actual.String())}, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,167 @@
+package provisioning
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/shepherd/clients/rancher"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	clusterExtensions "github.com/rancher/shepherd/extensions/clusters"
+	"github.com/rancher/shepherd/extensions/defaults"
+	"github.com/rancher/shepherd/pkg/config/operations"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/defaults/clustertypes"
+	framework "github.com/rancher/tfp-automation/framework/set"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	kwait "k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	nodeSteveType = "node"
+	rke1Suffix    = "-rancher"
+)
+
+// ChainedKubernetesUpgrade is a function that will upgrade the Kubernetes version of the provisioned clusters through
+// each version of their kubernetesUpgradePath, running terraform apply once per hop. Every entry of the configMap
+// follows its own path; an entry with a shorter path stays on its last version for the remaining hops. Between hops,
+// the version of every node is verified and a probe workload records the downtime of each cluster, which must not exceed
+// the maxUpgradeDowntime of the cluster entry when it is set.
+func ChainedKubernetesUpgrade(t *testing.T, client *rancher.Client, testUser, testPassword string, terraformOptions *terraform.Options,
+	configMap []map[string]any, newFile *hclwrite.File, rootBody *hclwrite.Body, file *os.File, isWindows bool) []string {
+	var upgradePaths [][]string
+	var modules []string
+	var maxDowntimes []time.Duration
+	var hops int
+
+	for _, cattleConfig := range configMap {
+		terraformConfig := new(config.TerraformConfig)
+		operations.LoadObjectFromMap(config.TerraformConfigurationFileKey, cattleConfig, terraformConfig)
+
+		terratestConfig := new(config.TerratestConfig)
+		operations.LoadObjectFromMap(config.TerratestConfigurationFileKey, cattleConfig, terratestConfig)
+
+		var maxDowntime time.Duration
+		if terratestConfig.MaxUpgradeDowntime != "" {
+			var err error
+			maxDowntime, err = time.ParseDuration(terratestConfig.MaxUpgradeDowntime)
+			require.NoError(t, err)
+		}
+
+		upgradePaths = append(upgradePaths, terratestConfig.KubernetesUpgradePath)
+		modules = append(modules, terraformConfig.Module)
+		maxDowntimes = append(maxDowntimes, maxDowntime)
+		hops = max(hops, len(terratestConfig.KubernetesUpgradePath))
+	}
+
+	require.NotZero(t, hops, "kubernetesUpgradePath must be set for at least one cluster")
+
+	var clusterIDs []string
+	var probes []*UpgradeProbe
+
+	for hop := 0; hop < hops; hop++ {
+		for i, upgradePath := range upgradePaths {
+			if len(upgradePath) == 0 {
+				continue
+			}
+
+			version := upgradePath[min(hop, len(upgradePath)-1)]
+
+			_, err := operations.ReplaceValue([]string{"terratest", "kubernetesVersion"}, version, configMap[i])
+			require.NoError(t, err)
+		}
+
+		clusterNames, _, err := framework.ConfigTF(client, testUser, testPassword, "", configMap, newFile, rootBody, file, isWindows, false, false, nil)
+		require.NoError(t, err)
+
+		if probes == nil {
+			for _, clusterName := range clusterNames {
+				clusterID, err := clusterExtensions.GetClusterIDByName(client, clusterName)
+				require.NoError(t, err)
+
+				probe, err := NewUpgradeProbe(client, clusterID)
+				require.NoError(t, err)
+
+				probe.Start()
+
+				clusterIDs = append(clusterIDs, clusterID)
+				probes = append(probes, probe)
+			}
+		}
+
+		for i, probe := range probes {
+			probe.SetHop(getKubernetesVersion(configMap[i]))
+		}
+
+		logrus.Infof("Upgrading clusters, hop %d of %d...", hop+1, hops)
+		terraform.Apply(t, terraformOptions)
+		CheckDrift(t, terraformOptions, configMap)
+
+		for i, clusterID := range clusterIDs {
+			err = clusterExtensions.WaitClusterToBeUpgraded(client, clusterID)
+			require.NoError(t, err)
+
+			VerifyNodesKubernetesVersion(t, client, clusterID, getKubernetesVersion(configMap[i]), modules[i])
+		}
+	}
+
+	for i, probe := range probes {
+		err := probe.Stop()
+		require.NoError(t, err)
+
+		if maxDowntimes[i] > 0 {
+			err = probe.VerifyDowntime(maxDowntimes[i])
+			require.NoError(t, err)
+		}
+	}
+
+	return clusterIDs
+}
+
+// VerifyNodesKubernetesVersion validates that every node of the cluster runs the expected Kubernetes version, by
+// checking the kubelet version of the nodes through the steve API.
+func VerifyNodesKubernetesVersion(t *testing.T, client *rancher.Client, clusterID, expectedKubernetesVersion, module string) {
+	if strings.Contains(module, clustertypes.RKE1) {
+		expectedKubernetesVersion = strings.Split(expectedKubernetesVersion, rke1Suffix)[0]
+	}
+
+	steveclient, err := client.Steve.ProxyDownstream(clusterID)
+	require.NoError(t, err)
+
+	err = kwait.PollUntilContextTimeout(context.TODO(), defaults.FiveSecondTimeout, defaults.TenMinuteTimeout, true, func(ctx context.Context) (bool, error) {
+		nodes, err := steveclient.SteveType(nodeSteveType).List(nil)
+		if err != nil {
+			return false, nil
+		}
+
+		for _, nodeObject := range nodes.Data {
+			node := &corev1.Node{}
+			err = steveV1.ConvertToK8sType(nodeObject.JSONResp, node)
+			if err != nil {
+				return false, err
+			}
+
+			if node.Status.NodeInfo.KubeletVersion != expectedKubernetesVersion {
+				logrus.Infof("Node %s is on %s, waiting for %s...", node.Name, node.Status.NodeInfo.KubeletVersion, expectedKubernetesVersion)
+				return false, nil
+			}
+		}
+
+		return len(nodes.Data) > 0, nil
+	})
+	require.NoError(t, err, "not every node of cluster %s is on %s", clusterID, expectedKubernetesVersion)
+}
+
+// getKubernetesVersion returns the Kubernetes version currently set in the given config.
+func getKubernetesVersion(cattleConfig map[string]any) string {
+	terratestConfig := new(config.TerratestConfig)
+	operations.LoadObjectFromMap(config.TerratestConfigurationFileKey, cattleConfig, terratestConfig)
+
+	return terratestConfig.KubernetesVersion
+}
@@ -0,0 +1,217 @@
+package provisioning
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/rancher/shepherd/pkg/config/operations"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	driftPlanFile = "drift.tfplan"
+	noChanges     = 0
+)
+
+// GetDrift is a function that will run terraform plan -detailed-exitcode against the applied configuration and return
+// every drifting attribute of the given resource types, formatted as <address>: <action> <attribute>. If no resource
+// types are given, every resource in the plan is checked.
+func GetDrift(t *testing.T, terraformOptions *terraform.Options, resourceTypes ...string) []string {
+	planOptions := *terraformOptions
+	planOptions.PlanFilePath = filepath.Join(terraformOptions.TerraformDir, driftPlanFile)
+	defer os.Remove(planOptions.PlanFilePath)
+
+	logrus.Infof("Running terraform plan to check for drift...")
+	exitCode := terraform.PlanExitCode(t, &planOptions)
+	if exitCode == noChanges {
+		return nil
+	}
+
+	plan := terraform.ShowWithStruct(t, &planOptions)
+
+	var drift []string
+	for _, address := range sortedAddresses(plan.ResourceChangesMap) {
+		resourceChange := plan.ResourceChangesMap[address]
+		if !isResourceType(resourceChange.Type, resourceTypes) {
+			continue
+		}
+
+		drift = append(drift, getResourceDrift(resourceChange)...)
+	}
+
+	return drift
+}
+
+// VerifyNoDrift is a function that will fail the test if terraform plan shows changes to the given resource types after
+// an apply. Each drifting attribute is listed in the failure message.
+func VerifyNoDrift(t *testing.T, terraformOptions *terraform.Options, resourceTypes ...string) {
+	drift := GetDrift(t, terraformOptions, resourceTypes...)
+	for _, attribute := range drift {
+		logrus.Warnf("Drift detected: %s", attribute)
+	}
+
+	require.Empty(t, drift, "Terraform plan is not empty after apply")
+}
+
+// CheckDrift is a function that will verify that the last apply converged when terratest.verifyNoDrift is enabled in
+// the config. It is a no-op otherwise.
+func CheckDrift(t *testing.T, terraformOptions *terraform.Options, configMap []map[string]any) {
+	terratest := new(config.TerratestConfig)
+	operations.LoadObjectFromMap(config.TerratestConfigurationFileKey, configMap[0], terratest)
+
+	if !terratest.VerifyNoDrift {
+		return
+	}
+
+	VerifyNoDrift(t, terraformOptions)
+}
+
+// getResourceDrift is a helper function that will list the drifting attributes of a single planned resource change.
+func getResourceDrift(resourceChange *tfjson.ResourceChange) []string {
+	actions := resourceChange.Change.Actions
+	if actions.NoOp() || actions.Read() {
+		return nil
+	}
+
+	action := "update"
+	switch {
+	case actions.Replace():
+		action = "replace"
+	case actions.Create():
+		action = "create"
+	case actions.Delete():
+		action = "delete"
+	}
+
+	if !actions.Update() && !actions.Replace() {
+		return []string{fmt.Sprintf("%s: %s", resourceChange.Address, action)}
+	}
+
+	attributes := diffAttributes("", resourceChange.Change.Before, resourceChange.Change.After, resourceChange.Change.AfterUnknown)
+
+	var drift []string
+	for _, attribute := range attributes {
+		drift = append(drift, fmt.Sprintf("%s: %s %s", resourceChange.Address, action, attribute))
+	}
+
+	if len(drift) == 0 {
+		drift = append(drift, fmt.Sprintf("%s: %s", resourceChange.Address, action))
+	}
+
+	return drift
+}
+
+// diffAttributes is a helper function that will walk the before and after values of a planned change and return the
+// path of every attribute that differs or is only known after apply.
+func diffAttributes(path string, before, after, afterUnknown any) []string {
+	if unknown, ok := afterUnknown.(bool); ok && unknown {
+		return []string{path}
+	}
+
+	beforeMap, beforeIsMap := before.(map[string]any)
+	afterMap, afterIsMap := after.(map[string]any)
+	if beforeIsMap || afterIsMap {
+		unknownMap, _ := afterUnknown.(map[string]any)
+
+		keys := map[string]bool{}
+		for key := range beforeMap {
+			keys[key] = true
+		}
+
+		for key := range afterMap {
+			keys[key] = true
+		}
+
+		for key := range unknownMap {
+			keys[key] = true
+		}
+
+		var sortedKeys []string
+		for key := range keys {
+			sortedKeys = append(sortedKeys, key)
+		}
+
+		sort.Strings(sortedKeys)
+
+		var attributes []string
+		for _, key := range sortedKeys {
+			attributes = append(attributes, diffAttributes(joinPath(path, key), beforeMap[key], afterMap[key], unknownMap[key])...)
+		}
+
+		return attributes
+	}
+
+	beforeList, beforeIsList := before.([]any)
+	afterList, afterIsList := after.([]any)
+	if beforeIsList || afterIsList {
+		unknownList, _ := afterUnknown.([]any)
+
+		length := max(len(beforeList), len(afterList), len(unknownList))
+
+		var attributes []string
+		for i := 0; i < length; i++ {
+			attributes = append(attributes, diffAttributes(joinPath(path, strconv.Itoa(i)), listItem(beforeList, i),
+				listItem(afterList, i), listItem(unknownList, i))...)
+		}
+
+		return attributes
+	}
+
+	if !reflect.DeepEqual(before, after) {
+		return []string{path}
+	}
+
+	return nil
+}
+
+// isResourceType is a helper function that will check if the resource type is one of the given types. An empty list
+// matches every resource type.
+func isResourceType(resourceType string, resourceTypes []string) bool {
+	if len(resourceTypes) == 0 {
+		return true
+	}
+
+	for _, expectedType := range resourceTypes {
+		if resourceType == expectedType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortedAddresses(resourceChanges map[string]*tfjson.ResourceChange) []string {
+	var addresses []string
+	for address := range resourceChanges {
+		addresses = append(addresses, address)
+	}
+
+	sort.Strings(addresses)
+
+	return addresses
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+func listItem(list []any, index int) any {
+	if index < len(list) {
+		return list[index]
+	}
+
+	return nil
+}
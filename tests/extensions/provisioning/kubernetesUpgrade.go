@@ -1,14 +1,19 @@
 package provisioning
 
 import (
+	"context"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/gruntwork-io/terratest/modules/terraform"
 	"github.com/hashicorp/hcl/v2/hclwrite"
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
 	"github.com/rancher/shepherd/clients/rancher"
 	clusterExtensions "github.com/rancher/shepherd/extensions/clusters"
+	"github.com/rancher/shepherd/pkg/config/operations"
 	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/defaults/clustertypes"
 	framework "github.com/rancher/tfp-automation/framework/set"
 	"github.com/stretchr/testify/require"
 )
@@ -27,13 +32,45 @@ func KubernetesUpgrade(t *testing.T, client *rancher.Client, rancherConfig *ranc
 	clusterNames, customClusterNames, err := framework.ConfigTF(client, testUser, testPassword, "", configMap, newFile, rootBody, file, isWindows, false, false, nil)
 	require.NoError(t, err)
 
-	terraform.Apply(t, terraformOptions)
+	var watchers []*CordonWatcher
+	var upgradeStrategies []*rkev1.ClusterUpgradeStrategy
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	for _, clusterName := range clusterNames {
+	for i, clusterName := range clusterNames {
 		clusterID, err := clusterExtensions.GetClusterIDByName(client, clusterName)
 		require.NoError(t, err)
 
 		clusterIDs = append(clusterIDs, clusterID)
+
+		if i >= len(configMap) {
+			continue
+		}
+
+		clusterConfig := new(config.TerraformConfig)
+		operations.LoadObjectFromMap(config.TerraformConfigurationFileKey, configMap[i], clusterConfig)
+
+		if clusterConfig.UpgradeStrategy == nil || strings.Contains(clusterConfig.Module, clustertypes.RKE1) {
+			continue
+		}
+
+		watcher, err := WatchCordonedNodes(ctx, client, clusterID)
+		require.NoError(t, err)
+
+		watchers = append(watchers, watcher)
+		upgradeStrategies = append(upgradeStrategies, clusterConfig.UpgradeStrategy)
+	}
+
+	terraform.Apply(t, terraformOptions)
+	CheckDrift(t, terraformOptions, configMap)
+
+	for i, watcher := range watchers {
+		err = clusterExtensions.WaitClusterToBeUpgraded(client, watcher.ClusterID)
+		require.NoError(t, err)
+
+		watcher.Stop()
+		VerifyUpgradeConcurrency(t, watcher, upgradeStrategies[i])
 	}
 
 	return clusterIDs, customClusterNames
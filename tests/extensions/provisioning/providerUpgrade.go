@@ -0,0 +1,91 @@
+package provisioning
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/shepherd/clients/rancher"
+	"github.com/rancher/shepherd/pkg/config/operations"
+	"github.com/rancher/tfp-automation/config"
+	framework "github.com/rancher/tfp-automation/framework/set"
+	"github.com/rancher/tfp-automation/framework/set/defaults"
+	"github.com/rancher/tfp-automation/framework/versions"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// ProviderUpgrade is a function that will rewrite the required rancher2 provider of every config to the upgraded provider
+// version, run terraform init -upgrade and verify that the plan does not change or replace the provisioned clusters. The
+// provider source is pinned to the one used for the original apply, so that the provider address in the state does not
+// change.
+func ProviderUpgrade(t *testing.T, client *rancher.Client, terraformConfig *config.TerraformConfig,
+	terratestConfig *config.TerratestConfig, testUser, testPassword string, terraformOptions *terraform.Options, configMap []map[string]any,
+	newFile *hclwrite.File, rootBody *hclwrite.Body, file *os.File) {
+	require.NotEmpty(t, terratestConfig.UpgradedProviderVersion, "terratest.upgradedProviderVersion must be set")
+
+	rancher2Source, _ := versions.GetProviderVersion(terraformConfig, defaults.Rancher2)
+	logrus.Infof("Upgrading rancher2 provider from %s to version %s...", rancher2Source, terratestConfig.UpgradedProviderVersion)
+
+	for _, cattleConfig := range configMap {
+		clusterConfig := new(config.TerraformConfig)
+		operations.LoadObjectFromMap(config.TerraformConfigurationFileKey, cattleConfig, clusterConfig)
+
+		providers := upgradedProviders(clusterConfig, rancher2Source, terratestConfig.UpgradedProviderVersion)
+
+		_, err := operations.ReplaceValue([]string{"terraform", "providers"}, providers, cattleConfig)
+		require.NoError(t, err)
+	}
+
+	upgradedTerraformConfig := new(config.TerraformConfig)
+	operations.LoadObjectFromMap(config.TerraformConfigurationFileKey, configMap[0], upgradedTerraformConfig)
+
+	err := versions.ValidateProviders(upgradedTerraformConfig)
+	require.NoError(t, err)
+
+	cliConfigPath, err := versions.SetCLIConfig(upgradedTerraformConfig, terraformOptions.TerraformDir)
+	require.NoError(t, err)
+
+	if cliConfigPath != "" {
+		if terraformOptions.EnvVars == nil {
+			terraformOptions.EnvVars = map[string]string{}
+		}
+
+		terraformOptions.EnvVars[versions.CLIConfigEnvVar] = cliConfigPath
+	}
+
+	_, _, err = framework.ConfigTF(client, testUser, testPassword, "", configMap, newFile, rootBody, file, false, false, false, nil)
+	require.NoError(t, err)
+
+	upgradeOptions := *terraformOptions
+	upgradeOptions.Upgrade = true
+
+	terraform.Init(t, &upgradeOptions)
+
+	VerifyNoDrift(t, terraformOptions, defaults.ClusterV2, defaults.MachineConfigV2)
+}
+
+// upgradedProviders is a helper function that will return a copy of the providers section of the given config with the
+// rancher2 provider pinned to the given source and version, and without its dev override.
+func upgradedProviders(terraformConfig *config.TerraformConfig, source, version string) *config.Providers {
+	providers := new(config.Providers)
+	if terraformConfig.Providers != nil {
+		*providers = *terraformConfig.Providers
+	}
+
+	providerVersions := map[string]config.TerraformProvider{}
+	for name, provider := range providers.Versions {
+		providerVersions[name] = provider
+	}
+
+	rancher2Provider := providerVersions[defaults.Rancher2]
+	rancher2Provider.Source = source
+	rancher2Provider.Version = version
+	rancher2Provider.DevOverride = ""
+
+	providerVersions[defaults.Rancher2] = rancher2Provider
+	providers.Versions = providerVersions
+
+	return providers
+}
@@ -28,6 +28,7 @@ func Provision(t *testing.T, client *rancher.Client, rancherConfig *rancher.Conf
 	require.NoError(t, err)
 
 	terraform.InitAndApply(t, terraformOptions)
+	CheckDrift(t, terraformOptions, configMap)
 
 	for _, clusterName := range clusterNames {
 		clusterID, err := clusterExtensions.GetClusterIDByName(client, clusterName)
@@ -0,0 +1,171 @@
+package provisioning
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rancher/norman/types"
+	"github.com/rancher/shepherd/clients/rancher"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	"github.com/rancher/tfp-automation/defaults/stevetypes"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+const (
+	activeState      = "active"
+	clusterAgentID   = "cattle-system/cattle-cluster-agent"
+	clusterIDFilter  = "clusterId"
+	rancherID        = "cattle-system/rancher"
+	imageTagSplitter = ":"
+)
+
+// ClusterInventory is the state of a downstream cluster that is expected to survive a Rancher server upgrade.
+type ClusterInventory struct {
+	ID                string
+	Name              string
+	State             string
+	KubernetesVersion string
+	NodeCount         int
+	AgentImage        string
+	Workloads         map[string]bool
+}
+
+// RancherInventory is the state of the downstream clusters and the Terraform plan of their module, captured before and
+// after a Rancher server upgrade.
+type RancherInventory struct {
+	Clusters map[string]ClusterInventory
+	Drift    []string
+}
+
+// GetClusterInventory is a function that will capture the state, Kubernetes version, node count, cluster agent image
+// and deployments of the given downstream cluster. Deployments are keyed by <namespace>/<name> and mapped to whether
+// they were fully rolled out.
+func GetClusterInventory(client *rancher.Client, clusterID string) (*ClusterInventory, error) {
+	cluster, err := client.Management.Cluster.ByID(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	inventory := &ClusterInventory{
+		ID:        clusterID,
+		Name:      cluster.Name,
+		State:     cluster.State,
+		Workloads: map[string]bool{},
+	}
+
+	if cluster.Version != nil {
+		inventory.KubernetesVersion = cluster.Version.GitVersion
+	}
+
+	nodes, err := client.Management.Node.List(&types.ListOpts{Filters: map[string]interface{}{clusterIDFilter: clusterID}})
+	if err != nil {
+		return nil, err
+	}
+
+	inventory.NodeCount = len(nodes.Data)
+
+	steveclient, err := client.Steve.ProxyDownstream(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	deployments, err := steveclient.SteveType(stevetypes.Deployment).List(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, deploymentObject := range deployments.Data {
+		deployment := &appsv1.Deployment{}
+		err = steveV1.ConvertToK8sType(deploymentObject.JSONResp, deployment)
+		if err != nil {
+			return nil, err
+		}
+
+		deploymentID := deployment.Namespace + "/" + deployment.Name
+		inventory.Workloads[deploymentID] = isRolledOut(deployment)
+
+		if deploymentID == clusterAgentID && len(deployment.Spec.Template.Spec.Containers) > 0 {
+			inventory.AgentImage = deployment.Spec.Template.Spec.Containers[0].Image
+		}
+	}
+
+	return inventory, nil
+}
+
+// DiffRancherInventory is a function that will compare the inventory captured after a Rancher server upgrade with the
+// inventory captured before it. Every downstream cluster must still be active on the same Kubernetes version with the
+// same number of nodes, and every deployment that was rolled out before the upgrade must still be rolled out.
+func DiffRancherInventory(before, after *RancherInventory) []string {
+	var diff []string
+
+	for _, clusterID := range sortedKeys(before.Clusters) {
+		beforeCluster := before.Clusters[clusterID]
+
+		afterCluster, ok := after.Clusters[clusterID]
+		if !ok {
+			diff = append(diff, fmt.Sprintf("cluster %s: missing after the upgrade", beforeCluster.Name))
+			continue
+		}
+
+		if afterCluster.State != activeState {
+			diff = append(diff, fmt.Sprintf("cluster %s: state is %s", afterCluster.Name, afterCluster.State))
+		}
+
+		if afterCluster.KubernetesVersion != beforeCluster.KubernetesVersion {
+			diff = append(diff, fmt.Sprintf("cluster %s: Kubernetes version changed from %s to %s", afterCluster.Name,
+				beforeCluster.KubernetesVersion, afterCluster.KubernetesVersion))
+		}
+
+		if afterCluster.NodeCount != beforeCluster.NodeCount {
+			diff = append(diff, fmt.Sprintf("cluster %s: node count changed from %d to %d", afterCluster.Name,
+				beforeCluster.NodeCount, afterCluster.NodeCount))
+		}
+
+		for _, workload := range sortedKeys(beforeCluster.Workloads) {
+			if !beforeCluster.Workloads[workload] {
+				continue
+			}
+
+			rolledOut, ok := afterCluster.Workloads[workload]
+			switch {
+			case !ok:
+				diff = append(diff, fmt.Sprintf("cluster %s: deployment %s is missing", afterCluster.Name, workload))
+			case !rolledOut:
+				diff = append(diff, fmt.Sprintf("cluster %s: deployment %s is not rolled out", afterCluster.Name, workload))
+			}
+		}
+	}
+
+	return diff
+}
+
+// hasImageTag is a helper function that will check if the given image uses the given tag.
+func hasImageTag(image, tag string) bool {
+	return strings.HasSuffix(image, imageTagSplitter+tag)
+}
+
+// isRolledOut is a helper function that will check if every replica of the deployment is updated and available.
+func isRolledOut(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas
+}
+
+// sortedKeys is a helper function that will return the keys of the given map in order, so that the inventory diff is
+// stable between runs.
+func sortedKeys[V any](items map[string]V) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
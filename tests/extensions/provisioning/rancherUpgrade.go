@@ -0,0 +1,151 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/rancher/shepherd/clients/rancher"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	"github.com/rancher/shepherd/extensions/defaults"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/defaults/stevetypes"
+	"github.com/rancher/tfp-automation/framework/set/resources/backup"
+	"github.com/rancher/tfp-automation/framework/set/resources/upgrade"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	kwait "k8s.io/apimachinery/pkg/util/wait"
+)
+
+// RancherUpgrade is a function that will upgrade the Rancher server and validate the downstream clusters before and
+// after the upgrade. The inventory of the downstream clusters and the Terraform plan of their module are captured
+// before the Helm upgrade. Once the rancher deployment is rolled out and the cluster agents are redeployed with the
+// upgraded version, the inventory is captured again and diffed, and the Terraform plan must still be clean. The Rancher
+// environment to upgrade is selected by the Standalone.Upgrade* flags of the terraformConfig.
+func RancherUpgrade(t *testing.T, client *rancher.Client, terraformConfig *config.TerraformConfig, terratestConfig *config.TerratestConfig,
+	clusterIDs []string, terraformOptions, upgradeTerraformOptions *terraform.Options, upgradeKeyPath, serverNode, proxyNode,
+	bastionNode, registryNode string) {
+	upgradedTag := terraformConfig.Standalone.UpgradedRancherTagVersion
+	require.NotEmpty(t, upgradedTag, "standalone.upgradedRancherTagVersion must be set")
+
+	logrus.Infof("Capturing the inventory of %d downstream cluster(s) before the upgrade...", len(clusterIDs))
+	before := getRancherInventory(t, client, clusterIDs, terraformOptions)
+	for _, attribute := range before.Drift {
+		logrus.Warnf("Drift detected before the upgrade: %s", attribute)
+	}
+
+	require.Empty(t, before.Drift, "Terraform plan of the downstream clusters is not empty before the upgrade")
+
+	err := upgrade.CreateMainTF(t, upgradeTerraformOptions, upgradeKeyPath, terraformConfig, terratestConfig, serverNode, proxyNode, bastionNode, registryNode)
+	require.NoError(t, err)
+
+	verifyRancherRollout(t, client, clusterIDs, terraformOptions, before, upgradedTag)
+}
+
+// RancherRollback is a function that will restore the Rancher server from the given rancher-backup backup and reinstall
+// the Rancher version it was originally installed with. The downstream clusters are validated the same way as after an
+// upgrade, with the cluster agents expected to be redeployed on the original version.
+func RancherRollback(t *testing.T, client *rancher.Client, terraformConfig *config.TerraformConfig, clusterIDs []string,
+	terraformOptions, rollbackTerraformOptions *terraform.Options, rollbackKeyPath, serverNode, backupName string) {
+	rancherTag := terraformConfig.Standalone.RancherTagVersion
+	require.NotEmpty(t, rancherTag, "standalone.rancherTagVersion must be set")
+
+	logrus.Infof("Capturing the inventory of %d downstream cluster(s) before the rollback...", len(clusterIDs))
+	before := getRancherInventory(t, client, clusterIDs, terraformOptions)
+	for _, attribute := range before.Drift {
+		logrus.Warnf("Drift detected before the rollback: %s", attribute)
+	}
+
+	require.Empty(t, before.Drift, "Terraform plan of the downstream clusters is not empty before the rollback")
+
+	err := backup.RestoreBackup(t, rollbackTerraformOptions, rollbackKeyPath, terraformConfig, serverNode, backupName)
+	require.NoError(t, err)
+
+	verifyRancherRollout(t, client, clusterIDs, terraformOptions, before, rancherTag)
+}
+
+// verifyRancherRollout is a helper function that will wait for the rancher deployment and the cluster agents to be
+// rolled out on the given tag, then diff the inventory of the downstream clusters against the given inventory and
+// verify that their Terraform plan is clean.
+func verifyRancherRollout(t *testing.T, client *rancher.Client, clusterIDs []string, terraformOptions *terraform.Options,
+	before *RancherInventory, tag string) {
+	logrus.Infof("Waiting for the rancher deployment to be rolled out on %s...", tag)
+	err := waitForDeploymentImageTag(client.Steve, rancherID, tag, defaults.ThirtyMinuteTimeout)
+	require.NoError(t, err)
+
+	for _, clusterID := range clusterIDs {
+		logrus.Infof("Waiting for the cluster agent of cluster %s to be redeployed on %s...", before.Clusters[clusterID].Name, tag)
+
+		steveclient, err := client.Steve.ProxyDownstream(clusterID)
+		require.NoError(t, err)
+
+		err = waitForDeploymentImageTag(steveclient, clusterAgentID, tag, defaults.FifteenMinuteTimeout)
+		require.NoError(t, err)
+	}
+
+	VerifyClustersState(t, client, clusterIDs)
+
+	logrus.Infof("Capturing the inventory of the downstream clusters after the rollout...")
+	after := getRancherInventory(t, client, clusterIDs, terraformOptions)
+
+	diff := DiffRancherInventory(before, after)
+	for _, change := range diff {
+		logrus.Warnf("Inventory changed during the rollout: %s", change)
+	}
+
+	require.Empty(t, diff, "downstream clusters changed during the rollout of Rancher %s", tag)
+
+	for _, attribute := range after.Drift {
+		logrus.Warnf("Drift detected after the rollout: %s", attribute)
+	}
+
+	require.Empty(t, after.Drift, "Terraform plan of the downstream clusters is not empty after the rollout of Rancher %s", tag)
+}
+
+// getRancherInventory is a helper function that will capture the inventory of every downstream cluster and the drift of
+// their Terraform module.
+func getRancherInventory(t *testing.T, client *rancher.Client, clusterIDs []string, terraformOptions *terraform.Options) *RancherInventory {
+	inventory := &RancherInventory{
+		Clusters: map[string]ClusterInventory{},
+	}
+
+	for _, clusterID := range clusterIDs {
+		clusterInventory, err := GetClusterInventory(client, clusterID)
+		require.NoError(t, err)
+
+		logrus.Infof("Cluster %s is %s on %s with %d node(s), %d deployment(s) and cluster agent %s", clusterInventory.Name,
+			clusterInventory.State, clusterInventory.KubernetesVersion, clusterInventory.NodeCount, len(clusterInventory.Workloads),
+			clusterInventory.AgentImage)
+
+		inventory.Clusters[clusterID] = *clusterInventory
+	}
+
+	inventory.Drift = GetDrift(t, terraformOptions)
+
+	return inventory
+}
+
+// waitForDeploymentImageTag is a helper function that will wait for the given deployment to run the given image tag and
+// to be fully rolled out. Errors from the API are ignored, since Rancher is briefly unavailable while it is upgraded.
+func waitForDeploymentImageTag(steveclient *steveV1.Client, deploymentID, tag string, timeout time.Duration) error {
+	return kwait.PollUntilContextTimeout(context.TODO(), defaults.TenSecondTimeout, timeout, true, func(ctx context.Context) (bool, error) {
+		deploymentObject, err := steveclient.SteveType(stevetypes.Deployment).ByID(deploymentID)
+		if err != nil {
+			return false, nil
+		}
+
+		deployment := &appsv1.Deployment{}
+		err = steveV1.ConvertToK8sType(deploymentObject.JSONResp, deployment)
+		if err != nil {
+			return false, err
+		}
+
+		if len(deployment.Spec.Template.Spec.Containers) == 0 || !hasImageTag(deployment.Spec.Template.Spec.Containers[0].Image, tag) {
+			return false, nil
+		}
+
+		return isRolledOut(deployment), nil
+	})
+}
@@ -21,4 +21,5 @@ func Scale(t *testing.T, client *rancher.Client, rancherConfig *rancher.Config,
 	require.NoError(t, err)
 
 	terraform.Apply(t, terraformOptions)
+	CheckDrift(t, terraformOptions, configMap)
 }
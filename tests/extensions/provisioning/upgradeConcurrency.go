@@ -0,0 +1,184 @@
+package provisioning
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/rancher/shepherd/clients/rancher"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	controlPlaneRoleLabel = "node-role.kubernetes.io/control-plane"
+	etcdRoleLabel         = "node-role.kubernetes.io/etcd"
+	cordonInterval        = 2 * time.Second
+)
+
+// CordonWatcher polls the nodes of a downstream cluster during an upgrade and records the highest number of control
+// plane and worker nodes that were cordoned at the same time.
+type CordonWatcher struct {
+	ClusterID string
+
+	steveclient *steveV1.Client
+
+	mu                      sync.Mutex
+	controlPlaneNodes       int
+	workerNodes             int
+	maxCordonedControlPlane int
+	maxCordonedWorkers      int
+	cancel                  context.CancelFunc
+	done                    chan struct{}
+}
+
+// WatchCordonedNodes is a function that will start watching the cordoned nodes of the given downstream cluster, until
+// the watcher is stopped or the given context is cancelled.
+func WatchCordonedNodes(ctx context.Context, client *rancher.Client, clusterID string) (*CordonWatcher, error) {
+	steveclient, err := client.Steve.ProxyDownstream(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	watcher := &CordonWatcher{
+		ClusterID:   clusterID,
+		steveclient: steveclient,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+	}
+
+	go func() {
+		defer close(watcher.done)
+
+		ticker := time.NewTicker(cordonInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				watcher.record()
+			}
+		}
+	}()
+
+	return watcher, nil
+}
+
+// Stop is a function that will stop watching the cordoned nodes and wait for the watcher to return. It is safe to call
+// more than once.
+func (w *CordonWatcher) Stop() {
+	w.cancel()
+	<-w.done
+}
+
+// VerifyUpgradeConcurrency validates that no more control plane and worker nodes were cordoned at the same time than
+// the configured upgrade strategy allows. If draining is enabled for a role, at least one node of that role must have
+// been cordoned, since nodes are only cordoned when they are drained.
+func VerifyUpgradeConcurrency(t *testing.T, watcher *CordonWatcher, upgradeStrategy *rkev1.ClusterUpgradeStrategy) {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+
+	allowedControlPlane := allowedConcurrency(upgradeStrategy.ControlPlaneConcurrency, watcher.controlPlaneNodes)
+	allowedWorkers := allowedConcurrency(upgradeStrategy.WorkerConcurrency, watcher.workerNodes)
+
+	logrus.Infof("Cluster %s cordoned at most %d of %d control plane nodes (allowed %d) and %d of %d worker nodes (allowed %d) at once",
+		watcher.ClusterID, watcher.maxCordonedControlPlane, watcher.controlPlaneNodes, allowedControlPlane,
+		watcher.maxCordonedWorkers, watcher.workerNodes, allowedWorkers)
+
+	require.LessOrEqual(t, watcher.maxCordonedControlPlane, allowedControlPlane)
+	require.LessOrEqual(t, watcher.maxCordonedWorkers, allowedWorkers)
+
+	if upgradeStrategy.ControlPlaneDrainOptions.Enabled {
+		require.NotZero(t, watcher.maxCordonedControlPlane, "no control plane node was cordoned during the upgrade")
+	}
+
+	if upgradeStrategy.WorkerDrainOptions.Enabled && watcher.workerNodes > 0 {
+		require.NotZero(t, watcher.maxCordonedWorkers, "no worker node was cordoned during the upgrade")
+	}
+}
+
+// record lists the nodes of the cluster and updates the highest number of cordoned nodes. Errors are ignored, since the
+// Kubernetes API can be briefly unavailable while the control plane is upgraded.
+func (w *CordonWatcher) record() {
+	nodes, err := w.steveclient.SteveType(nodeSteveType).List(nil)
+	if err != nil {
+		return
+	}
+
+	var controlPlaneNodes, workerNodes, cordonedControlPlane, cordonedWorkers int
+
+	for _, nodeObject := range nodes.Data {
+		node := &corev1.Node{}
+		err = steveV1.ConvertToK8sType(nodeObject.JSONResp, node)
+		if err != nil {
+			return
+		}
+
+		// Etcd nodes are upgraded with the control plane concurrency.
+		isControlPlane := node.Labels[controlPlaneRoleLabel] == "true" || node.Labels[etcdRoleLabel] == "true"
+
+		if isControlPlane {
+			controlPlaneNodes++
+		} else {
+			workerNodes++
+		}
+
+		if !node.Spec.Unschedulable {
+			continue
+		}
+
+		if isControlPlane {
+			cordonedControlPlane++
+		} else {
+			cordonedWorkers++
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.controlPlaneNodes = max(w.controlPlaneNodes, controlPlaneNodes)
+	w.workerNodes = max(w.workerNodes, workerNodes)
+	w.maxCordonedControlPlane = max(w.maxCordonedControlPlane, cordonedControlPlane)
+	w.maxCordonedWorkers = max(w.maxCordonedWorkers, cordonedWorkers)
+}
+
+// allowedConcurrency returns the number of nodes that can be upgraded at once for the given concurrency, which is either
+// a number or a percentage of the nodes. As in Rancher, a percentage is rounded up, an empty concurrency upgrades one node
+// at a time and a concurrency of 0 upgrades all nodes at once.
+func allowedConcurrency(concurrency string, nodeCount int) int {
+	if concurrency == "" {
+		return 1
+	}
+
+	if strings.HasSuffix(concurrency, "%") {
+		percentage, err := strconv.ParseFloat(strings.TrimSuffix(concurrency, "%"), 64)
+		if err != nil {
+			return 1
+		}
+
+		return int(math.Ceil(percentage * float64(nodeCount) / 100))
+	}
+
+	count, err := strconv.Atoi(concurrency)
+	if err != nil {
+		return 1
+	}
+
+	if count == 0 {
+		return nodeCount
+	}
+
+	return count
+}
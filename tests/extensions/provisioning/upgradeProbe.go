@@ -0,0 +1,216 @@
+package provisioning
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rancher/shepherd/clients/rancher"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	"github.com/rancher/shepherd/extensions/workloads"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+	"github.com/rancher/tests/actions/services"
+	deploy "github.com/rancher/tests/actions/workloads/deployment"
+	"github.com/rancher/tfp-automation/defaults/stevetypes"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	probeName           = "upgrade-probe"
+	probeImage          = "nginx"
+	probeNamespace      = "default"
+	probePort           = 80
+	probeReplicas       = int32(2)
+	probeInterval       = 2 * time.Second
+	probeRequestTimeout = 5 * time.Second
+)
+
+// UpgradeProbe is a long-lived workload in a downstream cluster that is polled through the Rancher proxy while the
+// cluster is upgraded, recording the time the workload is unreachable during each upgrade hop. The downtime is the
+// elapsed time between checks that failed, so that slow requests are counted for as long as they took.
+type UpgradeProbe struct {
+	ClusterID string
+
+	url         string
+	token       string
+	httpClient  *http.Client
+	steveclient *steveV1.Client
+	deployment  *steveV1.SteveAPIObject
+	service     *steveV1.SteveAPIObject
+
+	mu       sync.Mutex
+	hop      string
+	hops     []string
+	downtime map[string]time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewUpgradeProbe is a function that will deploy the probe workload and service in the given downstream cluster and wait
+// for them to be ready.
+func NewUpgradeProbe(client *rancher.Client, clusterID string) (*UpgradeProbe, error) {
+	steveclient, err := client.Steve.ProxyDownstream(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := namegen.AppendRandomString(probeName)
+
+	container := workloads.NewContainer(name, probeImage, corev1.PullAlways, []corev1.VolumeMount{}, []corev1.EnvFromSource{}, nil, nil, nil)
+	podTemplate := workloads.NewPodTemplate([]corev1.Container{container}, []corev1.Volume{}, []corev1.LocalObjectReference{}, nil, nil)
+
+	deploymentTemplate := workloads.NewDeploymentTemplate(name, probeNamespace, podTemplate, true, nil)
+	replicas := probeReplicas
+	deploymentTemplate.Spec.Replicas = &replicas
+
+	deployment, err := steveclient.SteveType(stevetypes.Deployment).Create(deploymentTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	err = deploy.VerifyDeployment(steveclient, deployment)
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := services.CreateService(steveclient, corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: probeNamespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeClusterIP,
+			Ports:    []corev1.ServicePort{{Name: "http", Port: probePort}},
+			Selector: deploymentTemplate.Spec.Template.Labels,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = services.VerifyService(steveclient, service)
+	if err != nil {
+		return nil, err
+	}
+
+	insecure := client.RancherConfig.Insecure != nil && *client.RancherConfig.Insecure
+
+	return &UpgradeProbe{
+		ClusterID: clusterID,
+		url: "https://" + client.RancherConfig.Host + "/k8s/clusters/" + clusterID + "/api/v1/namespaces/" + probeNamespace +
+			"/services/http:" + name + ":http/proxy/",
+		token: client.RancherConfig.AdminToken,
+		httpClient: &http.Client{
+			Timeout:   probeRequestTimeout,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure}},
+		},
+		steveclient: steveclient,
+		deployment:  deployment,
+		service:     service,
+		downtime:    map[string]time.Duration{},
+	}, nil
+}
+
+// Start is a function that will start polling the probe workload in the background.
+func (p *UpgradeProbe) Start() {
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(probeInterval)
+		defer ticker.Stop()
+
+		lastCheck := time.Now()
+
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				reachable := p.reachable()
+				now := time.Now()
+
+				if !reachable {
+					p.mu.Lock()
+					p.downtime[p.hop] += now.Sub(lastCheck)
+					p.mu.Unlock()
+				}
+
+				lastCheck = now
+			}
+		}
+	}()
+}
+
+// SetHop is a function that will attribute any downtime recorded from now on to the given upgrade hop.
+func (p *UpgradeProbe) SetHop(hop string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.hop = hop
+	p.hops = append(p.hops, hop)
+}
+
+// Downtime is a function that will return the downtime recorded for the given upgrade hop.
+func (p *UpgradeProbe) Downtime(hop string) time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.downtime[hop]
+}
+
+// VerifyDowntime is a function that will return an error if the downtime recorded for any upgrade hop exceeds the given
+// maximum.
+func (p *UpgradeProbe) VerifyDowntime(maxDowntime time.Duration) error {
+	for _, hop := range p.hops {
+		downtime := p.Downtime(hop)
+		if downtime > maxDowntime {
+			return fmt.Errorf("cluster %s was unreachable for %s during the upgrade to %s, exceeding the maximum of %s",
+				p.ClusterID, downtime, hop, maxDowntime)
+		}
+	}
+
+	return nil
+}
+
+// Stop is a function that will stop polling the probe workload, log the downtime recorded for each upgrade hop and
+// delete the probe workload and service.
+func (p *UpgradeProbe) Stop() error {
+	close(p.stop)
+	<-p.done
+
+	for _, hop := range p.hops {
+		logrus.Infof("Cluster %s was unreachable for %s during the upgrade to %s", p.ClusterID, p.Downtime(hop), hop)
+	}
+
+	err := p.steveclient.SteveType(stevetypes.Deployment).Delete(p.deployment)
+	if err != nil {
+		return err
+	}
+
+	return p.steveclient.SteveType(stevetypes.Service).Delete(p.service)
+}
+
+// reachable is a function that will check if the probe workload responds through the Rancher proxy.
+func (p *UpgradeProbe) reachable() bool {
+	req, err := http.NewRequest(http.MethodGet, p.url, nil)
+	if err != nil {
+		return false
+	}
+
+	req.Header.Set("Authorization", "Bearer "+p.token)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode == http.StatusOK
+}
@@ -0,0 +1,181 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	provisioningv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/rancher/shepherd/clients/rancher"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	"github.com/rancher/shepherd/extensions/defaults"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/defaults/stevetypes"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kwait "k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	fleetAgentID         = "cattle-fleet-system/fleet-agent"
+	statefulSetSteveType = "apps.statefulset"
+)
+
+// VerifyAgentCustomization validates that the cattle-cluster-agent and fleet-agent workloads of the given clusters run
+// with the tolerations, affinity, resource requirements and environment variables set in the agentCustomization of the
+// terraform config. It is a no-op if no agent customization is configured. Newer Fleet versions run the fleet-agent as
+// a statefulset, so both workload types are checked.
+func VerifyAgentCustomization(t *testing.T, client *rancher.Client, clusterIDs []string, terraformConfig *config.TerraformConfig) {
+	if terraformConfig.AgentCustomization == nil {
+		return
+	}
+
+	agentCustomization := terraformConfig.AgentCustomization
+
+	for _, clusterID := range clusterIDs {
+		steveclient, err := client.Steve.ProxyDownstream(clusterID)
+		require.NoError(t, err)
+
+		logrus.Infof("Verifying the cluster agent customization of cluster %s...", clusterID)
+		err = waitForAgentCustomization(steveclient, clusterAgentID, agentCustomization.ClusterAgent, agentCustomization.AgentEnvVars)
+		require.NoError(t, err)
+
+		if agentCustomization.FleetAgent != nil {
+			logrus.Infof("Verifying the fleet agent customization of cluster %s...", clusterID)
+			err = waitForAgentCustomization(steveclient, fleetAgentID, agentCustomization.FleetAgent, nil)
+			require.NoError(t, err)
+		}
+	}
+}
+
+// waitForAgentCustomization is a helper function that will wait for the pod template of the given agent to match the
+// customization, returning the last mismatch if it does not match before the timeout.
+func waitForAgentCustomization(steveclient *steveV1.Client, agentID string, customization *provisioningv1.AgentDeploymentCustomization,
+	envVars []rkev1.EnvVar) error {
+	var mismatch error
+
+	err := kwait.PollUntilContextTimeout(context.TODO(), defaults.FiveSecondTimeout, defaults.TenMinuteTimeout, true, func(ctx context.Context) (bool, error) {
+		podTemplate, err := getAgentPodTemplate(steveclient, agentID)
+		if err != nil {
+			mismatch = err
+			return false, nil
+		}
+
+		mismatch = matchAgentCustomization(podTemplate, customization, envVars)
+
+		return mismatch == nil, nil
+	})
+	if err != nil && mismatch != nil {
+		return fmt.Errorf("%s does not match the agent customization: %w", agentID, mismatch)
+	}
+
+	return err
+}
+
+// getAgentPodTemplate is a helper function that will return the pod template of the given agent, which is either a
+// deployment or a statefulset.
+func getAgentPodTemplate(steveclient *steveV1.Client, agentID string) (*corev1.PodTemplateSpec, error) {
+	deploymentObject, err := steveclient.SteveType(stevetypes.Deployment).ByID(agentID)
+	if err == nil {
+		deployment := &appsv1.Deployment{}
+		err = steveV1.ConvertToK8sType(deploymentObject.JSONResp, deployment)
+		if err != nil {
+			return nil, err
+		}
+
+		return &deployment.Spec.Template, nil
+	}
+
+	statefulSetObject, err := steveclient.SteveType(statefulSetSteveType).ByID(agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	statefulSet := &appsv1.StatefulSet{}
+	err = steveV1.ConvertToK8sType(statefulSetObject.JSONResp, statefulSet)
+	if err != nil {
+		return nil, err
+	}
+
+	return &statefulSet.Spec.Template, nil
+}
+
+// matchAgentCustomization is a helper function that will check that the pod template contains every appended
+// toleration and environment variable, and uses the overridden affinity and resource requirements.
+func matchAgentCustomization(podTemplate *corev1.PodTemplateSpec, customization *provisioningv1.AgentDeploymentCustomization,
+	envVars []rkev1.EnvVar) error {
+	if len(podTemplate.Spec.Containers) == 0 {
+		return fmt.Errorf("no containers found")
+	}
+
+	container := podTemplate.Spec.Containers[0]
+
+	for _, envVar := range envVars {
+		if !hasEnvVar(container.Env, envVar.Name, envVar.Value) {
+			return fmt.Errorf("environment variable %s=%s not found", envVar.Name, envVar.Value)
+		}
+	}
+
+	if customization == nil {
+		return nil
+	}
+
+	for _, toleration := range customization.AppendTolerations {
+		if !hasToleration(podTemplate.Spec.Tolerations, toleration) {
+			return fmt.Errorf("toleration %s not found", toleration.Key)
+		}
+	}
+
+	if customization.OverrideAffinity != nil && !reflect.DeepEqual(customization.OverrideAffinity, podTemplate.Spec.Affinity) {
+		return fmt.Errorf("affinity %v does not match %v", podTemplate.Spec.Affinity, customization.OverrideAffinity)
+	}
+
+	if customization.OverrideResourceRequirements != nil {
+		for resourceName, expected := range customization.OverrideResourceRequirements.Limits {
+			actual, ok := container.Resources.Limits[resourceName]
+			if !ok || actual.Cmp(expected) != 0 {
+				return fmt.Errorf("%s limit is %s, expected %s", resourceName, actual.String(), expected.String())
+			}
+		}
+
+		for resourceName, expected := range customization.OverrideResourceRequirements.Requests {
+			actual, ok := container.Resources.Requests[resourceName]
+			if !ok || actual.Cmp(expected) != 0 {
+				return fmt.Errorf("%s request is %s, expected %s", resourceName, actual.String(), expected.String())
+			}
+		}
+	}
+
+	return nil
+}
+
+// hasEnvVar is a helper function that will check if the given environment variable is set on the container.
+func hasEnvVar(env []corev1.EnvVar, name, value string) bool {
+	for _, envVar := range env {
+		if envVar.Name == name && envVar.Value == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasToleration is a helper function that will check if the given toleration is in the list of tolerations. Rancher
+// may default the operator, so an empty operator in the expected toleration matches any operator.
+func hasToleration(tolerations []corev1.Toleration, expected corev1.Toleration) bool {
+	for _, toleration := range tolerations {
+		if toleration.Key != expected.Key || toleration.Value != expected.Value || toleration.Effect != expected.Effect {
+			continue
+		}
+
+		if expected.Operator == "" || toleration.Operator == expected.Operator {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,110 @@
+package provisioning
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	provv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/rancher/shepherd/clients/rancher"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	"github.com/rancher/shepherd/extensions/defaults/namespaces"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/defaults/clustertypes"
+	"github.com/rancher/tfp-automation/defaults/stevetypes"
+	"github.com/rancher/tfp-automation/framework/set/provisioning/nodedriver/rke2k3s"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// VerifyRKEConfig validates that the rkeConfig section of the terraform config was applied to the provisioning
+// cluster of the given RKE2/K3s clusters. It is a no-op if no rkeConfig section is configured or the module is an RKE1
+// module, which has no rkeConfig. Configs are compared by their JSON representation, so that values loaded from the
+// config file match the values stored by Rancher.
+func VerifyRKEConfig(t *testing.T, client *rancher.Client, clusterIDs []string, terraformConfig *config.TerraformConfig) {
+	if terraformConfig.RKEConfig == nil || strings.Contains(terraformConfig.Module, clustertypes.RKE1) {
+		return
+	}
+
+	rkeConfig := terraformConfig.RKEConfig
+
+	for _, clusterID := range clusterIDs {
+		managementCluster, err := client.Management.Cluster.ByID(clusterID)
+		require.NoError(t, err)
+
+		logrus.Infof("Verifying the rkeConfig of cluster %s...", managementCluster.Name)
+
+		clusterObject, err := client.Steve.SteveType(stevetypes.Provisioning).ByID(namespaces.FleetDefault + "/" + managementCluster.Name)
+		require.NoError(t, err)
+
+		cluster := &provv1.Cluster{}
+		err = steveV1.ConvertToK8sType(clusterObject.JSONResp, cluster)
+		require.NoError(t, err)
+		require.NotNil(t, cluster.Spec.RKEConfig, "cluster %s has no rkeConfig", managementCluster.Name)
+
+		for key, expected := range rkeConfig.MachineGlobalConfig {
+			actual, ok := cluster.Spec.RKEConfig.MachineGlobalConfig.Data[key]
+			require.True(t, ok, "machineGlobalConfig key %s not found", key)
+			require.True(t, jsonEqual(t, expected, actual), "machineGlobalConfig key %s is %v, expected %v", key, actual, expected)
+		}
+
+		if rkeConfig.AdditionalManifest != "" {
+			require.Equal(t, strings.TrimSpace(rkeConfig.AdditionalManifest), strings.TrimSpace(cluster.Spec.RKEConfig.AdditionalManifest))
+		}
+
+		if rkeConfig.LocalAuthEndpoint != nil {
+			require.Equal(t, rkeConfig.LocalAuthEndpoint.Enabled, cluster.Spec.LocalClusterAuthEndpoint.Enabled)
+			require.Equal(t, rkeConfig.LocalAuthEndpoint.FQDN, cluster.Spec.LocalClusterAuthEndpoint.FQDN)
+		}
+
+		expectedSelectorConfigs := append([]rkev1.RKESystemConfig{}, rkeConfig.MachineSelectorConfig...)
+		if len(rkeConfig.ETCDArgs) > 0 {
+			expectedSelectorConfigs = append(expectedSelectorConfigs, rke2k3s.ETCDArgsSelectorConfig(rkeConfig.ETCDArgs))
+		}
+
+		for _, expected := range expectedSelectorConfigs {
+			require.NoError(t, containsJSON(t, cluster.Spec.RKEConfig.MachineSelectorConfig, expected), "machineSelectorConfig not found")
+		}
+
+		for _, expected := range rkeConfig.MachineSelectorFiles {
+			require.NoError(t, containsJSON(t, cluster.Spec.RKEConfig.MachineSelectorFiles, expected), "machineSelectorFiles not found")
+		}
+	}
+}
+
+// containsJSON is a helper function that will check if the given list contains an item with the same JSON
+// representation as the expected item.
+func containsJSON[T any](t *testing.T, items []T, expected T) error {
+	for _, item := range items {
+		if jsonEqual(t, expected, item) {
+			return nil
+		}
+	}
+
+	expectedJSON, err := json.Marshal(expected)
+	require.NoError(t, err)
+
+	return fmt.Errorf("%s not found", string(expectedJSON))
+}
+
+// jsonEqual is a helper function that will check if the given values have the same JSON representation.
+func jsonEqual(t *testing.T, expected, actual any) bool {
+	var expectedValue, actualValue any
+
+	expectedJSON, err := json.Marshal(expected)
+	require.NoError(t, err)
+
+	err = json.Unmarshal(expectedJSON, &expectedValue)
+	require.NoError(t, err)
+
+	actualJSON, err := json.Marshal(actual)
+	require.NoError(t, err)
+
+	err = json.Unmarshal(actualJSON, &actualValue)
+	require.NoError(t, err)
+
+	return reflect.DeepEqual(expectedValue, actualValue)
+}
@@ -0,0 +1,150 @@
+package rbac
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/rancher/norman/types"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/extensions/defaults"
+	"github.com/rancher/shepherd/pkg/config/operations"
+	"github.com/rancher/tfp-automation/config"
+	framework "github.com/rancher/tfp-automation/framework/set"
+	"github.com/rancher/tfp-automation/framework/set/resources/rancher2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	kwait "k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	adminUser          = "admin"
+	localPrincipal     = "local://"
+	requiredAccess     = "required"
+	restrictedAccess   = "restricted"
+	unrestrictedAccess = "unrestricted"
+)
+
+// AuthLifecycle is a function that will enable the configured auth provider, update its access mode between
+// unrestricted, restricted and required while verifying that only the allowed principals can log in, and then disable
+// it through terraform destroy. A second provider user, who is not an allowed principal, must be refused in the
+// restricted modes. After the provider is disabled, the local admin must still have access and the tokens issued by
+// the provider must be revoked.
+func AuthLifecycle(t *testing.T, client *rancher.Client, rancherConfig *rancher.Config, terraformOptions *terraform.Options,
+	testUser, testPassword string, configMap []map[string]any) {
+	_, terraformConfig, _ := config.LoadTFPConfigs(configMap[0])
+
+	newFile, rootBody, file := rancher2.InitializeMainTF()
+	defer file.Close()
+
+	AuthConfig(t, terraformConfig, terraformOptions, testUser, testPassword, configMap, newFile, rootBody, file)
+
+	loginEndpoint, provider, providerUsername, providerPassword, _ := getLoginDetails(terraformConfig)
+	deniedUsername, deniedPassword := getDeniedCredentials(terraformConfig)
+	if loginEndpoint == "" {
+		logrus.Infof("Auth provider %s does not support a username and password login, only the apply of each access mode is verified", terraformConfig.AuthProvider)
+	} else {
+		require.NotEmpty(t, deniedUsername, "No denied user specified for auth provider %s", terraformConfig.AuthProvider)
+	}
+
+	adminUsers, err := client.Management.User.List(&types.ListOpts{Filters: map[string]interface{}{"me": "true"}})
+	require.NoError(t, err)
+	require.NotEmpty(t, adminUsers.Data)
+
+	allowedPrincipalIDs := []string{localPrincipal + adminUsers.Data[0].ID}
+
+	var providerClient *rancher.Client
+	if loginEndpoint != "" {
+		providerClient, _, err = loginAsProviderUser(client, rancherConfig, loginEndpoint, providerUsername, providerPassword)
+		require.NoError(t, err)
+
+		userPrincipal, _, err := getUserPrincipal(providerClient)
+		require.NoError(t, err)
+		require.NotNil(t, userPrincipal, "No principal found for user %s", providerUsername)
+
+		allowedPrincipalIDs = append(allowedPrincipalIDs, userPrincipal.ID)
+	}
+
+	defer restoreConfigValue(configMap[0], []string{"terraform", "accessMode"})()
+	defer restoreConfigValue(configMap[0], []string{"terraform", "allowedPrincipalIds"})()
+
+	for _, accessMode := range []string{unrestrictedAccess, restrictedAccess, requiredAccess} {
+		logrus.Infof("Updating auth provider %s access mode to %s...", terraformConfig.AuthProvider, accessMode)
+
+		_, err = operations.ReplaceValue([]string{"terraform", "accessMode"}, accessMode, configMap[0])
+		require.NoError(t, err)
+
+		principalIDs := []string{}
+		if accessMode != unrestrictedAccess {
+			principalIDs = allowedPrincipalIDs
+		}
+
+		_, err = operations.ReplaceValue([]string{"terraform", "allowedPrincipalIds"}, principalIDs, configMap[0])
+		require.NoError(t, err)
+
+		newFile, rootBody, file := rancher2.InitializeMainTF()
+
+		err = framework.AuthConfig(testUser, testPassword, configMap, newFile, rootBody, file)
+		file.Close()
+		require.NoError(t, err)
+
+		terraform.Apply(t, terraformOptions)
+
+		if loginEndpoint != "" {
+			_, token, err := loginAsProviderUser(client, rancherConfig, loginEndpoint, providerUsername, providerPassword)
+			require.NoError(t, err, "Allowed user %s could not log in with access mode %s", providerUsername, accessMode)
+
+			deleteToken(client, token)
+
+			_, token, err = loginAsProviderUser(client, rancherConfig, loginEndpoint, deniedUsername, deniedPassword)
+			if accessMode == unrestrictedAccess {
+				require.NoError(t, err, "Denied user %s could not log in with access mode %s", deniedUsername, accessMode)
+
+				deleteToken(client, token)
+			} else {
+				require.ErrorIs(t, err, errLoginForbidden, "Denied user %s, who is not an allowed principal, was not refused with access mode %s",
+					deniedUsername, accessMode)
+			}
+		}
+	}
+
+	logrus.Infof("Disabling auth provider %s...", terraformConfig.AuthProvider)
+	terraform.Destroy(t, terraformOptions)
+
+	if rancherConfig.AdminPassword != "" {
+		_, err = client.AsUser(&management.User{Username: adminUser, Password: rancherConfig.AdminPassword})
+		require.NoError(t, err, "Local admin could not log in after disabling auth provider %s", terraformConfig.AuthProvider)
+	}
+
+	_, err = client.Management.User.List(&types.ListOpts{})
+	require.NoError(t, err, "Admin token is no longer valid after disabling auth provider %s", terraformConfig.AuthProvider)
+
+	if providerClient != nil {
+		logrus.Infof("Waiting for the tokens of %s user %s to be revoked...", provider, providerUsername)
+		err = kwait.PollUntilContextTimeout(context.TODO(), defaults.FiveSecondTimeout, defaults.TwoMinuteTimeout, true, func(ctx context.Context) (bool, error) {
+			_, err := providerClient.Management.Principal.List(&types.ListOpts{})
+			return err != nil, nil
+		})
+		require.NoError(t, err, "Token of %s user %s was not revoked", provider, providerUsername)
+	}
+}
+
+// restoreConfigValue is a helper function that will capture the value of the given key of the config, and return a
+// function that restores it. The key is removed again if it was not set.
+func restoreConfigValue(cattleConfig map[string]any, keyPath []string) func() {
+	original, err := operations.GetValue(keyPath, cattleConfig)
+	isSet := err == nil
+
+	return func() {
+		if isSet {
+			operations.ReplaceValue(keyPath, original, cattleConfig)
+			return
+		}
+
+		parent, err := operations.GetValue(keyPath[:len(keyPath)-1], cattleConfig)
+		if parentMap, ok := parent.(map[string]any); err == nil && ok {
+			delete(parentMap, keyPath[len(keyPath)-1])
+		}
+	}
+}
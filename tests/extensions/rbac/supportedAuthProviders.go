@@ -13,10 +13,16 @@ func SupportedAuthProviders(terraformConfig *config.TerraformConfig, terraformOp
 	authProvider := terraformConfig.AuthProvider
 	supportedAuthProviders := []string{
 		authproviders.AD,
+		authproviders.ADFS,
 		authproviders.AzureAD,
+		authproviders.FreeIPA,
+		authproviders.GenericOIDC,
 		authproviders.GitHub,
+		authproviders.Keycloak,
+		authproviders.KeycloakOIDC,
 		authproviders.Okta,
 		authproviders.OpenLDAP,
+		authproviders.Ping,
 	}
 
 	return slices.Contains(supportedAuthProviders, authProvider)
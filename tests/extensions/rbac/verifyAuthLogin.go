@@ -0,0 +1,280 @@
+package rbac
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rancher/norman/types"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/extensions/defaults"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/defaults/authproviders"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	kwait "k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	activeDirectoryLogin = "/v3-public/activeDirectoryProviders/activedirectory"
+	freeIPALogin         = "/v3-public/freeIpaProviders/freeipa"
+	openLDAPLogin        = "/v3-public/openLdapProviders/openldap"
+
+	activeDirectoryProvider = "activedirectory"
+	freeIPAProvider         = "freeipa"
+	openLDAPProvider        = "openldap"
+
+	groupPrincipalType = "group"
+	localCluster       = "local"
+	login              = "login"
+	tokenSeparator     = ":"
+	userPrincipalType  = "user"
+)
+
+// errLoginForbidden is returned when the provider accepts the credentials, but Rancher refuses the login because the
+// user is not an allowed principal.
+var errLoginForbidden = errors.New("login forbidden")
+
+// VerifyAuthLogin is a function that will log in as the test user of the configured auth provider and verify the
+// resulting principal, that users and groups can be searched, and that a project role bound to the test group grants
+// access to the project. Only providers that accept a username and password login can be verified, the test is
+// skipped for every other provider.
+func VerifyAuthLogin(t *testing.T, client *rancher.Client, rancherConfig *rancher.Config, terraformConfig *config.TerraformConfig) {
+	loginEndpoint, provider, testUsername, testPassword, testGroup := getLoginDetails(terraformConfig)
+	if loginEndpoint == "" {
+		t.Skipf("Auth provider %s does not support a username and password login, skipping login verification", terraformConfig.AuthProvider)
+	}
+
+	logrus.Infof("Logging in as %s user %s...", provider, testUsername)
+	userClient, token, err := loginAsProviderUser(client, rancherConfig, loginEndpoint, testUsername, testPassword)
+	require.NoError(t, err)
+
+	defer deleteToken(client, token)
+
+	userPrincipal, principals, err := getUserPrincipal(userClient)
+	require.NoError(t, err)
+	require.NotNil(t, userPrincipal, "No principal found for user %s", testUsername)
+	require.Equal(t, provider, userPrincipal.Provider)
+	require.Equal(t, testUsername, userPrincipal.LoginName)
+
+	logrus.Infof("Logged in as principal %s", userPrincipal.ID)
+
+	searchedUser, err := searchPrincipal(userClient, principals, testUsername, userPrincipalType)
+	require.NoError(t, err)
+	require.Equal(t, userPrincipal.ID, searchedUser.ID)
+
+	if testGroup == "" {
+		logrus.Infof("No test group specified, skipping group-based access verification")
+		return
+	}
+
+	groupPrincipal, err := searchPrincipal(userClient, principals, testGroup, groupPrincipalType)
+	require.NoError(t, err)
+
+	logrus.Infof("Found group principal %s", groupPrincipal.ID)
+
+	verifyGroupAccess(t, client, rancherConfig, loginEndpoint, testUsername, testPassword, groupPrincipal.ID)
+}
+
+// getDeniedCredentials is a helper function that will return the credentials of the provider user that is not an
+// allowed principal of the configured auth provider.
+func getDeniedCredentials(terraformConfig *config.TerraformConfig) (string, string) {
+	switch terraformConfig.AuthProvider {
+	case authproviders.AD:
+		return terraformConfig.ADConfig.DeniedUsername, terraformConfig.ADConfig.DeniedPassword
+	case authproviders.FreeIPA:
+		return terraformConfig.FreeIPAConfig.DeniedUsername, terraformConfig.FreeIPAConfig.DeniedPassword
+	case authproviders.OpenLDAP:
+		return terraformConfig.OpenLDAPConfig.DeniedUsername, terraformConfig.OpenLDAPConfig.DeniedPassword
+	default:
+		return "", ""
+	}
+}
+
+// getLoginDetails is a helper function that will return the login endpoint, principal provider name and test
+// credentials of the configured auth provider. An empty login endpoint is returned for providers that do not accept a
+// username and password login.
+func getLoginDetails(terraformConfig *config.TerraformConfig) (string, string, string, string, string) {
+	switch terraformConfig.AuthProvider {
+	case authproviders.AD:
+		return activeDirectoryLogin, activeDirectoryProvider, terraformConfig.ADConfig.TestUsername, terraformConfig.ADConfig.TestPassword,
+			terraformConfig.ADConfig.TestGroup
+	case authproviders.FreeIPA:
+		return freeIPALogin, freeIPAProvider, terraformConfig.FreeIPAConfig.TestUsername, terraformConfig.FreeIPAConfig.TestPassword,
+			terraformConfig.FreeIPAConfig.TestGroup
+	case authproviders.OpenLDAP:
+		return openLDAPLogin, openLDAPProvider, terraformConfig.OpenLDAPConfig.TestUsername, terraformConfig.OpenLDAPConfig.TestPassword,
+			terraformConfig.OpenLDAPConfig.TestGroup
+	default:
+		return "", "", "", "", ""
+	}
+}
+
+// verifyGroupAccess is a helper function that will bind the project member role to the group principal in the Default
+// project of the local cluster, and verify that the test user can only access the project once the binding exists.
+func verifyGroupAccess(t *testing.T, client *rancher.Client, rancherConfig *rancher.Config, loginEndpoint, testUsername, testPassword,
+	groupPrincipalID string) {
+	projects, err := client.Management.Project.List(&types.ListOpts{
+		Filters: map[string]interface{}{"clusterId": localCluster, "name": defaultProject},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, projects.Data, "Project %s not found in cluster %s", defaultProject, localCluster)
+
+	projectID := projects.Data[0].ID
+
+	userClient, token, err := loginAsProviderUser(client, rancherConfig, loginEndpoint, testUsername, testPassword)
+	require.NoError(t, err)
+
+	_, err = userClient.Management.Project.ByID(projectID)
+	deleteToken(client, token)
+	require.Error(t, err, "User %s has access to project %s before the group binding exists", testUsername, projectID)
+
+	binding, err := client.Management.ProjectRoleTemplateBinding.Create(&management.ProjectRoleTemplateBinding{
+		GroupPrincipalID: groupPrincipalID,
+		ProjectID:        projectID,
+		RoleTemplateID:   string(config.ProjectMember),
+	})
+	require.NoError(t, err)
+
+	defer client.Management.ProjectRoleTemplateBinding.Delete(binding)
+
+	logrus.Infof("Waiting for group %s to grant access to project %s...", groupPrincipalID, projectID)
+	err = kwait.PollUntilContextTimeout(context.TODO(), defaults.FiveSecondTimeout, defaults.TwoMinuteTimeout, true, func(ctx context.Context) (bool, error) {
+		userClient, token, err := loginAsProviderUser(client, rancherConfig, loginEndpoint, testUsername, testPassword)
+		if err != nil {
+			return false, nil
+		}
+
+		defer deleteToken(client, token)
+
+		_, err = userClient.Management.Project.ByID(projectID)
+
+		return err == nil, nil
+	})
+	require.NoError(t, err)
+}
+
+// getUserPrincipal is a helper function that will return the user principal of the logged in user, along with the
+// principal collection that is needed to search the auth provider.
+func getUserPrincipal(client *rancher.Client) (*management.Principal, *management.PrincipalCollection, error) {
+	principals, err := client.Management.Principal.List(&types.ListOpts{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, principal := range principals.Data {
+		if principal.Me && principal.PrincipalType == userPrincipalType {
+			return &principals.Data[i], principals, nil
+		}
+	}
+
+	return nil, principals, nil
+}
+
+// searchPrincipal is a helper function that will search the auth provider for a principal of the given type and name.
+func searchPrincipal(client *rancher.Client, principals *management.PrincipalCollection, name, principalType string) (*management.Principal, error) {
+	results, err := client.Management.Principal.CollectionActionSearch(principals, &management.SearchPrincipalsInput{
+		Name:          name,
+		PrincipalType: principalType,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, principal := range results.Data {
+		if principal.LoginName == name || principal.Name == name {
+			return &results.Data[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("no %s principal named %s found", principalType, name)
+}
+
+// loginAsProviderUser is a helper function that will log in through the given auth provider login endpoint and return a
+// client using the resulting token, along with the token so that it can be deleted once it is no longer needed.
+func loginAsProviderUser(client *rancher.Client, rancherConfig *rancher.Config, loginEndpoint, username,
+	password string) (*rancher.Client, *management.Token, error) {
+	bodyContent, err := json.Marshal(struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}{
+		Username: username,
+		Password: password,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	url := "https://" + rancherConfig.Host + loginEndpoint + "?action=" + login
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(bodyContent))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	insecure := rancherConfig.Insecure != nil && *rancherConfig.Insecure
+	httpClient := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure}},
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusForbidden {
+		return nil, nil, fmt.Errorf("login as %s failed: %s: %w", username, resp.Status, errLoginForbidden)
+	}
+
+	if resp.StatusCode >= 300 {
+		return nil, nil, fmt.Errorf("login as %s failed: %s", username, resp.Status)
+	}
+
+	byteContent, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	token := &management.Token{}
+	err = json.Unmarshal(byteContent, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	userClient, err := rancher.NewClient(token.Token, client.Session)
+	if err != nil {
+		deleteToken(client, token)
+		return nil, nil, err
+	}
+
+	return userClient, token, nil
+}
+
+// deleteToken is a helper function that will delete the login token as the admin user. Failures are only logged, as the
+// token expires on its own.
+func deleteToken(client *rancher.Client, token *management.Token) {
+	tokenID := token.ID
+	if tokenID == "" {
+		tokenID = strings.Split(token.Token, tokenSeparator)[0]
+	}
+
+	existingToken, err := client.Management.Token.ByID(tokenID)
+	if err == nil {
+		err = client.Management.Token.Delete(existingToken)
+	}
+
+	if err != nil {
+		logrus.Warnf("Failed to delete login token %s. Error: %v", tokenID, err)
+	}
+}
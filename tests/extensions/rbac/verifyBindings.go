@@ -0,0 +1,167 @@
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/rancher/norman/types"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/shepherd/clients/rancher"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	"github.com/rancher/shepherd/extensions/defaults"
+	"github.com/rancher/shepherd/extensions/users"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	kwait "k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	clusterRoleTemplateBindingType = "management.cattle.io.clusterroletemplatebinding"
+	globalRoleBindingType          = "management.cattle.io.globalrolebinding"
+	globalRoleType                 = "management.cattle.io.globalrole"
+	projectRoleTemplateBindingType = "management.cattle.io.projectroletemplatebinding"
+	roleTemplateType               = "management.cattle.io.roletemplate"
+)
+
+// VerifyBindings is a function that will verify, through the steve client, that every binding declared in the terratest
+// rbac config exists in Rancher with the expected role and subject.
+func VerifyBindings(t *testing.T, client *rancher.Client, clusterID, testUser string, rbacConfig *config.RBAC) {
+	userID, err := users.GetUserIDByName(client, testUser)
+	require.NoError(t, err)
+
+	roleTemplateIDs := map[string]string{}
+	for _, template := range rbacConfig.RoleTemplates {
+		roleTemplateIDs[template.Name], err = getIDByDisplayName(client, roleTemplateType, template.Name)
+		require.NoError(t, err)
+	}
+
+	globalRoleIDs := map[string]string{}
+	for _, role := range rbacConfig.GlobalRoles {
+		globalRoleIDs[role.Name], err = getIDByDisplayName(client, globalRoleType, role.Name)
+		require.NoError(t, err)
+	}
+
+	for _, binding := range rbacConfig.Bindings {
+		roleID := binding.Role
+		if binding.Scope == config.GlobalScope && globalRoleIDs[binding.Role] != "" {
+			roleID = globalRoleIDs[binding.Role]
+		} else if roleTemplateIDs[binding.Role] != "" {
+			roleID = roleTemplateIDs[binding.Role]
+		}
+
+		logrus.Infof("Verifying %s binding %s of role %s...", binding.Scope, binding.Name, roleID)
+
+		var projectID string
+		if binding.Scope == config.ProjectScope {
+			project := binding.Project
+			if project == "" {
+				project = tfpProject
+			}
+
+			projects, err := client.Management.Project.List(&types.ListOpts{
+				Filters: map[string]interface{}{"clusterId": clusterID, "name": project},
+			})
+			require.NoError(t, err)
+			require.NotEmpty(t, projects.Data, "Project %s not found in cluster %s", project, clusterID)
+
+			projectID = projects.Data[0].ID
+		}
+
+		err = kwait.PollUntilContextTimeout(context.TODO(), defaults.FiveSecondTimeout, defaults.TwoMinuteTimeout, true, func(ctx context.Context) (bool, error) {
+			return bindingExists(client, binding, clusterID, projectID, roleID, userID)
+		})
+		require.NoError(t, err, "Binding %s not found", binding.Name)
+	}
+}
+
+// bindingExists is a helper function that will check if a binding of the given role to the binding subject exists.
+func bindingExists(client *rancher.Client, binding config.RoleBinding, clusterID, projectID, roleID, userID string) (bool, error) {
+	switch binding.Scope {
+	case config.GlobalScope:
+		bindings, err := client.Steve.SteveType(globalRoleBindingType).List(nil)
+		if err != nil {
+			return false, err
+		}
+
+		for _, object := range bindings.Data {
+			globalRoleBinding := &v3.GlobalRoleBinding{}
+			err = steveV1.ConvertToK8sType(object.JSONResp, globalRoleBinding)
+			if err != nil {
+				return false, err
+			}
+
+			if globalRoleBinding.GlobalRoleName == roleID && subjectMatches(binding, globalRoleBinding.UserName, globalRoleBinding.GroupPrincipalName, userID) {
+				return true, nil
+			}
+		}
+	case config.ClusterScope:
+		bindings, err := client.Steve.SteveType(clusterRoleTemplateBindingType).NamespacedSteveClient(clusterID).List(nil)
+		if err != nil {
+			return false, err
+		}
+
+		for _, object := range bindings.Data {
+			clusterRoleTemplateBinding := &v3.ClusterRoleTemplateBinding{}
+			err = steveV1.ConvertToK8sType(object.JSONResp, clusterRoleTemplateBinding)
+			if err != nil {
+				return false, err
+			}
+
+			if clusterRoleTemplateBinding.RoleTemplateName == roleID &&
+				subjectMatches(binding, clusterRoleTemplateBinding.UserName, clusterRoleTemplateBinding.GroupPrincipalName, userID) {
+				return true, nil
+			}
+		}
+	case config.ProjectScope:
+		bindings, err := client.Steve.SteveType(projectRoleTemplateBindingType).List(nil)
+		if err != nil {
+			return false, err
+		}
+
+		for _, object := range bindings.Data {
+			projectRoleTemplateBinding := &v3.ProjectRoleTemplateBinding{}
+			err = steveV1.ConvertToK8sType(object.JSONResp, projectRoleTemplateBinding)
+			if err != nil {
+				return false, err
+			}
+
+			if projectRoleTemplateBinding.ProjectName == projectID && projectRoleTemplateBinding.RoleTemplateName == roleID &&
+				subjectMatches(binding, projectRoleTemplateBinding.UserName, projectRoleTemplateBinding.GroupPrincipalName, userID) {
+				return true, nil
+			}
+		}
+	default:
+		return false, fmt.Errorf("unsupported scope %q for binding %s", binding.Scope, binding.Name)
+	}
+
+	return false, nil
+}
+
+// subjectMatches is a helper function that will check if the subject of a binding is the declared group principal, or
+// the test user when no group principal is declared.
+func subjectMatches(binding config.RoleBinding, userName, groupPrincipalName, userID string) bool {
+	if binding.GroupPrincipalID != "" {
+		return groupPrincipalName == binding.GroupPrincipalID
+	}
+
+	return userName == userID
+}
+
+// getIDByDisplayName is a helper function that will return the ID of the steve object of the given type with the given
+// display name.
+func getIDByDisplayName(client *rancher.Client, steveType, displayName string) (string, error) {
+	objects, err := client.Steve.SteveType(steveType).List(nil)
+	if err != nil {
+		return "", err
+	}
+
+	for _, object := range objects.Data {
+		if object.JSONResp["displayName"] == displayName {
+			return object.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("%s %s not found", steveType, displayName)
+}
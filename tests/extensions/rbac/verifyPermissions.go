@@ -0,0 +1,294 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rancher/norman/types"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	"github.com/rancher/shepherd/extensions/defaults"
+	"github.com/rancher/shepherd/extensions/users"
+	"github.com/rancher/shepherd/extensions/workloads"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kwait "k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	ListNamespaces       = "list namespaces"
+	CreateNamespace      = "create namespace"
+	DeployWorkload       = "deploy workload"
+	ReadSecrets          = "read secrets"
+	EditCluster          = "edit cluster"
+	ManageClusterMembers = "manage cluster members"
+	ManageProjectMembers = "manage project members"
+
+	clusterMember     = "cluster-member"
+	containerImage    = "nginx"
+	defaultProject    = "Default"
+	deploymentType    = "apps.deployment"
+	description       = "description"
+	kubeSystem        = "kube-system"
+	namespaceType     = "namespace"
+	projectIDKey      = "field.cattle.io/projectId"
+	projectMember     = "project-member"
+	secretType        = "secret"
+	tfpProject        = "tfp-project"
+	verifyDescription = "tfp-automation rbac verification"
+)
+
+var errNoNamespace = errors.New("no namespace was created to deploy the workload in")
+
+// permissionOrder is the order in which the permissions are checked, as creating a namespace is needed before deploying
+// a workload.
+var permissionOrder = []string{ListNamespaces, CreateNamespace, DeployWorkload, ReadSecrets, EditCluster, ManageClusterMembers,
+	ManageProjectMembers}
+
+// expectedPermissions is the allow/deny matrix of each role that is bound by the RBAC generator.
+var expectedPermissions = map[config.Role]map[string]bool{
+	config.ClusterOwner: {
+		ListNamespaces:       true,
+		CreateNamespace:      true,
+		DeployWorkload:       true,
+		ReadSecrets:          true,
+		EditCluster:          true,
+		ManageClusterMembers: true,
+		ManageProjectMembers: true,
+	},
+	config.ProjectOwner: {
+		ListNamespaces:       true,
+		CreateNamespace:      true,
+		DeployWorkload:       true,
+		ReadSecrets:          false,
+		EditCluster:          false,
+		ManageClusterMembers: false,
+		ManageProjectMembers: true,
+	},
+	config.ClusterMember: {
+		ListNamespaces:       true,
+		CreateNamespace:      false,
+		DeployWorkload:       false,
+		ReadSecrets:          false,
+		EditCluster:          false,
+		ManageClusterMembers: false,
+		ManageProjectMembers: false,
+	},
+	config.ProjectMember: {
+		ListNamespaces:       true,
+		CreateNamespace:      true,
+		DeployWorkload:       true,
+		ReadSecrets:          false,
+		EditCluster:          false,
+		ManageClusterMembers: false,
+		ManageProjectMembers: false,
+	},
+	config.ReadOnly: {
+		ListNamespaces:       true,
+		CreateNamespace:      false,
+		DeployWorkload:       false,
+		ReadSecrets:          false,
+		EditCluster:          false,
+		ManageClusterMembers: false,
+		ManageProjectMembers: false,
+	},
+}
+
+// permissionCheck holds what is needed to run each permission check as the bound user.
+type permissionCheck struct {
+	client      *rancher.Client
+	steveClient *steveV1.Client
+	clusterID   string
+	project     *management.Project
+	userID      string
+	namespace   string
+}
+
+// VerifyPermissions is a function that will log in as the test user bound by the RBAC generator and verify that the
+// user is allowed or denied each action according to the expected permissions of the role.
+func VerifyPermissions(t *testing.T, client *rancher.Client, clusterID, testUser, testPassword string, rbacRole config.Role) {
+	expected, ok := expectedPermissions[rbacRole]
+	require.True(t, ok, "No expected permissions defined for role %s", rbacRole)
+
+	userClient, err := client.AsUser(&management.User{Username: testUser, Password: testPassword})
+	require.NoError(t, err)
+
+	userID, err := users.GetUserIDByName(client, testUser)
+	require.NoError(t, err)
+	require.NotEmpty(t, userID, "User %s not found", testUser)
+
+	projectName := defaultProject
+	switch rbacRole {
+	case config.ProjectOwner, config.ProjectMember, config.ReadOnly:
+		projectName = tfpProject
+	}
+
+	projects, err := client.Management.Project.List(&types.ListOpts{
+		Filters: map[string]interface{}{"clusterId": clusterID, "name": projectName},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, projects.Data, "Project %s not found in cluster %s", projectName, clusterID)
+
+	var steveClient *steveV1.Client
+
+	logrus.Infof("Waiting for %s permissions of user %s to be rolled out...", rbacRole, testUser)
+	err = kwait.PollUntilContextTimeout(context.TODO(), defaults.FiveSecondTimeout, defaults.FiveMinuteTimeout, true, func(ctx context.Context) (bool, error) {
+		steveClient, err = userClient.Steve.ProxyDownstream(clusterID)
+		if err != nil {
+			return false, nil
+		}
+
+		_, err = steveClient.SteveType(namespaceType).List(nil)
+
+		return err == nil, nil
+	})
+	require.NoError(t, err)
+
+	check := &permissionCheck{
+		client:      userClient,
+		steveClient: steveClient,
+		clusterID:   clusterID,
+		project:     &projects.Data[0],
+		userID:      userID,
+	}
+
+	defer deleteNamespace(client, clusterID, check)
+
+	checks := map[string]func() error{
+		ListNamespaces:       check.listNamespaces,
+		CreateNamespace:      check.createNamespace,
+		DeployWorkload:       check.deployWorkload,
+		ReadSecrets:          check.readSecrets,
+		EditCluster:          check.editCluster,
+		ManageClusterMembers: check.manageClusterMembers,
+		ManageProjectMembers: check.manageProjectMembers,
+	}
+
+	var mismatches []string
+	for _, permission := range permissionOrder {
+		err := checks[permission]()
+		allowed := err == nil
+
+		logrus.Infof("Role %s: %s allowed: %t, expected: %t", rbacRole, permission, allowed, expected[permission])
+		if allowed != expected[permission] {
+			if err != nil {
+				logrus.Warnf("Role %s: %s failed. Error: %v", rbacRole, permission, err)
+			}
+
+			mismatches = append(mismatches, permission)
+		}
+	}
+
+	require.Empty(t, mismatches, "Effective permissions of role %s do not match the expected permissions", rbacRole)
+}
+
+// deleteNamespace is a helper function that will delete the namespace created by the permission checks, and the
+// workload deployed in it, as the admin user. Failures are only logged so that they do not hide the permission results.
+func deleteNamespace(client *rancher.Client, clusterID string, check *permissionCheck) {
+	if check.namespace == "" {
+		return
+	}
+
+	steveClient, err := client.Steve.ProxyDownstream(clusterID)
+	if err != nil {
+		logrus.Warnf("Failed to delete namespace %s. Error: %v", check.namespace, err)
+		return
+	}
+
+	namespace, err := steveClient.SteveType(namespaceType).ByID(check.namespace)
+	if err == nil {
+		err = steveClient.SteveType(namespaceType).Delete(namespace)
+	}
+
+	if err != nil {
+		logrus.Warnf("Failed to delete namespace %s. Error: %v", check.namespace, err)
+	}
+}
+
+func (p *permissionCheck) listNamespaces() error {
+	_, err := p.steveClient.SteveType(namespaceType).List(nil)
+	return err
+}
+
+func (p *permissionCheck) createNamespace() error {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        namegen.AppendRandomString("tfp-rbac"),
+			Annotations: map[string]string{projectIDKey: p.project.ID},
+		},
+	}
+
+	_, err := p.steveClient.SteveType(namespaceType).Create(namespace)
+	if err != nil {
+		return err
+	}
+
+	p.namespace = namespace.Name
+
+	return nil
+}
+
+func (p *permissionCheck) deployWorkload() error {
+	if p.namespace == "" {
+		return errNoNamespace
+	}
+
+	container := workloads.NewContainer(containerImage, containerImage, corev1.PullAlways, nil, nil, nil, nil, nil)
+	podTemplate := workloads.NewPodTemplate([]corev1.Container{container}, nil, nil, nil, nil)
+	deployment := workloads.NewDeploymentTemplate(namegen.AppendRandomString("tfp-rbac"), p.namespace, podTemplate, true, nil)
+
+	_, err := p.steveClient.SteveType(deploymentType).Create(deployment)
+	return err
+}
+
+func (p *permissionCheck) readSecrets() error {
+	_, err := p.steveClient.SteveType(secretType).NamespacedSteveClient(kubeSystem).List(nil)
+	return err
+}
+
+func (p *permissionCheck) editCluster() error {
+	cluster, err := p.client.Management.Cluster.ByID(p.clusterID)
+	if err != nil {
+		return err
+	}
+
+	updatedCluster, err := p.client.Management.Cluster.Update(cluster, map[string]any{description: verifyDescription})
+	if err != nil {
+		return err
+	}
+
+	_, err = p.client.Management.Cluster.Update(updatedCluster, map[string]any{description: cluster.Description})
+	return err
+}
+
+func (p *permissionCheck) manageClusterMembers() error {
+	binding, err := p.client.Management.ClusterRoleTemplateBinding.Create(&management.ClusterRoleTemplateBinding{
+		ClusterID:      p.clusterID,
+		RoleTemplateID: clusterMember,
+		UserID:         p.userID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.client.Management.ClusterRoleTemplateBinding.Delete(binding)
+}
+
+func (p *permissionCheck) manageProjectMembers() error {
+	binding, err := p.client.Management.ProjectRoleTemplateBinding.Create(&management.ProjectRoleTemplateBinding{
+		ProjectID:      p.project.ID,
+		RoleTemplateID: projectMember,
+		UserID:         p.userID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.client.Management.ProjectRoleTemplateBinding.Delete(binding)
+}
@@ -0,0 +1,94 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/rancher/shepherd/clients/rancher"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	value = "value"
+)
+
+// OriginalValues holds the values of the global settings and feature flags before Terraform changes them.
+type OriginalValues struct {
+	Features map[string]*bool
+	Settings map[string]string
+}
+
+// GetOriginalValues is a function that will record the current values of the global settings and feature flags
+// declared in the terratest config, so that they can be restored once the test is done.
+func GetOriginalValues(t *testing.T, client *rancher.Client, globalSettings *config.GlobalSettings) *OriginalValues {
+	originalValues := &OriginalValues{
+		Features: map[string]*bool{},
+		Settings: map[string]string{},
+	}
+
+	for _, tfpSetting := range globalSettings.Settings {
+		setting, err := client.Management.Setting.ByID(tfpSetting.Name)
+		require.NoError(t, err)
+
+		originalValues.Settings[tfpSetting.Name] = setting.Value
+	}
+
+	for _, tfpFeature := range globalSettings.Features {
+		feature, err := client.Management.Feature.ByID(tfpFeature.Name)
+		require.NoError(t, err)
+
+		originalValues.Features[tfpFeature.Name] = feature.Value
+	}
+
+	return originalValues
+}
+
+// RestoreOriginalValues is a function that will restore the global settings and feature flags to the values recorded
+// by GetOriginalValues. Failures are logged rather than failing the test, so that every value gets a restore attempt.
+func RestoreOriginalValues(client *rancher.Client, originalValues *OriginalValues) {
+	for name, originalValue := range originalValues.Settings {
+		setting, err := client.Management.Setting.ByID(name)
+		if err != nil {
+			logrus.Infof("Failed to get setting %s. Error: %v", name, err)
+			continue
+		}
+
+		if setting.Value == originalValue {
+			continue
+		}
+
+		logrus.Infof("Restoring setting %s to %q...", name, originalValue)
+		_, err = client.Management.Setting.Update(setting, map[string]any{value: originalValue})
+		if err != nil {
+			logrus.Infof("Failed to restore setting %s. Error: %v", name, err)
+		}
+	}
+
+	for name, originalValue := range originalValues.Features {
+		feature, err := client.Management.Feature.ByID(name)
+		if err != nil {
+			logrus.Infof("Failed to get feature %s. Error: %v", name, err)
+			continue
+		}
+
+		if boolEqual(feature.Value, originalValue) {
+			continue
+		}
+
+		logrus.Infof("Restoring feature %s...", name)
+		_, err = client.Management.Feature.Update(feature, map[string]any{value: originalValue})
+		if err != nil {
+			logrus.Infof("Failed to restore feature %s. Error: %v", name, err)
+		}
+	}
+}
+
+// boolEqual is a helper function that will compare two optional booleans.
+func boolEqual(a, b *bool) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
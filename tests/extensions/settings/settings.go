@@ -0,0 +1,20 @@
+package settings
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	framework "github.com/rancher/tfp-automation/framework/set"
+	"github.com/stretchr/testify/require"
+)
+
+// Settings is a function that will run terraform apply to set the global settings, feature flags and tokens.
+func Settings(t *testing.T, terraformOptions *terraform.Options, configMap []map[string]any, newFile *hclwrite.File, rootBody *hclwrite.Body,
+	file *os.File) {
+	err := framework.SettingsConfig(configMap, newFile, rootBody, file)
+	require.NoError(t, err)
+
+	terraform.InitAndApply(t, terraformOptions)
+}
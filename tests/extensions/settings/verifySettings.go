@@ -0,0 +1,113 @@
+package settings
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/rancher/shepherd/clients/rancher"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/framework/set/settings"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	currentUserEndpoint   = "/v3/users?me=true"
+	clusterVersionFormat  = "/k8s/clusters/%s/version"
+	expiryTolerance       = time.Minute
+	millisecondsPerSecond = 1000
+)
+
+// VerifySettings is a function that will verify that the global settings, feature flags and tokens declared in the
+// terratest config were applied by Terraform.
+func VerifySettings(t *testing.T, client *rancher.Client, rancherConfig *rancher.Config, terraformOptions *terraform.Options,
+	globalSettings *config.GlobalSettings) {
+	for _, tfpSetting := range globalSettings.Settings {
+		logrus.Infof("Verifying setting %s...", tfpSetting.Name)
+
+		setting, err := client.Management.Setting.ByID(tfpSetting.Name)
+		require.NoError(t, err)
+		assert.Equal(t, tfpSetting.Value, setting.Value)
+	}
+
+	for _, tfpFeature := range globalSettings.Features {
+		logrus.Infof("Verifying feature %s...", tfpFeature.Name)
+
+		feature, err := client.Management.Feature.ByID(tfpFeature.Name)
+		require.NoError(t, err)
+		require.NotNil(t, feature.Value)
+		assert.Equal(t, tfpFeature.Value, *feature.Value)
+	}
+
+	for _, tfpToken := range globalSettings.Tokens {
+		logrus.Infof("Verifying token %s...", tfpToken.Name)
+
+		tokenValue := terraform.Output(t, terraformOptions, tfpToken.Name)
+		tokenID := terraform.Output(t, terraformOptions, tfpToken.Name+settings.TokenIDSuffix)
+
+		token, err := client.Management.Token.ByID(tokenID)
+		require.NoError(t, err)
+		assert.Equal(t, tfpToken.ClusterID, token.ClusterID)
+		assert.False(t, token.Expired)
+
+		if tfpToken.TTL > 0 {
+			verifyExpiry(t, token.Created, token.ExpiresAt, token.TTLMillis, tfpToken.TTL)
+		}
+
+		endpoint := currentUserEndpoint
+		if tfpToken.ClusterID != "" {
+			endpoint = fmt.Sprintf(clusterVersionFormat, tfpToken.ClusterID)
+		}
+
+		statusCode, err := callWithToken(rancherConfig, endpoint, tokenValue)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode, "request to %s with token %s failed", endpoint, tfpToken.Name)
+	}
+}
+
+// verifyExpiry is a helper function that will verify that the token TTL matches the configured TTL and that the token
+// expires roughly TTL seconds after it was created.
+func verifyExpiry(t *testing.T, created, expiresAt string, ttlMillis, ttlSeconds int64) {
+	assert.Equal(t, ttlSeconds*millisecondsPerSecond, ttlMillis)
+	require.NotEmpty(t, expiresAt)
+
+	createdTime, err := time.Parse(time.RFC3339, created)
+	require.NoError(t, err)
+
+	expiresAtTime, err := time.Parse(time.RFC3339, expiresAt)
+	require.NoError(t, err)
+
+	expectedExpiry := createdTime.Add(time.Duration(ttlSeconds) * time.Second)
+	assert.WithinDuration(t, expectedExpiry, expiresAtTime, expiryTolerance)
+	assert.True(t, expiresAtTime.After(time.Now()), "token expired at %s", expiresAt)
+}
+
+// callWithToken is a helper function that will call the given Rancher endpoint with the token as a bearer token and
+// return the response status code.
+func callWithToken(rancherConfig *rancher.Config, endpoint, token string) (int, error) {
+	req, err := http.NewRequest(http.MethodGet, "https://"+rancherConfig.Host+endpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	insecure := rancherConfig.Insecure != nil && *rancherConfig.Insecure
+	httpClient := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure}},
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	defer resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,63 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rancher/norman/types"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/extensions/defaults"
+	"github.com/rancher/shepherd/extensions/etcdsnapshot"
+	kwait "k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	active      = "active"
+	restoreNone = "none"
+)
+
+// WaitForRKE1Backup is a function that will wait until the etcd backup with the given name of a RKE1 cluster is active
+// and return it.
+func WaitForRKE1Backup(client *rancher.Client, clusterID, backupName string) (*management.EtcdBackup, error) {
+	var backup *management.EtcdBackup
+
+	err := kwait.PollUntilContextTimeout(context.TODO(), defaults.FiveSecondTimeout, defaults.TenMinuteTimeout, true, func(ctx context.Context) (bool, error) {
+		backups, err := client.Management.EtcdBackup.List(&types.ListOpts{
+			Filters: map[string]interface{}{
+				"clusterId": clusterID,
+				"name":      backupName,
+			},
+		})
+		if err != nil || len(backups.Data) == 0 {
+			return false, nil
+		}
+
+		if backups.Data[0].State != active {
+			return false, nil
+		}
+
+		backup = &backups.Data[0]
+
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("etcd backup %s of cluster %s is not active: %w", backupName, clusterID, err)
+	}
+
+	return backup, nil
+}
+
+// RestoreRKE1Backup is a function that will restore the given etcd backup of a RKE1 cluster. The rancher2 provider has
+// no restore for RKE1 clusters, so the restore is done through the Rancher API. The `none` restore mode of RKE2/K3s
+// clusters maps to an etcd only restore.
+func RestoreRKE1Backup(client *rancher.Client, clusterName, backupID, restoreRKEConfig string) error {
+	if restoreRKEConfig == restoreNone {
+		restoreRKEConfig = ""
+	}
+
+	return etcdsnapshot.RestoreRKE1Snapshot(client, clusterName, &management.RestoreFromEtcdBackupInput{
+		EtcdBackupID:     backupID,
+		RestoreRkeConfig: restoreRKEConfig,
+	})
+}
@@ -0,0 +1,197 @@
+package snapshot
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/rancher/shepherd/clients/rancher"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	"github.com/rancher/shepherd/extensions/defaults"
+	"github.com/rancher/shepherd/extensions/defaults/namespaces"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/defaults/stevetypes"
+	kwait "k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	clusterNameLabel = "rke.cattle.io/cluster-name"
+	defaultS3Region  = "us-east-1"
+)
+
+// GetClusterSnapshots is a function that will return the etcd snapshots of the given cluster, split into the snapshots
+// stored in S3 and the snapshots stored locally on the nodes.
+func GetClusterSnapshots(client *rancher.Client, clusterName string) ([]steveV1.SteveAPIObject, []steveV1.SteveAPIObject, error) {
+	query := map[string][]string{"labelSelector": {clusterNameLabel + "=" + clusterName}}
+
+	snapshots, err := client.Steve.SteveType(stevetypes.EtcdSnapshot).NamespacedSteveClient(namespaces.FleetDefault).List(query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var s3Snapshots, localSnapshots []steveV1.SteveAPIObject
+
+	for _, snapshotObject := range snapshots.Data {
+		etcdSnapshot := &rkev1.ETCDSnapshot{}
+		err = steveV1.ConvertToK8sType(snapshotObject.JSONResp, etcdSnapshot)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if etcdSnapshot.SnapshotFile.S3 != nil {
+			s3Snapshots = append(s3Snapshots, snapshotObject)
+		} else {
+			localSnapshots = append(localSnapshots, snapshotObject)
+		}
+	}
+
+	return s3Snapshots, localSnapshots, nil
+}
+
+// WaitForNewS3Snapshot is a function that will wait for an etcd snapshot of the given cluster that is stored in S3 and
+// is not in the given list of existing snapshot names, and return it.
+func WaitForNewS3Snapshot(client *rancher.Client, clusterName string, existingSnapshots []string) (*rkev1.ETCDSnapshot, error) {
+	existing := map[string]bool{}
+	for _, name := range existingSnapshots {
+		existing[name] = true
+	}
+
+	etcdSnapshot := &rkev1.ETCDSnapshot{}
+
+	err := kwait.PollUntilContextTimeout(context.TODO(), defaults.FiveSecondTimeout, defaults.TenMinuteTimeout, true, func(ctx context.Context) (bool, error) {
+		s3Snapshots, _, err := GetClusterSnapshots(client, clusterName)
+		if err != nil {
+			return false, nil
+		}
+
+		for _, snapshotObject := range s3Snapshots {
+			if existing[snapshotObject.ObjectMeta.Name] {
+				continue
+			}
+
+			err = steveV1.ConvertToK8sType(snapshotObject.JSONResp, etcdSnapshot)
+			if err != nil {
+				return false, err
+			}
+
+			return true, nil
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return etcdSnapshot, nil
+}
+
+// DeleteLocalSnapshots is a function that will delete the etcd snapshots of the given cluster that are stored locally on
+// the nodes and wait for them to be removed, so that only the snapshots stored in S3 remain.
+func DeleteLocalSnapshots(client *rancher.Client, clusterName string) error {
+	_, localSnapshots, err := GetClusterSnapshots(client, clusterName)
+	if err != nil {
+		return err
+	}
+
+	for _, localSnapshot := range localSnapshots {
+		err = client.Steve.SteveType(stevetypes.EtcdSnapshot).Delete(&localSnapshot)
+		if err != nil {
+			return err
+		}
+	}
+
+	return kwait.PollUntilContextTimeout(context.TODO(), defaults.FiveSecondTimeout, defaults.TenMinuteTimeout, true, func(ctx context.Context) (bool, error) {
+		_, localSnapshots, err := GetClusterSnapshots(client, clusterName)
+		if err != nil {
+			return false, nil
+		}
+
+		return len(localSnapshots) == 0, nil
+	})
+}
+
+// ListS3SnapshotObjects is a function that will list the keys of the objects in the etcd snapshot bucket, using the
+// dedicated S3 credentials and the cluster's etcd S3 configuration.
+func ListS3SnapshotObjects(terraformConfig *config.TerraformConfig) ([]string, error) {
+	if terraformConfig.S3Credentials == nil || terraformConfig.ETCD == nil || terraformConfig.ETCD.S3 == nil {
+		return nil, errors.New("s3Credentials and etcd.s3 must be configured to list S3 snapshots")
+	}
+
+	s3Config := terraformConfig.ETCD.S3
+	s3Credentials := terraformConfig.S3Credentials
+
+	bucket := firstNonEmpty(s3Config.Bucket, s3Credentials.Bucket)
+	endpoint := firstNonEmpty(s3Config.Endpoint, s3Credentials.Endpoint)
+	endpointCA := firstNonEmpty(s3Config.EndpointCA, s3Credentials.EndpointCA)
+	region := firstNonEmpty(s3Config.Region, s3Credentials.Region, defaultS3Region)
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: s3Config.SkipSSLVerify || s3Credentials.SkipSSLVerify}
+	if endpointCA != "" {
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM([]byte(endpointCA)) {
+			return nil, errors.New("failed to parse the S3 endpoint CA")
+		}
+
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	awsConfig := &aws.Config{
+		Credentials:      credentials.NewStaticCredentials(s3Credentials.AccessKey, s3Credentials.SecretKey, ""),
+		Region:           aws.String(region),
+		S3ForcePathStyle: aws.Bool(true),
+		HTTPClient:       &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}},
+	}
+
+	if endpoint != "" {
+		if !strings.HasPrefix(endpoint, "http") {
+			endpoint = "https://" + endpoint
+		}
+
+		awsConfig.Endpoint = aws.String(endpoint)
+	}
+
+	s3Session, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &s3.ListObjectsV2Input{Bucket: aws.String(bucket)}
+	if s3Config.Folder != "" {
+		input.Prefix = aws.String(s3Config.Folder)
+	}
+
+	var keys []string
+
+	err = s3.New(s3Session).ListObjectsV2Pages(input, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			keys = append(keys, aws.StringValue(object.Key))
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// firstNonEmpty is a helper function that will return the first of the given values that is not empty.
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,86 @@
+package snapshot
+
+import (
+	provv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/rancher/shepherd/clients/rancher"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	"github.com/rancher/shepherd/extensions/defaults/namespaces"
+	"github.com/rancher/shepherd/pkg/config/operations"
+	"github.com/rancher/tfp-automation/defaults/stevetypes"
+)
+
+// GetSnapshotGenerations is a function that will return the latest etcd snapshot create and restore generations of the
+// given RKE2/K3s cluster. The provisioning cluster spec only holds the generations that are currently declared, so the
+// generations last handled by the RKE control plane are also taken into account.
+func GetSnapshotGenerations(client *rancher.Client, clusterName string) (int64, int64, error) {
+	clusterObject, err := client.Steve.SteveType(stevetypes.Provisioning).ByID(namespaces.FleetDefault + "/" + clusterName)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	cluster := &provv1.Cluster{}
+	err = steveV1.ConvertToK8sType(clusterObject.JSONResp, cluster)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	controlPlaneObject, err := client.Steve.SteveType(stevetypes.RKEControlPlane).ByID(namespaces.FleetDefault + "/" + clusterName)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	controlPlane := &rkev1.RKEControlPlane{}
+	err = steveV1.ConvertToK8sType(controlPlaneObject.JSONResp, controlPlane)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var createGeneration, restoreGeneration int
+
+	if cluster.Spec.RKEConfig != nil {
+		if cluster.Spec.RKEConfig.ETCDSnapshotCreate != nil {
+			createGeneration = cluster.Spec.RKEConfig.ETCDSnapshotCreate.Generation
+		}
+
+		if cluster.Spec.RKEConfig.ETCDSnapshotRestore != nil {
+			restoreGeneration = cluster.Spec.RKEConfig.ETCDSnapshotRestore.Generation
+		}
+	}
+
+	if controlPlane.Status.ETCDSnapshotCreate != nil {
+		createGeneration = max(createGeneration, controlPlane.Status.ETCDSnapshotCreate.Generation)
+	}
+
+	if controlPlane.Status.ETCDSnapshotRestore != nil {
+		restoreGeneration = max(restoreGeneration, controlPlane.Status.ETCDSnapshotRestore.Generation)
+	}
+
+	return int64(createGeneration), int64(restoreGeneration), nil
+}
+
+// SetNextCreateSnapshotGeneration is a function that will set the create snapshot generation in the config to the next
+// generation of the given RKE2/K3s cluster, so that the next terraform apply takes a new snapshot.
+func SetNextCreateSnapshotGeneration(client *rancher.Client, clusterName string, configMap []map[string]any) (int64, error) {
+	createGeneration, _, err := GetSnapshotGenerations(client, clusterName)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = operations.ReplaceValue([]string{"terratest", "snapshotInput", "createSnapshotGeneration"}, createGeneration+1, configMap[0])
+
+	return createGeneration + 1, err
+}
+
+// SetNextRestoreSnapshotGeneration is a function that will set the restore snapshot generation in the config to the
+// next generation of the given RKE2/K3s cluster, so that the next terraform apply restores the snapshot.
+func SetNextRestoreSnapshotGeneration(client *rancher.Client, clusterName string, configMap []map[string]any) (int64, error) {
+	_, restoreGeneration, err := GetSnapshotGenerations(client, clusterName)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = operations.ReplaceValue([]string{"terratest", "snapshotInput", "restoreSnapshotGeneration"}, restoreGeneration+1, configMap[0])
+
+	return restoreGeneration + 1, err
+}
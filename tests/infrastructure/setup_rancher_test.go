@@ -43,6 +43,10 @@ func (i *RancherTestSuite) TestCreateRancher() {
 		logrus.Infof("Rancher server URL: %s", i.terraformConfig.Standalone.RancherHostname)
 		logrus.Infof("Booststrap password: %s", i.terraformConfig.Standalone.BootstrapPassword)
 
+		if i.terraformConfig.StandaloneMinIO != nil && i.terraformConfig.S3Credentials != nil {
+			logrus.Infof("MinIO endpoint: %s", i.terraformConfig.S3Credentials.Endpoint)
+		}
+
 		testSession := session.NewSession()
 		i.session = testSession
 
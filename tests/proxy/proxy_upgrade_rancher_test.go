@@ -2,6 +2,7 @@ package proxy
 
 import (
 	"os"
+	"slices"
 	"strings"
 	"testing"
 
@@ -21,7 +22,6 @@ import (
 	"github.com/rancher/tfp-automation/framework/cleanup"
 	resources "github.com/rancher/tfp-automation/framework/set/resources/proxy"
 	"github.com/rancher/tfp-automation/framework/set/resources/rancher2"
-	upgrade "github.com/rancher/tfp-automation/framework/set/resources/upgrade"
 	qase "github.com/rancher/tfp-automation/pipeline/qase/results"
 	"github.com/rancher/tfp-automation/tests/extensions/provisioning"
 	"github.com/stretchr/testify/require"
@@ -115,26 +115,23 @@ func (p *TfpProxyUpgradeRancherTestSuite) TfpSetupSuite() map[string]any {
 }
 
 func (p *TfpProxyUpgradeRancherTestSuite) TestTfpUpgradeProxyRancher() {
-	var clusterIDs []string
-
-	p.provisionAndVerifyCluster("Pre-Upgrade Proxy ", clusterIDs, false)
+	clusterIDs := p.provisionAndVerifyCluster("Pre-Upgrade Proxy ", false)
 
 	p.terraformConfig.Standalone.UpgradeProxyRancher = true
 
 	keyPath := rancher2.SetKeyPath(keypath.UpgradeKeyPath, p.terraformConfig.Provider)
-	err := upgrade.CreateMainTF(p.T(), p.upgradeTerraformOptions, keyPath, p.terraformConfig, p.terratestConfig, p.proxyPrivateIP, p.proxyNode, "", "")
-	require.NoError(p.T(), err)
+	provisioning.RancherUpgrade(p.T(), p.client, p.terraformConfig, p.terratestConfig, clusterIDs, p.terraformOptions, p.upgradeTerraformOptions, keyPath, p.proxyPrivateIP, p.proxyNode, "", "")
 
-	provisioning.VerifyClustersState(p.T(), p.client, clusterIDs)
-
-	p.provisionAndVerifyCluster("Post-Upgrade Proxy ", clusterIDs, true)
+	p.provisionAndVerifyCluster("Post-Upgrade Proxy ", true)
 
 	if p.terratestConfig.LocalQaseReporting {
 		qase.ReportTest()
 	}
 }
 
-func (p *TfpProxyUpgradeRancherTestSuite) provisionAndVerifyCluster(name string, clusterIDs []string, deleteClusters bool) []string {
+func (p *TfpProxyUpgradeRancherTestSuite) provisionAndVerifyCluster(name string, deleteClusters bool) []string {
+	var clusterIDs []string
+
 	nodeRolesDedicated := []config.Nodepool{config.EtcdNodePool, config.ControlPlaneNodePool, config.WorkerNodePool}
 
 	tests := []struct {
@@ -174,12 +171,20 @@ func (p *TfpProxyUpgradeRancherTestSuite) provisionAndVerifyCluster(name string,
 		tt.name = name + tt.name + " Kubernetes version: " + terratest.KubernetesVersion
 
 		p.Run((tt.name), func() {
-			clusterIDs, customClusterNames = provisioning.Provision(p.T(), p.client, rancher, terraform, testUser, testPassword, p.terraformOptions, configMap, newFile, rootBody, file, false, true, true, customClusterNames)
-			provisioning.VerifyClustersState(p.T(), p.client, clusterIDs)
+			var provisionedClusterIDs []string
+			provisionedClusterIDs, customClusterNames = provisioning.Provision(p.T(), p.client, rancher, terraform, testUser, testPassword, p.terraformOptions, configMap, newFile, rootBody, file, false, true, true, customClusterNames)
+			provisioning.VerifyClustersState(p.T(), p.client, provisionedClusterIDs)
+			clusterIDs = append(clusterIDs, provisionedClusterIDs...)
 
 			if strings.Contains(terraform.Module, modules.CustomEC2RKE2Windows) {
-				clusterIDs, _ = provisioning.Provision(p.T(), p.client, rancher, terraform, testUser, testPassword, p.terraformOptions, configMap, newFile, rootBody, file, true, true, true, customClusterNames)
-				provisioning.VerifyClustersState(p.T(), p.client, clusterIDs)
+				provisionedClusterIDs, _ = provisioning.Provision(p.T(), p.client, rancher, terraform, testUser, testPassword, p.terraformOptions, configMap, newFile, rootBody, file, true, true, true, customClusterNames)
+				provisioning.VerifyClustersState(p.T(), p.client, provisionedClusterIDs)
+
+				for _, clusterID := range provisionedClusterIDs {
+					if !slices.Contains(clusterIDs, clusterID) {
+						clusterIDs = append(clusterIDs, clusterID)
+					}
+				}
 			}
 		})
 	}
@@ -0,0 +1,122 @@
+package apps
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/rancher/shepherd/clients/rancher"
+	shepherdConfig "github.com/rancher/shepherd/pkg/config"
+	"github.com/rancher/shepherd/pkg/config/operations"
+	"github.com/rancher/shepherd/pkg/session"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/defaults/configs"
+	"github.com/rancher/tfp-automation/defaults/keypath"
+	"github.com/rancher/tfp-automation/framework"
+	"github.com/rancher/tfp-automation/framework/cleanup"
+	"github.com/rancher/tfp-automation/framework/set/resources/rancher2"
+	qase "github.com/rancher/tfp-automation/pipeline/qase/results"
+	"github.com/rancher/tfp-automation/tests/extensions/apps"
+	"github.com/rancher/tfp-automation/tests/extensions/provisioning"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type AppsTestSuite struct {
+	suite.Suite
+	client           *rancher.Client
+	session          *session.Session
+	cattleConfig     map[string]any
+	rancherConfig    *rancher.Config
+	terraformConfig  *config.TerraformConfig
+	terratestConfig  *config.TerratestConfig
+	terraformOptions *terraform.Options
+}
+
+func (a *AppsTestSuite) SetupSuite() {
+	testSession := session.NewSession()
+	a.session = testSession
+
+	client, err := rancher.NewClient("", testSession)
+	require.NoError(a.T(), err)
+
+	a.client = client
+
+	a.cattleConfig = shepherdConfig.LoadConfigFromFile(os.Getenv(shepherdConfig.ConfigEnvironmentKey))
+
+	a.cattleConfig, err = config.LoadProvisioningDefaults(a.cattleConfig, "")
+	require.NoError(a.T(), err)
+
+	a.cattleConfig, err = config.LoadPackageDefaults(a.cattleConfig, "")
+	require.NoError(a.T(), err)
+
+	configMap, err := provisioning.UniquifyTerraform([]map[string]any{a.cattleConfig})
+	require.NoError(a.T(), err)
+
+	a.cattleConfig = configMap[0]
+	a.rancherConfig, a.terraformConfig, a.terratestConfig = config.LoadTFPConfigs(a.cattleConfig)
+
+	keyPath := rancher2.SetKeyPath(keypath.RancherKeyPath, "")
+	terraformOptions := framework.Setup(a.T(), a.terraformConfig, a.terratestConfig, keyPath)
+	a.terraformOptions = terraformOptions
+
+	provisioning.GetK8sVersion(a.T(), a.client, a.terratestConfig, a.terraformConfig, configs.DefaultK8sVersion, configMap)
+}
+
+func (a *AppsTestSuite) TestTfpApps() {
+	if a.terratestConfig.Marketplace == nil {
+		a.T().Skip("No marketplace specified")
+	}
+
+	nodeRolesDedicated := []config.Nodepool{config.EtcdNodePool, config.ControlPlaneNodePool, config.WorkerNodePool}
+
+	tests := []struct {
+		name      string
+		nodeRoles []config.Nodepool
+	}{
+		{"Install, Upgrade and Uninstall Apps", nodeRolesDedicated},
+	}
+
+	configMap := []map[string]any{a.cattleConfig}
+	testUser, testPassword := configs.CreateTestCredentials()
+
+	for _, tt := range tests {
+		newFile, rootBody, file := rancher2.InitializeMainTF()
+		defer file.Close()
+
+		_, err := operations.ReplaceValue([]string{"terratest", "nodepools"}, tt.nodeRoles, configMap[0])
+		require.NoError(a.T(), err)
+
+		rancher, terraform, terratest := config.LoadTFPConfigs(configMap[0])
+
+		tt.name = tt.name + " Module: " + a.terraformConfig.Module
+
+		a.Run((tt.name), func() {
+			keyPath := rancher2.SetKeyPath(keypath.RancherKeyPath, "")
+			defer cleanup.Cleanup(a.T(), a.terraformOptions, keyPath)
+
+			adminClient, err := provisioning.FetchAdminClient(a.T(), a.client)
+			require.NoError(a.T(), err)
+
+			clusterIDs, _ := provisioning.Provision(a.T(), adminClient, rancher, terraform, testUser, testPassword, a.terraformOptions, configMap, newFile, rootBody, file, false, false, false, nil)
+			provisioning.VerifyClustersState(a.T(), adminClient, clusterIDs)
+			apps.VerifyApps(a.T(), adminClient, clusterIDs, terratest.Marketplace.Apps)
+
+			apps.UpgradeApps(a.T(), adminClient, testUser, testPassword, a.terraformOptions, configMap, newFile, rootBody, file)
+
+			_, _, upgradedTerratest := config.LoadTFPConfigs(configMap[0])
+			apps.VerifyApps(a.T(), adminClient, clusterIDs, upgradedTerratest.Marketplace.Apps)
+
+			apps.UninstallApps(a.T(), adminClient, testUser, testPassword, a.terraformOptions, configMap, newFile, rootBody, file)
+			apps.VerifyAppsRemoved(a.T(), adminClient, clusterIDs, terratest.Marketplace.Apps)
+		})
+	}
+
+	if a.terratestConfig.LocalQaseReporting {
+		qase.ReportTest()
+	}
+}
+
+func TestTfpAppsTestSuite(t *testing.T) {
+	suite.Run(t, new(AppsTestSuite))
+}
@@ -0,0 +1,16 @@
+#!/bin/sh
+# Packages the tfp-nginx chart as versions 0.1.0 and 0.2.0, indexes them and serves the chart repository on port 8080.
+set -e
+
+CHART_REPO_HOST="${CHART_REPO_HOST:-localhost}"
+
+cd "$(dirname "$0")"
+
+mkdir -p charts
+helm package tfp-nginx --version 0.1.0 --destination charts
+helm package tfp-nginx --version 0.2.0 --destination charts
+helm repo index charts --url "http://${CHART_REPO_HOST}:8080"
+
+docker compose up -d
+
+echo "Chart repository available at http://${CHART_REPO_HOST}:8080"
@@ -0,0 +1,114 @@
+package fleet
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/rancher/shepherd/clients/rancher"
+	shepherdConfig "github.com/rancher/shepherd/pkg/config"
+	"github.com/rancher/shepherd/pkg/config/operations"
+	"github.com/rancher/shepherd/pkg/session"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/defaults/configs"
+	"github.com/rancher/tfp-automation/defaults/keypath"
+	"github.com/rancher/tfp-automation/framework"
+	"github.com/rancher/tfp-automation/framework/cleanup"
+	"github.com/rancher/tfp-automation/framework/set/resources/rancher2"
+	qase "github.com/rancher/tfp-automation/pipeline/qase/results"
+	"github.com/rancher/tfp-automation/tests/extensions/fleet"
+	"github.com/rancher/tfp-automation/tests/extensions/provisioning"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type FleetTestSuite struct {
+	suite.Suite
+	client           *rancher.Client
+	session          *session.Session
+	cattleConfig     map[string]any
+	rancherConfig    *rancher.Config
+	terraformConfig  *config.TerraformConfig
+	terratestConfig  *config.TerratestConfig
+	terraformOptions *terraform.Options
+}
+
+func (f *FleetTestSuite) SetupSuite() {
+	testSession := session.NewSession()
+	f.session = testSession
+
+	client, err := rancher.NewClient("", testSession)
+	require.NoError(f.T(), err)
+
+	f.client = client
+
+	f.cattleConfig = shepherdConfig.LoadConfigFromFile(os.Getenv(shepherdConfig.ConfigEnvironmentKey))
+
+	f.cattleConfig, err = config.LoadProvisioningDefaults(f.cattleConfig, "")
+	require.NoError(f.T(), err)
+
+	f.cattleConfig, err = config.LoadPackageDefaults(f.cattleConfig, "")
+	require.NoError(f.T(), err)
+
+	configMap, err := provisioning.UniquifyTerraform([]map[string]any{f.cattleConfig})
+	require.NoError(f.T(), err)
+
+	f.cattleConfig = configMap[0]
+	f.rancherConfig, f.terraformConfig, f.terratestConfig = config.LoadTFPConfigs(f.cattleConfig)
+
+	keyPath := rancher2.SetKeyPath(keypath.RancherKeyPath, "")
+	terraformOptions := framework.Setup(f.T(), f.terraformConfig, f.terratestConfig, keyPath)
+	f.terraformOptions = terraformOptions
+
+	provisioning.GetK8sVersion(f.T(), f.client, f.terratestConfig, f.terraformConfig, configs.DefaultK8sVersion, configMap)
+}
+
+func (f *FleetTestSuite) TestTfpFleet() {
+	if f.terratestConfig.Fleet == nil {
+		f.T().Skip("No fleet config specified")
+	}
+
+	nodeRolesDedicated := []config.Nodepool{config.EtcdNodePool, config.ControlPlaneNodePool, config.WorkerNodePool}
+
+	tests := []struct {
+		name      string
+		nodeRoles []config.Nodepool
+	}{
+		{"Fleet GitOps", nodeRolesDedicated},
+	}
+
+	configMap := []map[string]any{f.cattleConfig}
+	testUser, testPassword := configs.CreateTestCredentials()
+
+	for _, tt := range tests {
+		newFile, rootBody, file := rancher2.InitializeMainTF()
+		defer file.Close()
+
+		_, err := operations.ReplaceValue([]string{"terratest", "nodepools"}, tt.nodeRoles, configMap[0])
+		require.NoError(f.T(), err)
+
+		rancher, terraform, terratest := config.LoadTFPConfigs(configMap[0])
+
+		tt.name = tt.name + " Module: " + f.terraformConfig.Module
+
+		f.Run((tt.name), func() {
+			keyPath := rancher2.SetKeyPath(keypath.RancherKeyPath, "")
+			defer cleanup.Cleanup(f.T(), f.terraformOptions, keyPath)
+
+			adminClient, err := provisioning.FetchAdminClient(f.T(), f.client)
+			require.NoError(f.T(), err)
+
+			clusterIDs, _ := provisioning.Provision(f.T(), adminClient, rancher, terraform, testUser, testPassword, f.terraformOptions, configMap, newFile, rootBody, file, false, false, false, nil)
+			provisioning.VerifyClustersState(f.T(), adminClient, clusterIDs)
+			fleet.VerifyFleet(f.T(), adminClient, terraform, clusterIDs, terratest.Fleet)
+		})
+	}
+
+	if f.terratestConfig.LocalQaseReporting {
+		qase.ReportTest()
+	}
+}
+
+func TestTfpFleetTestSuite(t *testing.T) {
+	suite.Run(t, new(FleetTestSuite))
+}
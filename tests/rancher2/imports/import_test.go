@@ -0,0 +1,87 @@
+package imports
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/rancher/shepherd/clients/rancher"
+	shepherdConfig "github.com/rancher/shepherd/pkg/config"
+	"github.com/rancher/shepherd/pkg/session"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/defaults/keypath"
+	"github.com/rancher/tfp-automation/framework"
+	"github.com/rancher/tfp-automation/framework/cleanup"
+	"github.com/rancher/tfp-automation/framework/set/resources/rancher2"
+	qase "github.com/rancher/tfp-automation/pipeline/qase/results"
+	"github.com/rancher/tfp-automation/tests/extensions/imports"
+	"github.com/rancher/tfp-automation/tests/extensions/provisioning"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ImportTestSuite struct {
+	suite.Suite
+	client           *rancher.Client
+	session          *session.Session
+	cattleConfig     map[string]any
+	rancherConfig    *rancher.Config
+	terraformConfig  *config.TerraformConfig
+	terratestConfig  *config.TerratestConfig
+	terraformOptions *terraform.Options
+}
+
+func (i *ImportTestSuite) TearDownSuite() {
+	i.session.Cleanup()
+}
+
+func (i *ImportTestSuite) SetupSuite() {
+	testSession := session.NewSession()
+	i.session = testSession
+
+	client, err := rancher.NewClient("", testSession)
+	require.NoError(i.T(), err)
+
+	i.client = client
+
+	i.cattleConfig = shepherdConfig.LoadConfigFromFile(os.Getenv(shepherdConfig.ConfigEnvironmentKey))
+	configMap, err := provisioning.UniquifyTerraform([]map[string]any{i.cattleConfig})
+	require.NoError(i.T(), err)
+
+	i.cattleConfig = configMap[0]
+	i.rancherConfig, i.terraformConfig, i.terratestConfig = config.LoadTFPConfigs(i.cattleConfig)
+
+	keyPath := rancher2.SetKeyPath(keypath.RancherKeyPath, "")
+	terraformOptions := framework.Setup(i.T(), i.terraformConfig, i.terratestConfig, keyPath)
+	i.terraformOptions = terraformOptions
+}
+
+func (i *ImportTestSuite) TestTfpImportRoundTrip() {
+	tests := []struct {
+		name string
+	}{
+		{"Import Round Trip"},
+	}
+
+	configMap := []map[string]any{i.cattleConfig}
+
+	for _, tt := range tests {
+		newFile, rootBody, file := rancher2.InitializeMainTF()
+		defer file.Close()
+
+		i.Run((tt.name), func() {
+			keyPath := rancher2.SetKeyPath(keypath.RancherKeyPath, "")
+			defer cleanup.Cleanup(i.T(), i.terraformOptions, keyPath)
+
+			imports.ImportRoundTrip(i.T(), i.client, i.terraformConfig, i.terraformOptions, configMap, newFile, rootBody, file)
+		})
+	}
+
+	if i.terratestConfig.LocalQaseReporting {
+		qase.ReportTest()
+	}
+}
+
+func TestTfpImportTestSuite(t *testing.T) {
+	suite.Run(t, new(ImportTestSuite))
+}
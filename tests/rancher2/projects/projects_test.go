@@ -0,0 +1,110 @@
+package projects
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/rancher/shepherd/clients/rancher"
+	shepherdConfig "github.com/rancher/shepherd/pkg/config"
+	"github.com/rancher/shepherd/pkg/config/operations"
+	"github.com/rancher/shepherd/pkg/session"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/defaults/configs"
+	"github.com/rancher/tfp-automation/defaults/keypath"
+	"github.com/rancher/tfp-automation/framework"
+	"github.com/rancher/tfp-automation/framework/cleanup"
+	"github.com/rancher/tfp-automation/framework/set/resources/rancher2"
+	qase "github.com/rancher/tfp-automation/pipeline/qase/results"
+	"github.com/rancher/tfp-automation/tests/extensions/projects"
+	"github.com/rancher/tfp-automation/tests/extensions/provisioning"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProjectsTestSuite struct {
+	suite.Suite
+	client           *rancher.Client
+	session          *session.Session
+	cattleConfig     map[string]any
+	rancherConfig    *rancher.Config
+	terraformConfig  *config.TerraformConfig
+	terratestConfig  *config.TerratestConfig
+	terraformOptions *terraform.Options
+}
+
+func (p *ProjectsTestSuite) SetupSuite() {
+	testSession := session.NewSession()
+	p.session = testSession
+
+	client, err := rancher.NewClient("", testSession)
+	require.NoError(p.T(), err)
+
+	p.client = client
+
+	p.cattleConfig = shepherdConfig.LoadConfigFromFile(os.Getenv(shepherdConfig.ConfigEnvironmentKey))
+
+	p.cattleConfig, err = config.LoadProvisioningDefaults(p.cattleConfig, "")
+	require.NoError(p.T(), err)
+
+	p.cattleConfig, err = config.LoadPackageDefaults(p.cattleConfig, "")
+	require.NoError(p.T(), err)
+
+	configMap, err := provisioning.UniquifyTerraform([]map[string]any{p.cattleConfig})
+	require.NoError(p.T(), err)
+
+	p.cattleConfig = configMap[0]
+	p.rancherConfig, p.terraformConfig, p.terratestConfig = config.LoadTFPConfigs(p.cattleConfig)
+
+	keyPath := rancher2.SetKeyPath(keypath.RancherKeyPath, "")
+	terraformOptions := framework.Setup(p.T(), p.terraformConfig, p.terratestConfig, keyPath)
+	p.terraformOptions = terraformOptions
+
+	provisioning.GetK8sVersion(p.T(), p.client, p.terratestConfig, p.terraformConfig, configs.DefaultK8sVersion, configMap)
+}
+
+func (p *ProjectsTestSuite) TestTfpProjects() {
+	nodeRolesDedicated := []config.Nodepool{config.EtcdNodePool, config.ControlPlaneNodePool, config.WorkerNodePool}
+
+	tests := []struct {
+		name      string
+		nodeRoles []config.Nodepool
+	}{
+		{"Project Quotas and Namespaces", nodeRolesDedicated},
+	}
+
+	configMap := []map[string]any{p.cattleConfig}
+	testUser, testPassword := configs.CreateTestCredentials()
+
+	for _, tt := range tests {
+		newFile, rootBody, file := rancher2.InitializeMainTF()
+		defer file.Close()
+
+		_, err := operations.ReplaceValue([]string{"terratest", "nodepools"}, tt.nodeRoles, configMap[0])
+		require.NoError(p.T(), err)
+
+		rancher, terraform, terratest := config.LoadTFPConfigs(configMap[0])
+
+		tt.name = tt.name + " Module: " + p.terraformConfig.Module
+
+		p.Run((tt.name), func() {
+			keyPath := rancher2.SetKeyPath(keypath.RancherKeyPath, "")
+			defer cleanup.Cleanup(p.T(), p.terraformOptions, keyPath)
+
+			adminClient, err := provisioning.FetchAdminClient(p.T(), p.client)
+			require.NoError(p.T(), err)
+
+			clusterIDs, _ := provisioning.Provision(p.T(), adminClient, rancher, terraform, testUser, testPassword, p.terraformOptions, configMap, newFile, rootBody, file, false, false, false, nil)
+			provisioning.VerifyClustersState(p.T(), adminClient, clusterIDs)
+			projects.VerifyProjects(p.T(), adminClient, clusterIDs[0], terratest.Projects)
+		})
+	}
+
+	if p.terratestConfig.LocalQaseReporting {
+		qase.ReportTest()
+	}
+}
+
+func TestTfpProjectsTestSuite(t *testing.T) {
+	suite.Run(t, new(ProjectsTestSuite))
+}
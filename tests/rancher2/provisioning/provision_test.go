@@ -2,14 +2,18 @@ package provisioning
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/gruntwork-io/terratest/modules/terraform"
+	provisioningv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
 	"github.com/rancher/shepherd/clients/rancher"
 	shepherdConfig "github.com/rancher/shepherd/pkg/config"
 	"github.com/rancher/shepherd/pkg/config/operations"
 	"github.com/rancher/shepherd/pkg/session"
 	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/defaults/clustertypes"
 	"github.com/rancher/tfp-automation/defaults/configs"
 	"github.com/rancher/tfp-automation/defaults/keypath"
 	"github.com/rancher/tfp-automation/framework"
@@ -19,6 +23,9 @@ import (
 	"github.com/rancher/tfp-automation/tests/extensions/provisioning"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type ProvisionTestSuite struct {
@@ -100,6 +107,165 @@ func (p *ProvisionTestSuite) TestTfpProvision() {
 	}
 }
 
+func (p *ProvisionTestSuite) TestTfpProvisionAgentCustomization() {
+	nodeRolesDedicated := []config.Nodepool{config.EtcdNodePool, config.ControlPlaneNodePool, config.WorkerNodePool}
+
+	agentCustomization := &config.AgentCustomization{
+		AgentEnvVars: []rkev1.EnvVar{
+			{Name: "TFP_AGENT_ENV", Value: "tfp-automation"},
+		},
+		ClusterAgent: &provisioningv1.AgentDeploymentCustomization{
+			AppendTolerations: []corev1.Toleration{
+				{Key: "tfp-cluster-agent", Operator: corev1.TolerationOpEqual, Value: "true", Effect: corev1.TaintEffectNoSchedule},
+			},
+			OverrideResourceRequirements: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("500m"),
+					corev1.ResourceMemory: resource.MustParse("512Mi"),
+				},
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("250m"),
+					corev1.ResourceMemory: resource.MustParse("256Mi"),
+				},
+			},
+		},
+		FleetAgent: &provisioningv1.AgentDeploymentCustomization{
+			AppendTolerations: []corev1.Toleration{
+				{Key: "tfp-fleet-agent", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+			},
+			OverrideAffinity: &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{
+						{
+							Weight: 1,
+							Preference: corev1.NodeSelectorTerm{
+								MatchExpressions: []corev1.NodeSelectorRequirement{
+									{Key: "fleet.cattle.io/agent", Operator: corev1.NodeSelectorOpIn, Values: []string{"true"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name               string
+		nodeRoles          []config.Nodepool
+		agentCustomization *config.AgentCustomization
+	}{
+		{"Agent Customization " + config.StandardClientName.String(), nodeRolesDedicated, agentCustomization},
+	}
+
+	// The agent customization is set on a copy of the config, so that it does not leak into the other tests.
+	cattleConfig, err := operations.DeepCopyMap(p.cattleConfig)
+	require.NoError(p.T(), err)
+
+	configMap := []map[string]any{cattleConfig}
+	testUser, testPassword := configs.CreateTestCredentials()
+
+	for _, tt := range tests {
+		newFile, rootBody, file := rancher2.InitializeMainTF()
+		defer file.Close()
+
+		operations.ReplaceValue([]string{"terratest", "nodepools"}, tt.nodeRoles, configMap[0])
+		operations.ReplaceValue([]string{"terraform", "agentCustomization"}, tt.agentCustomization, configMap[0])
+
+		provisioning.GetK8sVersion(p.T(), p.client, p.terratestConfig, p.terraformConfig, configs.DefaultK8sVersion, configMap)
+
+		rancher, terraform, terratest := config.LoadTFPConfigs(configMap[0])
+
+		tt.name = tt.name + " Module: " + p.terraformConfig.Module + " Kubernetes version: " + terratest.KubernetesVersion
+
+		p.Run((tt.name), func() {
+			keyPath := rancher2.SetKeyPath(keypath.RancherKeyPath, "")
+			defer cleanup.Cleanup(p.T(), p.terraformOptions, keyPath)
+
+			adminClient, err := provisioning.FetchAdminClient(p.T(), p.client)
+			require.NoError(p.T(), err)
+
+			clusterIDs, _ := provisioning.Provision(p.T(), p.client, rancher, terraform, testUser, testPassword, p.terraformOptions, configMap, newFile, rootBody, file, false, false, false, nil)
+			provisioning.VerifyClustersState(p.T(), adminClient, clusterIDs)
+			provisioning.VerifyAgentCustomization(p.T(), adminClient, clusterIDs, terraform)
+		})
+	}
+
+	if p.terratestConfig.LocalQaseReporting {
+		qase.ReportTest()
+	}
+}
+
+func (p *ProvisionTestSuite) TestTfpProvisionRKEConfig() {
+	nodeRolesDedicated := []config.Nodepool{config.EtcdNodePool, config.ControlPlaneNodePool, config.WorkerNodePool}
+
+	rkeConfig := &config.RKEConfig{
+		AdditionalManifest: "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: tfp-additional-manifest\n",
+		ETCDArgs:           []string{"quota-backend-bytes=4294967296"},
+		LocalAuthEndpoint:  &rkev1.LocalClusterAuthEndpoint{Enabled: true},
+		MachineGlobalConfig: map[string]any{
+			"kube-apiserver-arg": []string{"audit-log-maxage=30"},
+		},
+		MachineSelectorConfig: []rkev1.RKESystemConfig{
+			{
+				MachineLabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"rke.cattle.io/worker-role": "true"},
+				},
+				Config: rkev1.GenericMap{Data: map[string]any{"kubelet-arg": []string{"max-pods=150"}}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		nodeRoles []config.Nodepool
+		rkeConfig *config.RKEConfig
+	}{
+		{"RKE Config " + config.StandardClientName.String(), nodeRolesDedicated, rkeConfig},
+	}
+
+	if strings.Contains(p.terraformConfig.Module, clustertypes.RKE1) {
+		p.T().Skip("The rkeConfig section is only supported for RKE2/K3s clusters")
+	}
+
+	// The rkeConfig section is set on a copy of the config, so that it does not leak into the other tests.
+	cattleConfig, err := operations.DeepCopyMap(p.cattleConfig)
+	require.NoError(p.T(), err)
+
+	configMap := []map[string]any{cattleConfig}
+	testUser, testPassword := configs.CreateTestCredentials()
+
+	for _, tt := range tests {
+		newFile, rootBody, file := rancher2.InitializeMainTF()
+		defer file.Close()
+
+		operations.ReplaceValue([]string{"terratest", "nodepools"}, tt.nodeRoles, configMap[0])
+		operations.ReplaceValue([]string{"terraform", "rkeConfig"}, tt.rkeConfig, configMap[0])
+
+		provisioning.GetK8sVersion(p.T(), p.client, p.terratestConfig, p.terraformConfig, configs.DefaultK8sVersion, configMap)
+
+		rancher, terraform, terratest := config.LoadTFPConfigs(configMap[0])
+
+		tt.name = tt.name + " Module: " + p.terraformConfig.Module + " Kubernetes version: " + terratest.KubernetesVersion
+
+		p.Run((tt.name), func() {
+			keyPath := rancher2.SetKeyPath(keypath.RancherKeyPath, "")
+			defer cleanup.Cleanup(p.T(), p.terraformOptions, keyPath)
+
+			adminClient, err := provisioning.FetchAdminClient(p.T(), p.client)
+			require.NoError(p.T(), err)
+
+			clusterIDs, _ := provisioning.Provision(p.T(), p.client, rancher, terraform, testUser, testPassword, p.terraformOptions, configMap, newFile, rootBody, file, false, false, false, nil)
+			provisioning.VerifyClustersState(p.T(), adminClient, clusterIDs)
+			provisioning.VerifyRKEConfig(p.T(), adminClient, clusterIDs, terraform)
+		})
+	}
+
+	if p.terratestConfig.LocalQaseReporting {
+		qase.ReportTest()
+	}
+}
+
 func (p *ProvisionTestSuite) TestTfpProvisionDynamicInput() {
 	tests := []struct {
 		name string
@@ -129,6 +295,8 @@ func (p *ProvisionTestSuite) TestTfpProvisionDynamicInput() {
 			clusterIDs, _ := provisioning.Provision(p.T(), p.client, p.rancherConfig, p.terraformConfig, testUser, testPassword, p.terraformOptions, configMap, newFile, rootBody, file, false, false, false, nil)
 			provisioning.VerifyClustersState(p.T(), adminClient, clusterIDs)
 			provisioning.VerifyWorkloads(p.T(), adminClient, clusterIDs)
+			provisioning.VerifyAgentCustomization(p.T(), adminClient, clusterIDs, p.terraformConfig)
+			provisioning.VerifyRKEConfig(p.T(), adminClient, clusterIDs, p.terraformConfig)
 		})
 	}
 
@@ -82,6 +82,7 @@ func (r *AuthConfigTestSuite) TestTfpAuthConfig() {
 			defer cleanup.Cleanup(r.T(), r.terraformOptions, keyPath)
 
 			rbac.AuthConfig(r.T(), terraform, r.terraformOptions, testUser, testPassword, configMap, newFile, rootBody, file)
+			rbac.VerifyAuthLogin(r.T(), r.client, r.rancherConfig, terraform)
 		})
 	}
 
@@ -117,6 +118,36 @@ func (r *AuthConfigTestSuite) TestTfpAuthConfigDynamicInput() {
 			defer cleanup.Cleanup(r.T(), r.terraformOptions, keyPath)
 
 			rbac.AuthConfig(r.T(), r.terraformConfig, r.terraformOptions, testUser, testPassword, configMap, newFile, rootBody, file)
+			rbac.VerifyAuthLogin(r.T(), r.client, r.rancherConfig, r.terraformConfig)
+		})
+	}
+
+	if r.terratestConfig.LocalQaseReporting {
+		qase.ReportTest()
+	}
+}
+
+func (r *AuthConfigTestSuite) TestTfpAuthLifecycleDynamicInput() {
+	if r.terraformConfig.AuthProvider == "" {
+		r.T().Skip("No auth provider specified")
+	}
+
+	tests := []struct {
+		name string
+	}{
+		{r.terraformConfig.AuthProvider + " Lifecycle"},
+	}
+
+	configMap := []map[string]any{r.cattleConfig}
+
+	for _, tt := range tests {
+		testUser, testPassword := configs.CreateTestCredentials()
+
+		r.Run((tt.name), func() {
+			keyPath := rancher2.SetKeyPath(keypath.RancherKeyPath, "")
+			defer cleanup.Cleanup(r.T(), r.terraformOptions, keyPath)
+
+			rbac.AuthLifecycle(r.T(), r.client, r.rancherConfig, r.terraformOptions, testUser, testPassword, configMap)
 		})
 	}
 
@@ -67,6 +67,8 @@ func (r *RBACTestSuite) TestTfpRBAC() {
 	}{
 		{"Cluster Owner", config.ClusterOwner},
 		{"Project Owner", config.ProjectOwner},
+		{"Cluster Member", config.ClusterMember},
+		{"Project Member", config.ProjectMember},
 	}
 
 	configMap := []map[string]any{r.cattleConfig}
@@ -95,6 +97,55 @@ func (r *RBACTestSuite) TestTfpRBAC() {
 			clusterIDs, _ := provisioning.Provision(r.T(), adminClient, rancher, terraform, testUser, testPassword, r.terraformOptions, configMap, newFile, rootBody, file, false, false, false, nil)
 			provisioning.VerifyClustersState(r.T(), adminClient, clusterIDs)
 			rb.RBAC(r.T(), r.client, r.rancherConfig, terraform, terratest, testUser, testPassword, r.terraformOptions, configMap, tt.rbacRole, newFile, rootBody, file)
+			rb.VerifyPermissions(r.T(), adminClient, clusterIDs[0], testUser, testPassword, tt.rbacRole)
+		})
+	}
+
+	if r.terratestConfig.LocalQaseReporting {
+		qase.ReportTest()
+	}
+}
+
+func (r *RBACTestSuite) TestTfpRBACDynamicInput() {
+	if r.terratestConfig.RBAC == nil {
+		r.T().Skip("No RBAC bindings specified")
+	}
+
+	nodeRolesDedicated := []config.Nodepool{config.EtcdNodePool, config.ControlPlaneNodePool, config.WorkerNodePool}
+
+	tests := []struct {
+		name string
+	}{
+		{"RBAC Bindings"},
+	}
+
+	configMap := []map[string]any{r.cattleConfig}
+	testUser, testPassword := configs.CreateTestCredentials()
+
+	for _, tt := range tests {
+		newFile, rootBody, file := rancher2.InitializeMainTF()
+		defer file.Close()
+
+		_, err := operations.ReplaceValue([]string{"terratest", "nodepools"}, nodeRolesDedicated, configMap[0])
+		require.NoError(r.T(), err)
+
+		provisioning.GetK8sVersion(r.T(), r.client, r.terratestConfig, r.terraformConfig, configs.DefaultK8sVersion, configMap)
+
+		rancher, terraform, terratest := config.LoadTFPConfigs(configMap[0])
+
+		tt.name = tt.name + " Module: " + r.terraformConfig.Module
+
+		r.Run((tt.name), func() {
+			keyPath := rancher2.SetKeyPath(keypath.RancherKeyPath, "")
+			defer cleanup.Cleanup(r.T(), r.terraformOptions, keyPath)
+
+			adminClient, err := provisioning.FetchAdminClient(r.T(), r.client)
+			require.NoError(r.T(), err)
+
+			clusterIDs, _ := provisioning.Provision(r.T(), adminClient, rancher, terraform, testUser, testPassword, r.terraformOptions, configMap, newFile, rootBody, file, false, false, false, nil)
+			provisioning.VerifyClustersState(r.T(), adminClient, clusterIDs)
+			rb.RBAC(r.T(), r.client, r.rancherConfig, terraform, terratest, testUser, testPassword, r.terraformOptions, configMap, config.DeclaredBindings, newFile, rootBody, file)
+			rb.VerifyBindings(r.T(), adminClient, clusterIDs[0], testUser, terratest.RBAC)
 		})
 	}
 
@@ -0,0 +1,98 @@
+package settings
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/rancher/shepherd/clients/rancher"
+	shepherdConfig "github.com/rancher/shepherd/pkg/config"
+	"github.com/rancher/shepherd/pkg/session"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/defaults/keypath"
+	"github.com/rancher/tfp-automation/framework"
+	"github.com/rancher/tfp-automation/framework/cleanup"
+	"github.com/rancher/tfp-automation/framework/set/resources/rancher2"
+	qase "github.com/rancher/tfp-automation/pipeline/qase/results"
+	"github.com/rancher/tfp-automation/tests/extensions/provisioning"
+	"github.com/rancher/tfp-automation/tests/extensions/settings"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SettingsTestSuite struct {
+	suite.Suite
+	client           *rancher.Client
+	session          *session.Session
+	cattleConfig     map[string]any
+	rancherConfig    *rancher.Config
+	terraformConfig  *config.TerraformConfig
+	terratestConfig  *config.TerratestConfig
+	terraformOptions *terraform.Options
+}
+
+func (s *SettingsTestSuite) SetupSuite() {
+	testSession := session.NewSession()
+	s.session = testSession
+
+	client, err := rancher.NewClient("", testSession)
+	require.NoError(s.T(), err)
+
+	s.client = client
+
+	s.cattleConfig = shepherdConfig.LoadConfigFromFile(os.Getenv(shepherdConfig.ConfigEnvironmentKey))
+
+	s.cattleConfig, err = config.LoadPackageDefaults(s.cattleConfig, "")
+	require.NoError(s.T(), err)
+
+	configMap, err := provisioning.UniquifyTerraform([]map[string]any{s.cattleConfig})
+	require.NoError(s.T(), err)
+
+	s.cattleConfig = configMap[0]
+	s.rancherConfig, s.terraformConfig, s.terratestConfig = config.LoadTFPConfigs(s.cattleConfig)
+
+	keyPath := rancher2.SetKeyPath(keypath.RancherKeyPath, "")
+	terraformOptions := framework.Setup(s.T(), s.terraformConfig, s.terratestConfig, keyPath)
+	s.terraformOptions = terraformOptions
+}
+
+func (s *SettingsTestSuite) TestTfpSettings() {
+	if s.terratestConfig.GlobalSettings == nil {
+		s.T().Skip("No global settings specified")
+	}
+
+	tests := []struct {
+		name string
+	}{
+		{"Settings, Features and Tokens"},
+	}
+
+	configMap := []map[string]any{s.cattleConfig}
+
+	for _, tt := range tests {
+		newFile, rootBody, file := rancher2.InitializeMainTF()
+		defer file.Close()
+
+		s.Run((tt.name), func() {
+			adminClient, err := provisioning.FetchAdminClient(s.T(), s.client)
+			require.NoError(s.T(), err)
+
+			originalValues := settings.GetOriginalValues(s.T(), adminClient, s.terratestConfig.GlobalSettings)
+			defer settings.RestoreOriginalValues(adminClient, originalValues)
+
+			keyPath := rancher2.SetKeyPath(keypath.RancherKeyPath, "")
+			defer cleanup.Cleanup(s.T(), s.terraformOptions, keyPath)
+
+			settings.Settings(s.T(), s.terraformOptions, configMap, newFile, rootBody, file)
+			settings.VerifySettings(s.T(), adminClient, s.rancherConfig, s.terraformOptions, s.terratestConfig.GlobalSettings)
+		})
+	}
+
+	if s.terratestConfig.LocalQaseReporting {
+		qase.ReportTest()
+	}
+}
+
+func TestTfpSettingsTestSuite(t *testing.T) {
+	suite.Run(t, new(SettingsTestSuite))
+}
@@ -0,0 +1,187 @@
+package snapshot
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/rancher/shepherd/clients/rancher"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	"github.com/rancher/shepherd/extensions/clusters"
+	"github.com/rancher/shepherd/extensions/workloads"
+	"github.com/rancher/shepherd/pkg/config/operations"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/defaults/stevetypes"
+	"github.com/rancher/tfp-automation/tests/extensions/nodes"
+	"github.com/rancher/tfp-automation/tests/extensions/provisioning"
+	"github.com/rancher/tfp-automation/tests/extensions/snapshot"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	controlPlaneRole = "control-plane"
+	etcdRole         = "etcd"
+)
+
+// disasterRecovery takes a snapshot of the cluster and terminates nodes with the given role outside of Terraform: a
+// majority of the etcd nodes, or all of the control plane nodes. The lost nodes are then replaced by scaling up their
+// machine pool and the snapshot is restored in the same Terraform apply. Once the cluster is recovered, the machine pool
+// is scaled back down, removing the lost machines, and the workloads are verified against the snapshot.
+func disasterRecovery(t *testing.T, client *rancher.Client, rancherConfig *rancher.Config, terraformConfig *config.TerraformConfig,
+	terratestConfig *config.TerratestConfig, testUser, testPassword string, terraformOptions *terraform.Options, configMap []map[string]any,
+	newFile *hclwrite.File, rootBody *hclwrite.Body, file *os.File, lostRole string) {
+	initialWorkloadName := namegen.AppendRandomString(initialWorkload)
+
+	clusterID, err := clusters.GetClusterIDByName(client, terraformConfig.ResourcePrefix)
+	require.NoError(t, err)
+
+	containerTemplate := workloads.NewContainer(containerName, containerImage, corev1.PullAlways, []corev1.VolumeMount{}, []corev1.EnvFromSource{}, nil, nil, nil)
+	podTemplate := workloads.NewPodTemplate([]corev1.Container{containerTemplate}, []corev1.Volume{}, []corev1.LocalObjectReference{}, nil, nil)
+
+	deploymentResp, serviceResp := createWorkloads(t, client, clusterID, podTemplate, initialWorkloadName, isCattleLabeled, DeploymentSteveType)
+
+	useS3 := terraformConfig.S3Credentials != nil && terraformConfig.ETCD != nil && terraformConfig.ETCD.S3 != nil
+
+	var existingS3SnapshotNames []string
+	if useS3 {
+		existingS3Snapshots, _, err := snapshot.GetClusterSnapshots(client, terraformConfig.ResourcePrefix)
+		require.NoError(t, err)
+
+		for _, existingS3Snapshot := range existingS3Snapshots {
+			existingS3SnapshotNames = append(existingS3SnapshotNames, existingS3Snapshot.ObjectMeta.Name)
+		}
+	}
+
+	snapshotName, postDeploymentResp, postServiceResp, err := snapshotV2Prov(t, client, terraformConfig, podTemplate, testUser, testPassword, clusterID, terraformOptions, configMap, newFile, rootBody, file)
+	require.NoError(t, err)
+
+	// A local snapshot is only available on the node that took it, so that node must survive the loss of the etcd majority.
+	// With S3 configured, the snapshot is restored from the bucket instead and any node can be lost.
+	var snapshotNodeName string
+	if useS3 {
+		s3Snapshot, err := snapshot.WaitForNewS3Snapshot(client, terraformConfig.ResourcePrefix, existingS3SnapshotNames)
+		require.NoError(t, err)
+
+		snapshotName = s3Snapshot.Name
+	} else {
+		snapshotObject, err := client.Steve.SteveType(stevetypes.EtcdSnapshot).ByID(namespace + "/" + snapshotName)
+		require.NoError(t, err)
+
+		etcdSnapshot := &rkev1.ETCDSnapshot{}
+		err = steveV1.ConvertToK8sType(snapshotObject.JSONResp, etcdSnapshot)
+		require.NoError(t, err)
+
+		snapshotNodeName = etcdSnapshot.SnapshotFile.NodeName
+	}
+
+	lostNodes := getLostNodes(t, client, clusterID, lostRole, snapshotNodeName)
+
+	var lostNodeNames []string
+	for _, lostNode := range lostNodes {
+		lostNodeNames = append(lostNodeNames, lostNode.Name)
+	}
+
+	logrus.Infof("Terminating %d %s node(s) outside of Terraform...", len(lostNodes), lostRole)
+	err = nodes.TerminateNodes(client, terraformConfig.ResourcePrefix, lostNodes)
+	require.NoError(t, err)
+
+	err = nodes.WaitForNodesUnavailable(client, clusterID, lostNodeNames)
+	require.NoError(t, err)
+
+	err = nodes.MarkMachinesForDeletion(client, lostNodes)
+	require.NoError(t, err)
+
+	scaledUpNodepools, scaledDownNodepools := replacementNodepools(terratestConfig.Nodepools, lostRole, int64(len(lostNodes)))
+
+	_, err = operations.ReplaceValue([]string{"terratest", "nodepools"}, scaledUpNodepools, configMap[0])
+	require.NoError(t, err)
+
+	logrus.Infof("Replacing lost nodes and restoring snapshot %s...", snapshotName)
+	restoreV2Prov(t, client, terraformConfig, snapshotName, testUser, testPassword, clusterID, terraformOptions, configMap, newFile, rootBody, file)
+
+	_, err = operations.ReplaceValue([]string{"terratest", "nodepools"}, scaledDownNodepools, configMap[0])
+	require.NoError(t, err)
+
+	logrus.Infof("Removing lost nodes...")
+	provisioning.Scale(t, client, rancherConfig, terraformConfig, terratestConfig, testUser, testPassword, terraformOptions, configMap, newFile, rootBody, file)
+
+	err = clusters.WaitClusterToBeUpgraded(client, clusterID)
+	require.NoError(t, err)
+
+	var nodeCount int64
+	for _, pool := range scaledDownNodepools {
+		nodeCount += pool.Quantity
+	}
+
+	provisioning.VerifyNodeCount(t, client, terraformConfig.ResourcePrefix, terraformConfig, nodeCount)
+
+	steveclient, err := client.Steve.ProxyDownstream(clusterID)
+	require.NoError(t, err)
+
+	_, err = steveclient.SteveType(DeploymentSteveType).ByID(deploymentResp.ID)
+	require.NoError(t, err)
+
+	_, err = steveclient.SteveType(DeploymentSteveType).ByID(postDeploymentResp.ID)
+	require.Error(t, err)
+
+	_, err = steveclient.SteveType(serviceType).ByID(postServiceResp.ID)
+	require.Error(t, err)
+
+	logrus.Infof("Deleting created workloads...")
+	err = steveclient.SteveType(stevetypes.Deployment).Delete(deploymentResp)
+	require.NoError(t, err)
+
+	err = steveclient.SteveType(stevetypes.Service).Delete(serviceResp)
+	require.NoError(t, err)
+}
+
+// getLostNodes returns the nodes to terminate for the given role: a majority of the etcd nodes, skipping the node that
+// holds the local snapshot, or all of the control plane nodes.
+func getLostNodes(t *testing.T, client *rancher.Client, clusterID, lostRole, snapshotNodeName string) []steveV1.SteveAPIObject {
+	roleNodes, err := nodes.GetNodesByRole(client, clusterID, lostRole)
+	require.NoError(t, err)
+	require.NotEmpty(t, roleNodes)
+
+	if lostRole == controlPlaneRole {
+		return roleNodes
+	}
+
+	majority := len(roleNodes)/2 + 1
+
+	var lostNodes []steveV1.SteveAPIObject
+	for _, node := range roleNodes {
+		if node.Name == snapshotNodeName {
+			continue
+		}
+
+		lostNodes = append(lostNodes, node)
+		if len(lostNodes) == majority {
+			break
+		}
+	}
+
+	require.Len(t, lostNodes, majority, "not enough etcd nodes to lose a majority while keeping the snapshot node")
+
+	return lostNodes
+}
+
+// replacementNodepools returns the node pools with the pool of the lost role scaled up by the number of lost nodes, and
+// the original node pools to scale back down to once the cluster is recovered.
+func replacementNodepools(nodepools []config.Nodepool, lostRole string, lostCount int64) ([]config.Nodepool, []config.Nodepool) {
+	scaledUpNodepools := make([]config.Nodepool, len(nodepools))
+	copy(scaledUpNodepools, nodepools)
+
+	for i, pool := range scaledUpNodepools {
+		if (lostRole == etcdRole && pool.Etcd) || (lostRole == controlPlaneRole && pool.Controlplane) {
+			scaledUpNodepools[i].Quantity += lostCount
+			break
+		}
+	}
+
+	return scaledUpNodepools, nodepools
+}
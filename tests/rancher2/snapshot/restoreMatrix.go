@@ -0,0 +1,250 @@
+package snapshot
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/shepherd/clients/rancher"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	"github.com/rancher/shepherd/extensions/clusters"
+	"github.com/rancher/shepherd/extensions/workloads"
+	"github.com/rancher/shepherd/pkg/config/operations"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/defaults/clustertypes"
+	"github.com/rancher/tfp-automation/defaults/stevetypes"
+	framework "github.com/rancher/tfp-automation/framework/set"
+	"github.com/rancher/tfp-automation/framework/set/provisioning/nodedriver/rke1"
+	"github.com/rancher/tfp-automation/tests/extensions/provisioning"
+	"github.com/rancher/tfp-automation/tests/extensions/snapshot"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	restoreAll               = "all"
+	restoreKubernetesVersion = "kubernetesVersion"
+)
+
+// clusterState is the Kubernetes version and the etcd config value of a cluster that a restore is verified against.
+type clusterState struct {
+	kubernetesVersion string
+	etcdConfig        int64
+}
+
+// snapshotRestoreMatrix takes a snapshot of the cluster, upgrades its Kubernetes version and etcd config and restores the
+// snapshot with the given restore mode. The Kubernetes version, the etcd config and the workloads are verified against the
+// state the restore mode is expected to produce, then the config is realigned with the restored cluster.
+func snapshotRestoreMatrix(t *testing.T, client *rancher.Client, rancherConfig *rancher.Config, testUser, testPassword string,
+	terraformOptions *terraform.Options, configMap []map[string]any, newFile *hclwrite.File, rootBody *hclwrite.Body, file *os.File,
+	restoreMode string) {
+	_, terraformConfig, terratestConfig := config.LoadTFPConfigs(configMap[0])
+	isRKE1 := strings.Contains(terraformConfig.Module, clustertypes.RKE1)
+
+	clusterID, err := clusters.GetClusterIDByName(client, terraformConfig.ResourcePrefix)
+	require.NoError(t, err)
+
+	steveclient, err := client.Steve.ProxyDownstream(clusterID)
+	require.NoError(t, err)
+
+	originalState := clusterState{
+		kubernetesVersion: terratestConfig.KubernetesVersion,
+		etcdConfig:        getConfiguredEtcdValue(t, terraformConfig, isRKE1),
+	}
+
+	containerTemplate := workloads.NewContainer(containerName, containerImage, corev1.PullAlways, []corev1.VolumeMount{}, []corev1.EnvFromSource{}, nil, nil, nil)
+	podTemplate := workloads.NewPodTemplate([]corev1.Container{containerTemplate}, []corev1.Volume{}, []corev1.LocalObjectReference{}, nil, nil)
+
+	initialWorkloadName := namegen.AppendRandomString(initialWorkload)
+	deploymentResp, serviceResp := createWorkloads(t, client, clusterID, podTemplate, initialWorkloadName, isCattleLabeled, DeploymentSteveType)
+
+	var snapshotName string
+	var postDeploymentResp, postServiceResp *steveV1.SteveAPIObject
+
+	if isRKE1 {
+		snapshotName, postDeploymentResp, postServiceResp = snapshotRKE1Prov(t, client, terraformConfig, podTemplate, testUser, testPassword, clusterID,
+			terraformOptions, configMap, newFile, rootBody, file)
+	} else {
+		snapshotName, postDeploymentResp, postServiceResp, err = snapshotV2Prov(t, client, terraformConfig, podTemplate, testUser, testPassword, clusterID,
+			terraformOptions, configMap, newFile, rootBody, file)
+		require.NoError(t, err)
+	}
+
+	setEtcdValue(t, configMap, originalState.etcdConfig+1, isRKE1)
+
+	_, terraformConfig, terratestConfig = config.LoadTFPConfigs(configMap[0])
+	provisioning.KubernetesUpgrade(t, client, rancherConfig, terraformConfig, terratestConfig, testUser, testPassword, terraformOptions, configMap,
+		newFile, rootBody, file, false)
+
+	_, _, upgradedTerratestConfig := config.LoadTFPConfigs(configMap[0])
+	upgradedState := clusterState{
+		kubernetesVersion: upgradedTerratestConfig.KubernetesVersion,
+		etcdConfig:        originalState.etcdConfig + 1,
+	}
+
+	require.NotEqual(t, originalState.kubernetesVersion, upgradedState.kubernetesVersion, "Kubernetes version was not upgraded")
+
+	logrus.Infof("Restoring snapshot %s with restore mode %s...", snapshotName, restoreMode)
+
+	if isRKE1 {
+		err = snapshot.RestoreRKE1Backup(client, terraformConfig.ResourcePrefix, snapshotName, restoreMode)
+		require.NoError(t, err)
+	} else {
+		_, err = operations.ReplaceValue([]string{"terratest", "snapshotInput", "snapshotRestore"}, restoreMode, configMap[0])
+		require.NoError(t, err)
+
+		restoreV2Prov(t, client, terraformConfig, snapshotName, testUser, testPassword, clusterID, terraformOptions, configMap, newFile, rootBody, file)
+	}
+
+	expectedState := upgradedState
+	switch restoreMode {
+	case restoreKubernetesVersion:
+		expectedState.kubernetesVersion = originalState.kubernetesVersion
+	case restoreAll:
+		expectedState = originalState
+	}
+
+	verifyClusterState(t, client, terraformConfig, clusterID, expectedState, isRKE1)
+
+	_, err = steveclient.SteveType(DeploymentSteveType).ByID(deploymentResp.ID)
+	require.NoError(t, err)
+
+	_, err = steveclient.SteveType(DeploymentSteveType).ByID(postDeploymentResp.ID)
+	require.Error(t, err)
+
+	_, err = steveclient.SteveType(serviceType).ByID(postServiceResp.ID)
+	require.Error(t, err)
+
+	logrus.Infof("Realigning the config with the restored cluster...")
+
+	_, err = operations.ReplaceValue([]string{"terratest", "kubernetesVersion"}, expectedState.kubernetesVersion, configMap[0])
+	require.NoError(t, err)
+
+	setEtcdValue(t, configMap, expectedState.etcdConfig, isRKE1)
+
+	_, _, err = framework.ConfigTF(client, testUser, testPassword, "", configMap, newFile, rootBody, file, false, false, false, nil)
+	require.NoError(t, err)
+
+	terraform.Apply(t, terraformOptions)
+	provisioning.CheckDrift(t, terraformOptions, configMap)
+
+	logrus.Infof("Deleting created workloads...")
+	err = steveclient.SteveType(stevetypes.Deployment).Delete(deploymentResp)
+	require.NoError(t, err)
+
+	err = steveclient.SteveType(stevetypes.Service).Delete(serviceResp)
+	require.NoError(t, err)
+}
+
+// snapshotRKE1Prov takes an etcd backup of the RKE1 cluster with Terraform and creates a deployment and service in the cluster.
+// It returns the ID of the etcd backup.
+func snapshotRKE1Prov(t *testing.T, client *rancher.Client, terraformConfig *config.TerraformConfig, podTemplate corev1.PodTemplateSpec,
+	testUser, testPassword, clusterID string, terraformOptions *terraform.Options, configMap []map[string]any, newFile *hclwrite.File,
+	rootBody *hclwrite.Body, file *os.File) (string, *steveV1.SteveAPIObject, *steveV1.SteveAPIObject) {
+	_, _, terratestConfig := config.LoadTFPConfigs(configMap[0])
+	generation := terratestConfig.SnapshotInput.CreateSnapshotGeneration + 1
+
+	_, err := operations.ReplaceValue([]string{"terratest", "snapshotInput", "createSnapshot"}, true, configMap[0])
+	require.NoError(t, err)
+
+	_, err = operations.ReplaceValue([]string{"terratest", "snapshotInput", "createSnapshotGeneration"}, generation, configMap[0])
+	require.NoError(t, err)
+
+	logrus.Infof("Taking etcd backup with generation %d...", generation)
+
+	_, _, err = framework.ConfigTF(client, testUser, testPassword, "", configMap, newFile, rootBody, file, false, false, false, nil)
+	require.NoError(t, err)
+
+	terraform.Apply(t, terraformOptions)
+	provisioning.CheckDrift(t, terraformOptions, configMap)
+
+	backup, err := snapshot.WaitForRKE1Backup(client, clusterID, rke1.ETCDBackupName(terraformConfig, generation))
+	require.NoError(t, err)
+
+	postWorkloadName := namegen.AppendRandomString(postWorkload)
+	postDeploymentResp, postServiceResp := createWorkloads(t, client, clusterID, podTemplate, postWorkloadName, isCattleLabeled, DeploymentSteveType)
+
+	return backup.ID, postDeploymentResp, postServiceResp
+}
+
+// getConfiguredEtcdValue returns the etcd config value that is changed by the restore matrix: the snapshot retention of
+// RKE2/K3s clusters or the backup interval hours of RKE1 clusters.
+func getConfiguredEtcdValue(t *testing.T, terraformConfig *config.TerraformConfig, isRKE1 bool) int64 {
+	if isRKE1 {
+		require.NotNil(t, terraformConfig.ETCDRKE1, "terraform.etcdRKE1 must be set")
+		require.NotNil(t, terraformConfig.ETCDRKE1.BackupConfig, "terraform.etcdRKE1.backupConfig must be set")
+
+		return terraformConfig.ETCDRKE1.BackupConfig.IntervalHours
+	}
+
+	require.NotNil(t, terraformConfig.ETCD, "terraform.etcd must be set")
+
+	return int64(terraformConfig.ETCD.SnapshotRetention)
+}
+
+// setEtcdValue sets the etcd config value that is changed by the restore matrix in the config.
+func setEtcdValue(t *testing.T, configMap []map[string]any, value int64, isRKE1 bool) {
+	_, terraformConfig, _ := config.LoadTFPConfigs(configMap[0])
+
+	var err error
+	if isRKE1 {
+		etcdRKE1 := terraformConfig.ETCDRKE1
+		etcdRKE1.BackupConfig.IntervalHours = value
+
+		_, err = operations.ReplaceValue([]string{"terraform", "etcdRKE1"}, etcdRKE1, configMap[0])
+	} else {
+		etcd := terraformConfig.ETCD
+		etcd.SnapshotRetention = int(value)
+
+		_, err = operations.ReplaceValue([]string{"terraform", "etcd"}, etcd, configMap[0])
+	}
+
+	require.NoError(t, err)
+}
+
+// verifyClusterState verifies that the Kubernetes version and the etcd config value of the cluster match the expected state
+// and that the nodes run the expected Kubernetes version.
+func verifyClusterState(t *testing.T, client *rancher.Client, terraformConfig *config.TerraformConfig, clusterID string,
+	expectedState clusterState, isRKE1 bool) {
+	var kubernetesVersion string
+	var etcdValue int64
+
+	if isRKE1 {
+		cluster, err := client.Management.Cluster.ByID(clusterID)
+		require.NoError(t, err)
+		require.NotNil(t, cluster.RancherKubernetesEngineConfig)
+
+		kubernetesVersion = cluster.RancherKubernetesEngineConfig.Version
+
+		etcdService := cluster.RancherKubernetesEngineConfig.Services.Etcd
+		if etcdService != nil && etcdService.BackupConfig != nil {
+			etcdValue = etcdService.BackupConfig.IntervalHours
+		}
+	} else {
+		clusterObject, _, err := clusters.GetProvisioningClusterByName(client, terraformConfig.ResourcePrefix, namespace)
+		require.NoError(t, err)
+		require.NotNil(t, clusterObject.Spec.RKEConfig)
+
+		kubernetesVersion = clusterObject.Spec.KubernetesVersion
+
+		if clusterObject.Spec.RKEConfig.ETCD != nil {
+			etcdValue = int64(clusterObject.Spec.RKEConfig.ETCD.SnapshotRetention)
+		}
+	}
+
+	logrus.Infof("Cluster Kubernetes version is %s, expected %s", kubernetesVersion, expectedState.kubernetesVersion)
+	assert.Equal(t, expectedState.kubernetesVersion, kubernetesVersion)
+	assert.Equal(t, expectedState.etcdConfig, etcdValue)
+
+	cluster, err := client.Management.Cluster.ByID(clusterID)
+	require.NoError(t, err)
+	require.NotNil(t, cluster.Version)
+
+	assert.True(t, strings.HasPrefix(expectedState.kubernetesVersion, cluster.Version.GitVersion),
+		"nodes run Kubernetes %s, expected %s", cluster.Version.GitVersion, expectedState.kubernetesVersion)
+}
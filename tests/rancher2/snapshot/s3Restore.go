@@ -0,0 +1,91 @@
+package snapshot
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/rancher/shepherd/clients/rancher"
+	"github.com/rancher/shepherd/extensions/clusters"
+	"github.com/rancher/shepherd/extensions/workloads"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/defaults/stevetypes"
+	"github.com/rancher/tfp-automation/tests/extensions/provisioning"
+	"github.com/rancher/tfp-automation/tests/extensions/snapshot"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// snapshotRestoreS3 takes a snapshot of the cluster that is uploaded to S3 and verifies that the snapshot is present in the
+// bucket. The snapshots stored locally on the nodes are then deleted, and the cluster is restored from the S3 snapshot,
+// verifying that the workload created after the snapshot is no longer present in the cluster.
+func snapshotRestoreS3(t *testing.T, client *rancher.Client, terraformConfig *config.TerraformConfig, testUser, testPassword string,
+	terraformOptions *terraform.Options, configMap []map[string]any, newFile *hclwrite.File, rootBody *hclwrite.Body, file *os.File) {
+	initialWorkloadName := namegen.AppendRandomString(initialWorkload)
+
+	clusterID, err := clusters.GetClusterIDByName(client, terraformConfig.ResourcePrefix)
+	require.NoError(t, err)
+
+	steveclient, err := client.Steve.ProxyDownstream(clusterID)
+	require.NoError(t, err)
+
+	containerTemplate := workloads.NewContainer(containerName, containerImage, corev1.PullAlways, []corev1.VolumeMount{}, []corev1.EnvFromSource{}, nil, nil, nil)
+	podTemplate := workloads.NewPodTemplate([]corev1.Container{containerTemplate}, []corev1.Volume{}, []corev1.LocalObjectReference{}, nil, nil)
+
+	deploymentResp, serviceResp := createWorkloads(t, client, clusterID, podTemplate, initialWorkloadName, isCattleLabeled, DeploymentSteveType)
+
+	existingS3Snapshots, _, err := snapshot.GetClusterSnapshots(client, terraformConfig.ResourcePrefix)
+	require.NoError(t, err)
+
+	var existingS3SnapshotNames []string
+	for _, existingS3Snapshot := range existingS3Snapshots {
+		existingS3SnapshotNames = append(existingS3SnapshotNames, existingS3Snapshot.ObjectMeta.Name)
+	}
+
+	_, postDeploymentResp, postServiceResp, err := snapshotV2Prov(t, client, terraformConfig, podTemplate, testUser, testPassword, clusterID, terraformOptions, configMap, newFile, rootBody, file)
+	require.NoError(t, err)
+
+	s3Snapshot, err := snapshot.WaitForNewS3Snapshot(client, terraformConfig.ResourcePrefix, existingS3SnapshotNames)
+	require.NoError(t, err)
+
+	logrus.Infof("Verifying snapshot %s is present in the S3 bucket...", s3Snapshot.SnapshotFile.Name)
+	objects, err := snapshot.ListS3SnapshotObjects(terraformConfig)
+	require.NoError(t, err)
+
+	var found bool
+	for _, object := range objects {
+		if strings.Contains(object, s3Snapshot.SnapshotFile.Name) {
+			found = true
+			break
+		}
+	}
+
+	require.True(t, found, "snapshot %s was not found in the S3 bucket", s3Snapshot.SnapshotFile.Name)
+
+	logrus.Infof("Deleting local snapshots...")
+	err = snapshot.DeleteLocalSnapshots(client, terraformConfig.ResourcePrefix)
+	require.NoError(t, err)
+
+	restoreV2Prov(t, client, terraformConfig, s3Snapshot.Name, testUser, testPassword, clusterID, terraformOptions, configMap, newFile, rootBody, file)
+	provisioning.CheckDrift(t, terraformOptions, configMap)
+
+	_, err = steveclient.SteveType(DeploymentSteveType).ByID(deploymentResp.ID)
+	require.NoError(t, err)
+
+	_, err = steveclient.SteveType(DeploymentSteveType).ByID(postDeploymentResp.ID)
+	require.Error(t, err)
+
+	_, err = steveclient.SteveType(serviceType).ByID(postServiceResp.ID)
+	require.Error(t, err)
+
+	logrus.Infof("Deleting created workloads...")
+	err = steveclient.SteveType(stevetypes.Deployment).Delete(deploymentResp)
+	require.NoError(t, err)
+
+	err = steveclient.SteveType(stevetypes.Service).Delete(serviceResp)
+	require.NoError(t, err)
+}
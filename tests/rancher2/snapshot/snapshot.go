@@ -22,6 +22,8 @@ import (
 	"github.com/rancher/tfp-automation/config"
 	"github.com/rancher/tfp-automation/defaults/stevetypes"
 	framework "github.com/rancher/tfp-automation/framework/set"
+	"github.com/rancher/tfp-automation/tests/extensions/provisioning"
+	"github.com/rancher/tfp-automation/tests/extensions/snapshot"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -53,10 +55,11 @@ const (
 	serviceType         = "service"
 )
 
-// snapshotRestore creates workloads, takes a snapshot of the cluster, restores the cluster and verifies the workloads created after
-// a snapshot no longer are present in the cluster
+// snapshotRestore creates workloads and takes the given number of snapshots of the cluster, creating a workload after each
+// snapshot. The cluster is then restored to each snapshot from the latest to the earliest, verifying that only the workloads
+// created after the restored snapshot are no longer present in the cluster.
 func snapshotRestore(t *testing.T, client *rancher.Client, terraformConfig *config.TerraformConfig, testUser, testPassword string, terraformOptions *terraform.Options,
-	configMap []map[string]any, newFile *hclwrite.File, rootBody *hclwrite.Body, file *os.File) {
+	configMap []map[string]any, newFile *hclwrite.File, rootBody *hclwrite.Body, file *os.File, snapshotCount int) {
 	initialWorkloadName := namegen.AppendRandomString(initialWorkload)
 
 	clusterID, err := clusters.GetClusterIDByName(client, terraformConfig.ResourcePrefix)
@@ -70,16 +73,35 @@ func snapshotRestore(t *testing.T, client *rancher.Client, terraformConfig *conf
 
 	deploymentResp, serviceResp := createWorkloads(t, client, clusterID, podTemplate, initialWorkloadName, isCattleLabeled, DeploymentSteveType)
 
-	snapshotName, postDeploymentResp, postServiceResp, err := snapshotV2Prov(t, client, terraformConfig, podTemplate, testUser, testPassword, clusterID, terraformOptions, configMap, newFile, rootBody, file)
-	require.NoError(t, err)
+	var snapshotNames []string
+	var postDeployments, postServices []*steveV1.SteveAPIObject
 
-	restoreV2Prov(t, client, terraformConfig, snapshotName, testUser, testPassword, clusterID, terraformOptions, configMap, newFile, rootBody, file)
+	for i := 0; i < snapshotCount; i++ {
+		snapshotName, postDeploymentResp, postServiceResp, err := snapshotV2Prov(t, client, terraformConfig, podTemplate, testUser, testPassword, clusterID, terraformOptions, configMap, newFile, rootBody, file)
+		require.NoError(t, err)
 
-	_, err = steveclient.SteveType(DeploymentSteveType).ByID(postDeploymentResp.ID)
-	require.Error(t, err)
+		snapshotNames = append(snapshotNames, snapshotName)
+		postDeployments = append(postDeployments, postDeploymentResp)
+		postServices = append(postServices, postServiceResp)
+	}
 
-	_, err = steveclient.SteveType(serviceType).ByID(postServiceResp.ID)
-	require.Error(t, err)
+	for i := snapshotCount - 1; i >= 0; i-- {
+		restoreV2Prov(t, client, terraformConfig, snapshotNames[i], testUser, testPassword, clusterID, terraformOptions, configMap, newFile, rootBody, file)
+		provisioning.CheckDrift(t, terraformOptions, configMap)
+
+		for j := range snapshotNames {
+			_, deploymentErr := steveclient.SteveType(DeploymentSteveType).ByID(postDeployments[j].ID)
+			_, serviceErr := steveclient.SteveType(serviceType).ByID(postServices[j].ID)
+
+			if j >= i {
+				require.Error(t, deploymentErr)
+				require.Error(t, serviceErr)
+			} else {
+				require.NoError(t, deploymentErr)
+				require.NoError(t, serviceErr)
+			}
+		}
+	}
 
 	logrus.Infof("Deleting created workloads...")
 	err = steveclient.SteveType(stevetypes.Deployment).Delete(deploymentResp)
@@ -89,17 +111,30 @@ func snapshotRestore(t *testing.T, client *rancher.Client, terraformConfig *conf
 	require.NoError(t, err)
 }
 
-// snapshotV2Prov takes a snapshot of the cluster and creates a deployment and service in the cluster.
+// snapshotV2Prov takes a snapshot of the cluster and creates a deployment and service in the cluster. The create snapshot
+// generation is incremented from the cluster's latest generation, so that repeated snapshots are always taken.
 func snapshotV2Prov(t *testing.T, client *rancher.Client, terraformConfig *config.TerraformConfig, podTemplate corev1.PodTemplateSpec,
 	testUser, testPassword, clusterID string, terraformOptions *terraform.Options, configMap []map[string]any, newFile *hclwrite.File,
 	rootBody *hclwrite.Body, file *os.File) (string, *steveV1.SteveAPIObject, *steveV1.SteveAPIObject, error) {
-	_, err := operations.ReplaceValue([]string{"terratest", "snapshotInput", "createSnapshot"}, true, configMap[0])
+	existingSnapshots, err := getSnapshots(client, terraformConfig.ResourcePrefix)
+	require.NoError(t, err)
+
+	_, err = operations.ReplaceValue([]string{"terratest", "snapshotInput", "createSnapshot"}, true, configMap[0])
+	require.NoError(t, err)
+
+	_, err = operations.ReplaceValue([]string{"terratest", "snapshotInput", "restoreSnapshot"}, false, configMap[0])
 	require.NoError(t, err)
 
+	generation, err := snapshot.SetNextCreateSnapshotGeneration(client, terraformConfig.ResourcePrefix, configMap)
+	require.NoError(t, err)
+
+	logrus.Infof("Taking snapshot with generation %d...", generation)
+
 	_, _, err = framework.ConfigTF(client, testUser, testPassword, "", configMap, newFile, rootBody, file, false, false, false, nil)
 	require.NoError(t, err)
 
 	terraform.Apply(t, terraformOptions)
+	provisioning.CheckDrift(t, terraformOptions, configMap)
 
 	err = clusters.WaitClusterToBeUpgraded(client, clusterID)
 	require.NoError(t, err)
@@ -107,16 +142,18 @@ func snapshotV2Prov(t *testing.T, client *rancher.Client, terraformConfig *confi
 	podErrors := pods.StatusPods(client, clusterID)
 	assert.Empty(t, podErrors)
 
+	snapshotName, err := waitForNewSnapshot(client, terraformConfig.ResourcePrefix, existingSnapshots)
+	require.NoError(t, err)
+
 	postWorkloadName := namegen.AppendRandomString(postWorkload)
 	postDeploymentResp, postServiceResp := createWorkloads(t, client, clusterID, podTemplate, postWorkloadName, isCattleLabeled, DeploymentSteveType)
 
-	snapshotID, err := getSnapshots(client, terraformConfig.ResourcePrefix)
-	require.NoError(t, err)
-
-	return snapshotID[0].Name, postDeploymentResp, postServiceResp, err
+	return snapshotName, postDeploymentResp, postServiceResp, err
 }
 
-// restoreV2Prov restores the cluster to the previous state after a snapshot is taken.
+// restoreV2Prov restores the cluster to the given snapshot. The restore snapshot generation is incremented from the cluster's
+// latest generation, so that repeated restores are always performed. Drift is not checked, since restoring the Kubernetes
+// version or the cluster config changes the cluster spec outside of Terraform.
 func restoreV2Prov(t *testing.T, client *rancher.Client, terraformConfig *config.TerraformConfig, snapshotName, testUser, testPassword string,
 	clusterID string, terraformOptions *terraform.Options, configMap []map[string]any, newFile *hclwrite.File, rootBody *hclwrite.Body,
 	file *os.File) {
@@ -129,6 +166,11 @@ func restoreV2Prov(t *testing.T, client *rancher.Client, terraformConfig *config
 	_, err = operations.ReplaceValue([]string{"terratest", "snapshotInput", "snapshotName"}, snapshotName, configMap[0])
 	require.NoError(t, err)
 
+	generation, err := snapshot.SetNextRestoreSnapshotGeneration(client, terraformConfig.ResourcePrefix, configMap)
+	require.NoError(t, err)
+
+	logrus.Infof("Restoring snapshot %s with generation %d...", snapshotName, generation)
+
 	_, _, err = framework.ConfigTF(nil, testUser, testPassword, "", configMap, newFile, rootBody, file, false, false, false, nil)
 	require.NoError(t, err)
 
@@ -146,6 +188,34 @@ func restoreV2Prov(t *testing.T, client *rancher.Client, terraformConfig *config
 	assert.Empty(t, podErrors)
 }
 
+// waitForNewSnapshot waits for a snapshot of the cluster that is not in the given list of existing snapshots and returns its name.
+func waitForNewSnapshot(client *rancher.Client, clusterName string, existingSnapshots []steveV1.SteveAPIObject) (string, error) {
+	existing := map[string]bool{}
+	for _, existingSnapshot := range existingSnapshots {
+		existing[existingSnapshot.ObjectMeta.Name] = true
+	}
+
+	var snapshotName string
+
+	err := kwait.PollUntilContextTimeout(context.TODO(), timeouts.FiveSecondTimeout, timeouts.FiveMinuteTimeout, true, func(ctx context.Context) (done bool, err error) {
+		snapshots, err := getSnapshots(client, clusterName)
+		if err != nil {
+			return false, nil
+		}
+
+		for _, snapshotObject := range snapshots {
+			if !existing[snapshotObject.ObjectMeta.Name] {
+				snapshotName = snapshotObject.ObjectMeta.Name
+				return true, nil
+			}
+		}
+
+		return false, nil
+	})
+
+	return snapshotName, err
+}
+
 // getSnapshots retrieves all snapshots for a given cluster.
 func getSnapshots(client *rancher.Client, clusterName string) ([]steveV1.SteveAPIObject, error) {
 	localclusterID, err := clusters.GetClusterIDByName(client, localCluster)
@@ -11,6 +11,7 @@ import (
 	"github.com/rancher/shepherd/pkg/config/operations"
 	"github.com/rancher/shepherd/pkg/session"
 	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/defaults/clustertypes"
 	"github.com/rancher/tfp-automation/defaults/configs"
 	"github.com/rancher/tfp-automation/defaults/keypath"
 	"github.com/rancher/tfp-automation/framework"
@@ -47,6 +48,9 @@ func (s *SnapshotRestoreTestSuite) SetupSuite() {
 	s.cattleConfig, err = config.LoadProvisioningDefaults(s.cattleConfig, "")
 	require.NoError(s.T(), err)
 
+	s.cattleConfig, err = config.LoadPackageDefaults(s.cattleConfig, "")
+	require.NoError(s.T(), err)
+
 	configMap, err := provisioning.UniquifyTerraform([]map[string]any{s.cattleConfig})
 	require.NoError(s.T(), err)
 
@@ -68,11 +72,13 @@ func (s *SnapshotRestoreTestSuite) TestTfpSnapshotRestore() {
 	}
 
 	tests := []struct {
-		name         string
-		nodeRoles    []config.Nodepool
-		etcdSnapshot config.TerratestConfig
+		name          string
+		nodeRoles     []config.Nodepool
+		etcdSnapshot  config.TerratestConfig
+		snapshotCount int
 	}{
-		{"Restore etcd only", nodeRolesDedicated, snapshotRestoreNone},
+		{"Restore etcd only", nodeRolesDedicated, snapshotRestoreNone, 1},
+		{"Restore etcd only from multiple snapshots", nodeRolesDedicated, snapshotRestoreNone, 3},
 	}
 
 	configMap := []map[string]any{s.cattleConfig}
@@ -85,7 +91,9 @@ func (s *SnapshotRestoreTestSuite) TestTfpSnapshotRestore() {
 		_, err := operations.ReplaceValue([]string{"terratest", "nodepools"}, tt.nodeRoles, configMap[0])
 		require.NoError(s.T(), err)
 
-		_, err = operations.ReplaceValue([]string{"terratest", "snapshotInput", "snapshotRestore"}, tt.etcdSnapshot.SnapshotInput.SnapshotRestore, configMap[0])
+		_, err = operations.ReplaceValue([]string{"terratest", "snapshotInput"}, map[string]any{
+			"snapshotRestore": tt.etcdSnapshot.SnapshotInput.SnapshotRestore,
+		}, configMap[0])
 		require.NoError(s.T(), err)
 
 		provisioning.GetK8sVersion(s.T(), s.client, s.terratestConfig, s.terraformConfig, configs.DefaultK8sVersion, configMap)
@@ -108,7 +116,180 @@ func (s *SnapshotRestoreTestSuite) TestTfpSnapshotRestore() {
 			clusterIDs, _ := provisioning.Provision(s.T(), s.client, rancher, terraform, testUser, testPassword, s.terraformOptions, configMap, newFile, rootBody, file, false, false, false, nil)
 			provisioning.VerifyClustersState(s.T(), adminClient, clusterIDs)
 
-			snapshotRestore(s.T(), s.client, terraform, testUser, testPassword, s.terraformOptions, configMap, newFile, rootBody, file)
+			snapshotRestore(s.T(), s.client, terraform, testUser, testPassword, s.terraformOptions, configMap, newFile, rootBody, file, tt.snapshotCount)
+			provisioning.VerifyClustersState(s.T(), adminClient, clusterIDs)
+		})
+	}
+
+	if s.terratestConfig.LocalQaseReporting {
+		qase.ReportTest()
+	}
+}
+
+func (s *SnapshotRestoreTestSuite) TestTfpSnapshotRestoreMatrix() {
+	nodeRolesDedicated := []config.Nodepool{config.EtcdNodePool, config.ControlPlaneNodePool, config.WorkerNodePool}
+
+	tests := []struct {
+		name        string
+		nodeRoles   []config.Nodepool
+		restoreMode string
+	}{
+		{"Upgrade and restore etcd only", nodeRolesDedicated, "none"},
+		{"Upgrade and restore etcd and Kubernetes version", nodeRolesDedicated, "kubernetesVersion"},
+		{"Upgrade and restore etcd, Kubernetes version and cluster config", nodeRolesDedicated, "all"},
+	}
+
+	configMap := []map[string]any{s.cattleConfig}
+	testUser, testPassword := configs.CreateTestCredentials()
+
+	for _, tt := range tests {
+		newFile, rootBody, file := rancher2.InitializeMainTF()
+		defer file.Close()
+
+		_, err := operations.ReplaceValue([]string{"terratest", "nodepools"}, tt.nodeRoles, configMap[0])
+		require.NoError(s.T(), err)
+
+		_, err = operations.ReplaceValue([]string{"terratest", "snapshotInput"}, map[string]any{"snapshotRestore": tt.restoreMode}, configMap[0])
+		require.NoError(s.T(), err)
+
+		_, err = operations.ReplaceValue([]string{"terratest", "kubernetesVersion"}, s.terratestConfig.KubernetesVersion, configMap[0])
+		require.NoError(s.T(), err)
+
+		_, err = operations.ReplaceValue([]string{"terraform", "etcd"}, s.terraformConfig.ETCD, configMap[0])
+		require.NoError(s.T(), err)
+
+		_, err = operations.ReplaceValue([]string{"terraform", "etcdRKE1"}, s.terraformConfig.ETCDRKE1, configMap[0])
+		require.NoError(s.T(), err)
+
+		provisioning.GetK8sVersion(s.T(), s.client, s.terratestConfig, s.terraformConfig, configs.SecondHighestVersion, configMap)
+
+		rancher, terraform, terratest := config.LoadTFPConfigs(configMap[0])
+
+		tt.name = tt.name + " Module: " + s.terraformConfig.Module + " Kubernetes version: " + terratest.KubernetesVersion
+
+		s.Run(tt.name, func() {
+			keyPath := rancher2.SetKeyPath(keypath.RancherKeyPath, "")
+			defer cleanup.Cleanup(s.T(), s.terraformOptions, keyPath)
+
+			adminClient, err := provisioning.FetchAdminClient(s.T(), s.client)
+			require.NoError(s.T(), err)
+
+			clusterIDs, _ := provisioning.Provision(s.T(), s.client, rancher, terraform, testUser, testPassword, s.terraformOptions, configMap, newFile, rootBody, file, false, false, false, nil)
+			provisioning.VerifyClustersState(s.T(), adminClient, clusterIDs)
+
+			snapshotRestoreMatrix(s.T(), s.client, rancher, testUser, testPassword, s.terraformOptions, configMap, newFile, rootBody, file, tt.restoreMode)
+			provisioning.VerifyClustersState(s.T(), adminClient, clusterIDs)
+		})
+	}
+
+	if s.terratestConfig.LocalQaseReporting {
+		qase.ReportTest()
+	}
+}
+
+func (s *SnapshotRestoreTestSuite) TestTfpSnapshotRestoreS3() {
+	nodeRolesDedicated := []config.Nodepool{config.EtcdNodePool, config.ControlPlaneNodePool, config.WorkerNodePool}
+
+	tests := []struct {
+		name      string
+		nodeRoles []config.Nodepool
+	}{
+		{"Restore etcd only from S3", nodeRolesDedicated},
+	}
+
+	if s.terraformConfig.S3Credentials == nil || s.terraformConfig.ETCD == nil || s.terraformConfig.ETCD.S3 == nil {
+		s.T().Skip("S3 snapshots require s3Credentials and etcd.s3 to be configured")
+	}
+
+	if strings.Contains(s.terraformConfig.Module, clustertypes.RKE1) {
+		s.T().Skip("RKE1 is not supported")
+	}
+
+	configMap := []map[string]any{s.cattleConfig}
+	testUser, testPassword := configs.CreateTestCredentials()
+
+	for _, tt := range tests {
+		newFile, rootBody, file := rancher2.InitializeMainTF()
+		defer file.Close()
+
+		_, err := operations.ReplaceValue([]string{"terratest", "nodepools"}, tt.nodeRoles, configMap[0])
+		require.NoError(s.T(), err)
+
+		_, err = operations.ReplaceValue([]string{"terratest", "snapshotInput"}, map[string]any{"snapshotRestore": "none"}, configMap[0])
+		require.NoError(s.T(), err)
+
+		provisioning.GetK8sVersion(s.T(), s.client, s.terratestConfig, s.terraformConfig, configs.DefaultK8sVersion, configMap)
+
+		rancher, terraform, terratest := config.LoadTFPConfigs(configMap[0])
+
+		tt.name = tt.name + " Module: " + s.terraformConfig.Module + " Kubernetes version: " + terratest.KubernetesVersion
+
+		s.Run(tt.name, func() {
+			keyPath := rancher2.SetKeyPath(keypath.RancherKeyPath, "")
+			defer cleanup.Cleanup(s.T(), s.terraformOptions, keyPath)
+
+			adminClient, err := provisioning.FetchAdminClient(s.T(), s.client)
+			require.NoError(s.T(), err)
+
+			clusterIDs, _ := provisioning.Provision(s.T(), s.client, rancher, terraform, testUser, testPassword, s.terraformOptions, configMap, newFile, rootBody, file, false, false, false, nil)
+			provisioning.VerifyClustersState(s.T(), adminClient, clusterIDs)
+
+			snapshotRestoreS3(s.T(), s.client, terraform, testUser, testPassword, s.terraformOptions, configMap, newFile, rootBody, file)
+			provisioning.VerifyClustersState(s.T(), adminClient, clusterIDs)
+		})
+	}
+
+	if s.terratestConfig.LocalQaseReporting {
+		qase.ReportTest()
+	}
+}
+
+func (s *SnapshotRestoreTestSuite) TestTfpSnapshotDisasterRecovery() {
+	nodeRolesDedicated := []config.Nodepool{config.EtcdNodePool, config.ControlPlaneNodePool, config.WorkerNodePool}
+
+	tests := []struct {
+		name      string
+		nodeRoles []config.Nodepool
+		lostRole  string
+	}{
+		{"Recover from losing the etcd majority", nodeRolesDedicated, etcdRole},
+		{"Recover from losing all control plane nodes", nodeRolesDedicated, controlPlaneRole},
+	}
+
+	if strings.Contains(s.terraformConfig.Module, clustertypes.RKE1) {
+		s.T().Skip("RKE1 is not supported")
+	}
+
+	configMap := []map[string]any{s.cattleConfig}
+	testUser, testPassword := configs.CreateTestCredentials()
+
+	for _, tt := range tests {
+		newFile, rootBody, file := rancher2.InitializeMainTF()
+		defer file.Close()
+
+		_, err := operations.ReplaceValue([]string{"terratest", "nodepools"}, tt.nodeRoles, configMap[0])
+		require.NoError(s.T(), err)
+
+		_, err = operations.ReplaceValue([]string{"terratest", "snapshotInput"}, map[string]any{"snapshotRestore": "none"}, configMap[0])
+		require.NoError(s.T(), err)
+
+		provisioning.GetK8sVersion(s.T(), s.client, s.terratestConfig, s.terraformConfig, configs.DefaultK8sVersion, configMap)
+
+		rancher, terraform, terratest := config.LoadTFPConfigs(configMap[0])
+
+		tt.name = tt.name + " Module: " + s.terraformConfig.Module + " Kubernetes version: " + terratest.KubernetesVersion
+
+		s.Run(tt.name, func() {
+			keyPath := rancher2.SetKeyPath(keypath.RancherKeyPath, "")
+			defer cleanup.Cleanup(s.T(), s.terraformOptions, keyPath)
+
+			adminClient, err := provisioning.FetchAdminClient(s.T(), s.client)
+			require.NoError(s.T(), err)
+
+			clusterIDs, _ := provisioning.Provision(s.T(), s.client, rancher, terraform, testUser, testPassword, s.terraformOptions, configMap, newFile, rootBody, file, false, false, false, nil)
+			provisioning.VerifyClustersState(s.T(), adminClient, clusterIDs)
+
+			disasterRecovery(s.T(), s.client, rancher, terraform, terratest, testUser, testPassword, s.terraformOptions, configMap, newFile, rootBody, file, tt.lostRole)
 			provisioning.VerifyClustersState(s.T(), adminClient, clusterIDs)
 		})
 	}
@@ -2,14 +2,17 @@ package upgrading
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/gruntwork-io/terratest/modules/terraform"
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
 	"github.com/rancher/shepherd/clients/rancher"
 	shepherdConfig "github.com/rancher/shepherd/pkg/config"
 	"github.com/rancher/shepherd/pkg/config/operations"
 	"github.com/rancher/shepherd/pkg/session"
 	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/defaults/clustertypes"
 	"github.com/rancher/tfp-automation/defaults/configs"
 	"github.com/rancher/tfp-automation/defaults/keypath"
 	"github.com/rancher/tfp-automation/framework"
@@ -143,6 +146,137 @@ func (k *KubernetesUpgradeTestSuite) TestTfpKubernetesUpgradeDynamicInput() {
 	}
 }
 
+func (k *KubernetesUpgradeTestSuite) TestTfpKubernetesUpgradeStrategy() {
+	nodeRolesDedicated := []config.Nodepool{config.EtcdNodePool, config.ControlPlaneNodePool, config.WorkerNodePool}
+
+	ignoreDaemonSets := true
+	drainOptions := rkev1.DrainOptions{
+		Enabled:                         true,
+		DeleteEmptyDirData:              true,
+		IgnoreDaemonSets:                &ignoreDaemonSets,
+		GracePeriod:                     30,
+		Timeout:                         300,
+		SkipWaitForDeleteTimeoutSeconds: 60,
+	}
+
+	tests := []struct {
+		name            string
+		nodeRoles       []config.Nodepool
+		upgradeStrategy rkev1.ClusterUpgradeStrategy
+	}{
+		{"Drain one node at a time", nodeRolesDedicated, rkev1.ClusterUpgradeStrategy{
+			ControlPlaneConcurrency:  "1",
+			ControlPlaneDrainOptions: drainOptions,
+			WorkerConcurrency:        "1",
+			WorkerDrainOptions:       drainOptions,
+		}},
+		{"Drain workers by percentage", nodeRolesDedicated, rkev1.ClusterUpgradeStrategy{
+			ControlPlaneConcurrency: "1",
+			WorkerConcurrency:       "50%",
+			WorkerDrainOptions:      drainOptions,
+		}},
+	}
+
+	if strings.Contains(k.terraformConfig.Module, clustertypes.RKE1) {
+		k.T().Skip("The upgrade strategy is only supported for RKE2/K3s clusters")
+	}
+
+	configMap := []map[string]any{k.cattleConfig}
+	testUser, testPassword := configs.CreateTestCredentials()
+
+	for _, tt := range tests {
+		newFile, rootBody, file := rancher2.InitializeMainTF()
+		defer file.Close()
+
+		_, err := operations.ReplaceValue([]string{"terratest", "nodepools"}, tt.nodeRoles, configMap[0])
+		require.NoError(k.T(), err)
+
+		_, err = operations.ReplaceValue([]string{"terratest", "kubernetesVersion"}, k.terratestConfig.KubernetesVersion, configMap[0])
+		require.NoError(k.T(), err)
+
+		_, err = operations.ReplaceValue([]string{"terraform", "upgradeStrategy"}, tt.upgradeStrategy, configMap[0])
+		require.NoError(k.T(), err)
+
+		provisioning.GetK8sVersion(k.T(), k.client, k.terratestConfig, k.terraformConfig, configs.SecondHighestVersion, configMap)
+
+		rancher, terraform, terratest := config.LoadTFPConfigs(configMap[0])
+
+		tt.name = tt.name + " Module: " + k.terraformConfig.Module + " Kubernetes version: " + terratest.KubernetesVersion
+
+		k.Run((tt.name), func() {
+			keyPath := rancher2.SetKeyPath(keypath.RancherKeyPath, "")
+			defer cleanup.Cleanup(k.T(), k.terraformOptions, keyPath)
+
+			adminClient, err := provisioning.FetchAdminClient(k.T(), k.client)
+			require.NoError(k.T(), err)
+
+			clusterIDs, _ := provisioning.Provision(k.T(), k.client, rancher, terraform, testUser, testPassword, k.terraformOptions, configMap, newFile, rootBody, file, false, false, false, nil)
+			provisioning.VerifyClustersState(k.T(), adminClient, clusterIDs)
+
+			provisioning.KubernetesUpgrade(k.T(), k.client, rancher, terraform, terratest, testUser, testPassword, k.terraformOptions, configMap, newFile, rootBody, file, false)
+			provisioning.VerifyClustersState(k.T(), adminClient, clusterIDs)
+
+			_, terraform, terratest = config.LoadTFPConfigs(configMap[0])
+			provisioning.VerifyNodesKubernetesVersion(k.T(), k.client, clusterIDs[0], terratest.KubernetesVersion, terraform.Module)
+		})
+	}
+
+	if k.terratestConfig.LocalQaseReporting {
+		qase.ReportTest()
+	}
+}
+
+func (k *KubernetesUpgradeTestSuite) TestTfpChainedKubernetesUpgrade() {
+	nodeRolesDedicated := []config.Nodepool{config.EtcdNodePool, config.ControlPlaneNodePool, config.WorkerNodePool}
+
+	tests := []struct {
+		name      string
+		nodeRoles []config.Nodepool
+	}{
+		{"8 nodes - 3 etcd, 2 cp, 3 worker " + config.StandardClientName.String(), nodeRolesDedicated},
+	}
+
+	if len(k.terratestConfig.KubernetesUpgradePath) == 0 {
+		k.T().Skip("kubernetesUpgradePath must be set for the chained Kubernetes upgrade")
+	}
+
+	configMap := []map[string]any{k.cattleConfig}
+	testUser, testPassword := configs.CreateTestCredentials()
+
+	for _, tt := range tests {
+		newFile, rootBody, file := rancher2.InitializeMainTF()
+		defer file.Close()
+
+		_, err := operations.ReplaceValue([]string{"terratest", "nodepools"}, tt.nodeRoles, configMap[0])
+		require.NoError(k.T(), err)
+
+		provisioning.GetK8sVersion(k.T(), k.client, k.terratestConfig, k.terraformConfig, configs.SecondHighestVersion, configMap)
+
+		rancher, terraform, terratest := config.LoadTFPConfigs(configMap[0])
+
+		tt.name = tt.name + " Module: " + k.terraformConfig.Module + " Kubernetes upgrade path: " + terratest.KubernetesVersion + " -> " +
+			strings.Join(terratest.KubernetesUpgradePath, " -> ")
+
+		k.Run((tt.name), func() {
+			keyPath := rancher2.SetKeyPath(keypath.RancherKeyPath, "")
+			defer cleanup.Cleanup(k.T(), k.terraformOptions, keyPath)
+
+			adminClient, err := provisioning.FetchAdminClient(k.T(), k.client)
+			require.NoError(k.T(), err)
+
+			clusterIDs, _ := provisioning.Provision(k.T(), k.client, rancher, terraform, testUser, testPassword, k.terraformOptions, configMap, newFile, rootBody, file, false, false, false, nil)
+			provisioning.VerifyClustersState(k.T(), adminClient, clusterIDs)
+
+			provisioning.ChainedKubernetesUpgrade(k.T(), k.client, testUser, testPassword, k.terraformOptions, configMap, newFile, rootBody, file, false)
+			provisioning.VerifyClustersState(k.T(), adminClient, clusterIDs)
+		})
+	}
+
+	if k.terratestConfig.LocalQaseReporting {
+		qase.ReportTest()
+	}
+}
+
 func TestTfpKubernetesUpgradeTestSuite(t *testing.T) {
 	suite.Run(t, new(KubernetesUpgradeTestSuite))
 }
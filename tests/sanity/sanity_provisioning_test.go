@@ -37,6 +37,7 @@ type TfpSanityProvisioningTestSuite struct {
 	terratestConfig            *config.TerratestConfig
 	standaloneTerraformOptions *terraform.Options
 	terraformOptions           *terraform.Options
+	minIOEndpoint              string
 }
 
 func (s *TfpSanityProvisioningTestSuite) TearDownSuite() {
@@ -54,6 +55,10 @@ func (s *TfpSanityProvisioningTestSuite) SetupSuite() {
 
 	_, err := resources.CreateMainTF(s.T(), s.standaloneTerraformOptions, keyPath, s.terraformConfig, s.terratestConfig)
 	require.NoError(s.T(), err)
+
+	if s.terraformConfig.S3Credentials != nil {
+		s.minIOEndpoint = s.terraformConfig.S3Credentials.Endpoint
+	}
 }
 
 func (s *TfpSanityProvisioningTestSuite) TfpSetupSuite() map[string]any {
@@ -65,6 +70,13 @@ func (s *TfpSanityProvisioningTestSuite) TfpSetupSuite() map[string]any {
 	require.NoError(s.T(), err)
 
 	s.cattleConfig = configMap[0]
+
+	// The MinIO endpoint is only known once the standalone MinIO server is created, so it is not in the config file.
+	if s.minIOEndpoint != "" {
+		_, err = operations.ReplaceValue([]string{"terraform", "s3Credentials", "endpoint"}, s.minIOEndpoint, s.cattleConfig)
+		require.NoError(s.T(), err)
+	}
+
 	s.rancherConfig, s.terraformConfig, s.terratestConfig = config.LoadTFPConfigs(s.cattleConfig)
 
 	adminUser := &management.User{
@@ -0,0 +1,236 @@
+package sanity
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/rancher/rancher/tests/v2/actions/pipeline"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/extensions/token"
+	"github.com/rancher/shepherd/extensions/users"
+	shepherdConfig "github.com/rancher/shepherd/pkg/config"
+	"github.com/rancher/shepherd/pkg/config/operations"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+	"github.com/rancher/shepherd/pkg/session"
+	"github.com/rancher/tfp-automation/config"
+	"github.com/rancher/tfp-automation/defaults/configs"
+	"github.com/rancher/tfp-automation/defaults/keypath"
+	"github.com/rancher/tfp-automation/defaults/modules"
+	"github.com/rancher/tfp-automation/framework"
+	"github.com/rancher/tfp-automation/framework/cleanup"
+	"github.com/rancher/tfp-automation/framework/set/resources/backup"
+	"github.com/rancher/tfp-automation/framework/set/resources/rancher2"
+	resources "github.com/rancher/tfp-automation/framework/set/resources/sanity"
+	qase "github.com/rancher/tfp-automation/pipeline/qase/results"
+	"github.com/rancher/tfp-automation/tests/extensions/provisioning"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	backupPrefix    = "tfp-backup"
+	rollbackSetting = "ui-issues"
+	rollbackValue   = "https://github.com/rancher/tfp-automation/issues"
+)
+
+type TfpSanityRollbackRancherTestSuite struct {
+	suite.Suite
+	client                     *rancher.Client
+	session                    *session.Session
+	cattleConfig               map[string]any
+	rancherConfig              *rancher.Config
+	terraformConfig            *config.TerraformConfig
+	terratestConfig            *config.TerratestConfig
+	standaloneTerraformOptions *terraform.Options
+	upgradeTerraformOptions    *terraform.Options
+	rollbackTerraformOptions   *terraform.Options
+	terraformOptions           *terraform.Options
+	serverNodeOne              string
+	minIOEndpoint              string
+}
+
+func (s *TfpSanityRollbackRancherTestSuite) TearDownSuite() {
+	keyPath := rancher2.SetKeyPath(keypath.SanityKeyPath, s.terraformConfig.Provider)
+	cleanup.Cleanup(s.T(), s.standaloneTerraformOptions, keyPath)
+
+	keyPath = rancher2.SetKeyPath(keypath.UpgradeKeyPath, s.terraformConfig.Provider)
+	cleanup.Cleanup(s.T(), s.upgradeTerraformOptions, keyPath)
+
+	keyPath = rancher2.SetKeyPath(keypath.RollbackKeyPath, s.terraformConfig.Provider)
+	cleanup.Cleanup(s.T(), s.rollbackTerraformOptions, keyPath)
+}
+
+func (s *TfpSanityRollbackRancherTestSuite) SetupSuite() {
+	s.cattleConfig = shepherdConfig.LoadConfigFromFile(os.Getenv(shepherdConfig.ConfigEnvironmentKey))
+	s.rancherConfig, s.terraformConfig, s.terratestConfig = config.LoadTFPConfigs(s.cattleConfig)
+
+	keyPath := rancher2.SetKeyPath(keypath.SanityKeyPath, s.terraformConfig.Provider)
+	standaloneTerraformOptions := framework.Setup(s.T(), s.terraformConfig, s.terratestConfig, keyPath)
+	s.standaloneTerraformOptions = standaloneTerraformOptions
+
+	serverNodeOne, err := resources.CreateMainTF(s.T(), s.standaloneTerraformOptions, keyPath, s.terraformConfig, s.terratestConfig)
+	require.NoError(s.T(), err)
+
+	s.serverNodeOne = serverNodeOne
+
+	if s.terraformConfig.S3Credentials != nil {
+		s.minIOEndpoint = s.terraformConfig.S3Credentials.Endpoint
+	}
+
+	keyPath = rancher2.SetKeyPath(keypath.UpgradeKeyPath, s.terraformConfig.Provider)
+	upgradeTerraformOptions := framework.Setup(s.T(), s.terraformConfig, s.terratestConfig, keyPath)
+
+	s.upgradeTerraformOptions = upgradeTerraformOptions
+
+	keyPath = rancher2.SetKeyPath(keypath.RollbackKeyPath, s.terraformConfig.Provider)
+	rollbackTerraformOptions := framework.Setup(s.T(), s.terraformConfig, s.terratestConfig, keyPath)
+
+	s.rollbackTerraformOptions = rollbackTerraformOptions
+}
+
+func (s *TfpSanityRollbackRancherTestSuite) TfpSetupSuite() map[string]any {
+	testSession := session.NewSession()
+	s.session = testSession
+
+	s.cattleConfig = shepherdConfig.LoadConfigFromFile(os.Getenv(shepherdConfig.ConfigEnvironmentKey))
+	configMap, err := provisioning.UniquifyTerraform([]map[string]any{s.cattleConfig})
+	require.NoError(s.T(), err)
+
+	s.cattleConfig = configMap[0]
+
+	// The MinIO endpoint is only known once the standalone MinIO server is created, so it is not in the config file.
+	if s.minIOEndpoint != "" {
+		_, err = operations.ReplaceValue([]string{"terraform", "s3Credentials", "endpoint"}, s.minIOEndpoint, s.cattleConfig)
+		require.NoError(s.T(), err)
+	}
+
+	s.rancherConfig, s.terraformConfig, s.terratestConfig = config.LoadTFPConfigs(s.cattleConfig)
+
+	adminUser := &management.User{
+		Username: "admin",
+		Password: s.rancherConfig.AdminPassword,
+	}
+
+	userToken, err := token.GenerateUserToken(adminUser, s.rancherConfig.Host)
+	require.NoError(s.T(), err)
+
+	s.rancherConfig.AdminToken = userToken.Token
+
+	client, err := rancher.NewClient(s.rancherConfig.AdminToken, testSession)
+	require.NoError(s.T(), err)
+
+	s.client = client
+	s.client.RancherConfig.AdminToken = s.rancherConfig.AdminToken
+	s.client.RancherConfig.AdminPassword = s.rancherConfig.AdminPassword
+	s.client.RancherConfig.Host = s.rancherConfig.Host
+
+	operations.ReplaceValue([]string{"rancher", "adminToken"}, s.rancherConfig.AdminToken, configMap[0])
+	operations.ReplaceValue([]string{"rancher", "adminPassword"}, s.rancherConfig.AdminPassword, configMap[0])
+	operations.ReplaceValue([]string{"rancher", "host"}, s.rancherConfig.Host, configMap[0])
+
+	err = pipeline.PostRancherInstall(s.client, s.client.RancherConfig.AdminPassword)
+	require.NoError(s.T(), err)
+
+	keyPath := rancher2.SetKeyPath(keypath.RancherKeyPath, "")
+	terraformOptions := framework.Setup(s.T(), s.terraformConfig, s.terratestConfig, keyPath)
+	s.terraformOptions = terraformOptions
+
+	return s.cattleConfig
+}
+
+func (s *TfpSanityRollbackRancherTestSuite) TestTfpRollbackRancher() {
+	clusterIDs := s.provisionAndVerifyCluster("Pre-Backup Sanity ")
+
+	logrus.Infof("Creating a user and updating setting %s before the backup...", rollbackSetting)
+	user, err := users.CreateUserWithRole(s.client, users.UserConfig())
+	require.NoError(s.T(), err)
+
+	setting, err := s.client.Management.Setting.ByID(rollbackSetting)
+	require.NoError(s.T(), err)
+
+	_, err = s.client.Management.Setting.Update(setting, map[string]any{"value": rollbackValue})
+	require.NoError(s.T(), err)
+
+	backupName := namegen.AppendRandomString(backupPrefix)
+	rollbackKeyPath := rancher2.SetKeyPath(keypath.RollbackKeyPath, s.terraformConfig.Provider)
+
+	err = backup.CreateBackup(s.T(), s.rollbackTerraformOptions, rollbackKeyPath, s.terraformConfig, s.serverNodeOne, backupName)
+	require.NoError(s.T(), err)
+
+	s.terraformConfig.Standalone.UpgradeRancher = true
+
+	upgradeKeyPath := rancher2.SetKeyPath(keypath.UpgradeKeyPath, s.terraformConfig.Provider)
+	provisioning.RancherUpgrade(s.T(), s.client, s.terraformConfig, s.terratestConfig, clusterIDs, s.terraformOptions, s.upgradeTerraformOptions, upgradeKeyPath, s.serverNodeOne, "", "", "")
+
+	provisioning.RancherRollback(s.T(), s.client, s.terraformConfig, clusterIDs, s.terraformOptions, s.rollbackTerraformOptions, rollbackKeyPath, s.serverNodeOne, backupName)
+
+	logrus.Infof("Verifying the user and setting %s created before the backup...", rollbackSetting)
+	restoredUser, err := s.client.Management.User.ByID(user.ID)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), user.Username, restoredUser.Username)
+
+	restoredSetting, err := s.client.Management.Setting.ByID(rollbackSetting)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), rollbackValue, restoredSetting.Value)
+
+	keyPath := rancher2.SetKeyPath(keypath.RancherKeyPath, "")
+	cleanup.Cleanup(s.T(), s.terraformOptions, keyPath)
+
+	if s.terratestConfig.LocalQaseReporting {
+		qase.ReportTest()
+	}
+}
+
+func (s *TfpSanityRollbackRancherTestSuite) provisionAndVerifyCluster(name string) []string {
+	var clusterIDs []string
+
+	nodeRolesDedicated := []config.Nodepool{config.EtcdNodePool, config.ControlPlaneNodePool, config.WorkerNodePool}
+
+	tests := []struct {
+		name      string
+		nodeRoles []config.Nodepool
+		module    string
+	}{
+		{"RKE2", nodeRolesDedicated, modules.EC2RKE2},
+		{"K3S", nodeRolesDedicated, modules.EC2K3s},
+	}
+
+	newFile, rootBody, file := rancher2.InitializeMainTF()
+	defer file.Close()
+
+	customClusterNames := []string{}
+	testUser, testPassword := configs.CreateTestCredentials()
+
+	for _, tt := range tests {
+		cattleConfig := s.TfpSetupSuite()
+		configMap := []map[string]any{cattleConfig}
+
+		_, err := operations.ReplaceValue([]string{"terratest", "nodepools"}, tt.nodeRoles, configMap[0])
+		require.NoError(s.T(), err)
+
+		_, err = operations.ReplaceValue([]string{"terraform", "module"}, tt.module, configMap[0])
+		require.NoError(s.T(), err)
+
+		provisioning.GetK8sVersion(s.T(), s.client, s.terratestConfig, s.terraformConfig, configs.DefaultK8sVersion, configMap)
+
+		rancher, terraform, terratest := config.LoadTFPConfigs(configMap[0])
+
+		tt.name = name + tt.name + " Kubernetes version: " + terratest.KubernetesVersion
+
+		s.Run((tt.name), func() {
+			var provisionedClusterIDs []string
+			provisionedClusterIDs, customClusterNames = provisioning.Provision(s.T(), s.client, rancher, terraform, testUser, testPassword, s.terraformOptions, configMap, newFile, rootBody, file, false, true, true, customClusterNames)
+			provisioning.VerifyClustersState(s.T(), s.client, provisionedClusterIDs)
+			clusterIDs = append(clusterIDs, provisionedClusterIDs...)
+		})
+	}
+
+	return clusterIDs
+}
+
+func TestTfpSanityRollbackRancherTestSuite(t *testing.T) {
+	suite.Run(t, new(TfpSanityRollbackRancherTestSuite))
+}
@@ -2,6 +2,7 @@ package sanity
 
 import (
 	"os"
+	"slices"
 	"strings"
 	"testing"
 
@@ -21,7 +22,6 @@ import (
 	"github.com/rancher/tfp-automation/framework/cleanup"
 	"github.com/rancher/tfp-automation/framework/set/resources/rancher2"
 	resources "github.com/rancher/tfp-automation/framework/set/resources/sanity"
-	"github.com/rancher/tfp-automation/framework/set/resources/upgrade"
 	qase "github.com/rancher/tfp-automation/pipeline/qase/results"
 	"github.com/rancher/tfp-automation/tests/extensions/provisioning"
 	"github.com/stretchr/testify/require"
@@ -40,6 +40,7 @@ type TfpSanityUpgradeRancherTestSuite struct {
 	upgradeTerraformOptions    *terraform.Options
 	terraformOptions           *terraform.Options
 	serverNodeOne              string
+	minIOEndpoint              string
 }
 
 func (s *TfpSanityUpgradeRancherTestSuite) TearDownSuite() {
@@ -63,6 +64,10 @@ func (s *TfpSanityUpgradeRancherTestSuite) SetupSuite() {
 
 	s.serverNodeOne = serverNodeOne
 
+	if s.terraformConfig.S3Credentials != nil {
+		s.minIOEndpoint = s.terraformConfig.S3Credentials.Endpoint
+	}
+
 	keyPath = rancher2.SetKeyPath(keypath.UpgradeKeyPath, s.terraformConfig.Provider)
 	upgradeTerraformOptions := framework.Setup(s.T(), s.terraformConfig, s.terratestConfig, keyPath)
 
@@ -78,6 +83,13 @@ func (s *TfpSanityUpgradeRancherTestSuite) TfpSetupSuite() map[string]any {
 	require.NoError(s.T(), err)
 
 	s.cattleConfig = configMap[0]
+
+	// The MinIO endpoint is only known once the standalone MinIO server is created, so it is not in the config file.
+	if s.minIOEndpoint != "" {
+		_, err = operations.ReplaceValue([]string{"terraform", "s3Credentials", "endpoint"}, s.minIOEndpoint, s.cattleConfig)
+		require.NoError(s.T(), err)
+	}
+
 	s.rancherConfig, s.terraformConfig, s.terratestConfig = config.LoadTFPConfigs(s.cattleConfig)
 
 	adminUser := &management.User{
@@ -113,26 +125,23 @@ func (s *TfpSanityUpgradeRancherTestSuite) TfpSetupSuite() map[string]any {
 }
 
 func (s *TfpSanityUpgradeRancherTestSuite) TestTfpUpgradeRancher() {
-	var clusterIDs []string
-
-	s.provisionAndVerifyCluster("Pre-Upgrade Sanity ", clusterIDs, false)
+	clusterIDs := s.provisionAndVerifyCluster("Pre-Upgrade Sanity ", false)
 
 	s.terraformConfig.Standalone.UpgradeRancher = true
 
 	keyPath := rancher2.SetKeyPath(keypath.UpgradeKeyPath, s.terraformConfig.Provider)
-	err := upgrade.CreateMainTF(s.T(), s.upgradeTerraformOptions, keyPath, s.terraformConfig, s.terratestConfig, s.serverNodeOne, "", "", "")
-	require.NoError(s.T(), err)
+	provisioning.RancherUpgrade(s.T(), s.client, s.terraformConfig, s.terratestConfig, clusterIDs, s.terraformOptions, s.upgradeTerraformOptions, keyPath, s.serverNodeOne, "", "", "")
 
-	provisioning.VerifyClustersState(s.T(), s.client, clusterIDs)
-
-	s.provisionAndVerifyCluster("Post-Upgrade Sanity ", clusterIDs, true)
+	s.provisionAndVerifyCluster("Post-Upgrade Sanity ", true)
 
 	if s.terratestConfig.LocalQaseReporting {
 		qase.ReportTest()
 	}
 }
 
-func (s *TfpSanityUpgradeRancherTestSuite) provisionAndVerifyCluster(name string, clusterIDs []string, deleteClusters bool) []string {
+func (s *TfpSanityUpgradeRancherTestSuite) provisionAndVerifyCluster(name string, deleteClusters bool) []string {
+	var clusterIDs []string
+
 	nodeRolesDedicated := []config.Nodepool{config.EtcdNodePool, config.ControlPlaneNodePool, config.WorkerNodePool}
 
 	tests := []struct {
@@ -169,12 +178,20 @@ func (s *TfpSanityUpgradeRancherTestSuite) provisionAndVerifyCluster(name string
 		tt.name = name + tt.name + " Kubernetes version: " + terratest.KubernetesVersion
 
 		s.Run((tt.name), func() {
-			clusterIDs, customClusterNames = provisioning.Provision(s.T(), s.client, rancher, terraform, testUser, testPassword, s.terraformOptions, configMap, newFile, rootBody, file, false, true, true, customClusterNames)
-			provisioning.VerifyClustersState(s.T(), s.client, clusterIDs)
+			var provisionedClusterIDs []string
+			provisionedClusterIDs, customClusterNames = provisioning.Provision(s.T(), s.client, rancher, terraform, testUser, testPassword, s.terraformOptions, configMap, newFile, rootBody, file, false, true, true, customClusterNames)
+			provisioning.VerifyClustersState(s.T(), s.client, provisionedClusterIDs)
+			clusterIDs = append(clusterIDs, provisionedClusterIDs...)
 
 			if strings.Contains(terraform.Module, modules.CustomEC2RKE2Windows) {
-				clusterIDs, _ = provisioning.Provision(s.T(), s.client, rancher, terraform, testUser, testPassword, s.terraformOptions, configMap, newFile, rootBody, file, true, true, true, customClusterNames)
-				provisioning.VerifyClustersState(s.T(), s.client, clusterIDs)
+				provisionedClusterIDs, _ = provisioning.Provision(s.T(), s.client, rancher, terraform, testUser, testPassword, s.terraformOptions, configMap, newFile, rootBody, file, true, true, true, customClusterNames)
+				provisioning.VerifyClustersState(s.T(), s.client, provisionedClusterIDs)
+
+				for _, clusterID := range provisionedClusterIDs {
+					if !slices.Contains(clusterIDs, clusterID) {
+						clusterIDs = append(clusterIDs, clusterID)
+					}
+				}
 			}
 		})
 	}